@@ -3,94 +3,68 @@ package factory
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
-	"github.com/finos-labs/ccc-cfi-compliance/testing/api/logging"
-	objstorage "github.com/finos-labs/ccc-cfi-compliance/testing/api/object-storage"
-	vpcapi "github.com/finos-labs/ccc-cfi-compliance/testing/api/vpc"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
 // AWSFactory implements the Factory interface for AWS
 type AWSFactory struct {
-	ctx          context.Context
-	instance     types.InstanceConfig
-	iamService   generic.Service
-	serviceCache map[string]generic.Service
-	serviceMu    sync.Mutex
+	ctx      *generic.SwitchableContext // Passed to every service this factory creates; see SetContext
+	instance types.InstanceConfig
+	shared   *generic.SharedState // State shared by this factory's services, e.g. the IAM service
+	services *serviceCache
 }
 
 // NewAWSFactory creates a new AWS factory
 func NewAWSFactory(instance types.InstanceConfig) *AWSFactory {
-	ctx := context.Background()
-
-	// Create IAM service once and cache it
-	iamService, err := iam.NewAWSIAMService(ctx, instance)
-	if err != nil {
-		fmt.Printf("⚠️  Warning: Failed to create AWS IAM service: %v\n", err)
-	}
-
 	return &AWSFactory{
-		ctx:          ctx,
-		instance:     instance,
-		iamService:   iamService,
-		serviceCache: make(map[string]generic.Service),
+		ctx:      generic.NewSwitchableContext(context.Background()),
+		instance: instance,
+		shared:   generic.NewSharedState(),
+		services: newServiceCache(),
 	}
 }
 
+// env returns the environment passed to the constructors of this factory's services
+func (f *AWSFactory) env(identity *iam.Identity) generic.ServiceEnv {
+	return generic.NewServiceEnv(f.ctx, &f.instance, identity, f.shared)
+}
+
 // GetServiceAPI returns a generic service API client for the given service type
 func (f *AWSFactory) GetServiceAPI(serviceID string) (generic.Service, error) {
-	key := serviceID
-	f.serviceMu.Lock()
-	if cached, ok := f.serviceCache[key]; ok {
-		f.serviceMu.Unlock()
-		return cached, nil
+	def, impl, err := generic.ServiceImplementation(serviceID, string(ProviderAWS))
+	if err != nil {
+		return nil, err
 	}
-	f.serviceMu.Unlock()
-
-	var service generic.Service
-	var err error
-	switch serviceID {
-	case "iam":
-		if f.iamService == nil {
-			return nil, fmt.Errorf("AWS IAM service not initialized")
-		}
-		service = f.iamService
 
-	case "object-storage":
-		service, err = objstorage.NewAWSS3Service(f.ctx, f.instance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create AWS service '%s': %w", serviceID, err)
-		}
-		if err := service.ElevateAccessForInspection(); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to elevate access for %s: %v\n", serviceID, err)
-		}
+	// Uncached clients (e.g. VPC) are fresh for each caller
+	if impl.Uncached {
+		return constructService(ProviderAWS, serviceID, impl.New, f.env(nil))
+	}
 
-	case "logging":
-		service, err = logging.NewAWSLoggingService(f.ctx, &f.instance)
+	return f.services.getOrCreate(serviceID, func() (generic.Service, error) {
+		service, err := constructService(ProviderAWS, serviceID, impl.New, f.env(nil))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create AWS logging service: %w", err)
+			return nil, err
 		}
-
-	case "vpc":
-		service, err := vpcapi.NewAWSVPCService(f.ctx, f.instance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create AWS service '%s': %w", serviceID, err)
+		if def.ElevateAccess {
+			if err := service.ElevateAccessForInspection(f.ctx); err != nil {
+				fmt.Printf("⚠️  Warning: Failed to elevate access for %s: %v\n", serviceID, err)
+			}
 		}
 		return service, nil
+	})
+}
 
-	default:
-		return nil, fmt.Errorf("unsupported service type for AWS: %s", serviceID)
-	}
-
-	if service != nil {
-		f.serviceMu.Lock()
-		f.serviceCache[key] = service
-		f.serviceMu.Unlock()
+// GetReadOnlyServiceAPI returns a service API client without elevating access or caching it
+func (f *AWSFactory) GetReadOnlyServiceAPI(serviceID string) (generic.Service, error) {
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderAWS))
+	if err != nil {
+		return nil, err
 	}
-	return service, nil
+	return constructService(ProviderAWS, serviceID, impl.New, f.env(nil))
 }
 
 // GetServiceAPIWithIdentity returns a service API client authenticated as the given identity
@@ -99,49 +73,28 @@ func (f *AWSFactory) GetServiceAPIWithIdentity(serviceID string, identity *iam.I
 		return nil, fmt.Errorf("identity is not for AWS provider: %s", identity.Provider)
 	}
 
-	key := serviceID + ":" + identity.UserName
-	f.serviceMu.Lock()
-	if cached, ok := f.serviceCache[key]; ok {
-		f.serviceMu.Unlock()
-		return cached, nil
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderAWS))
+	if err != nil {
+		return nil, err
+	}
+	if impl.NewWithIdentity == nil {
+		// Services without per-identity clients run with the runner's ambient credentials
+		return nil, fmt.Errorf("%s with identity not yet implemented for AWS", serviceID)
 	}
-	f.serviceMu.Unlock()
-
-	var service generic.Service
-	var err error
-	switch serviceID {
-	case "iam":
-		service = f.iamService
 
-	case "object-storage":
-		service, err = objstorage.NewAWSS3ServiceWithCredentials(f.ctx, f.instance, identity)
+	key := serviceID + ":" + identity.UserName
+	return f.services.getOrCreate(key, func() (generic.Service, error) {
+		service, err := constructService(ProviderAWS, serviceID, impl.NewWithIdentity, f.env(identity))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create AWS service '%s' with identity: %w", serviceID, err)
-		}
-		if err := service.ElevateAccessForInspection(); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to elevate access for %s: %v\n", serviceID, err)
+			return nil, err
 		}
 		if testAccess {
-			if err = waitForUserProvisioning(service); err != nil {
+			if err = waitForUserProvisioning(f.ctx, service); err != nil {
 				return nil, fmt.Errorf("user provisioning validation failed: %w", err)
 			}
 		}
-
-	case "vpc":
-		// VPC tests currently run with the runner's ambient credentials.
-		// Per-identity clients can be added later when needed for negative testing.
-		return nil, fmt.Errorf("vpc with identity not yet implemented for AWS")
-
-	default:
-		return nil, fmt.Errorf("unsupported service type for AWS: %s", serviceID)
-	}
-
-	if service != nil {
-		f.serviceMu.Lock()
-		f.serviceCache[key] = service
-		f.serviceMu.Unlock()
-	}
-	return service, nil
+		return service, nil
+	})
 }
 
 // GetProvider returns the cloud provider
@@ -149,24 +102,22 @@ func (f *AWSFactory) GetProvider() CloudProvider {
 	return ProviderAWS
 }
 
-// TearDown calls TearDown on all cached services
+// TearDown calls TearDown and then ResetAccess on all cached services
 func (f *AWSFactory) TearDown() error {
-	f.serviceMu.Lock()
-	services := make([]generic.Service, 0, len(f.serviceCache))
-	for _, svc := range f.serviceCache {
-		services = append(services, svc)
-	}
-	f.serviceMu.Unlock()
-
-	for _, svc := range services {
-		if err := svc.TearDown(); err != nil {
+	// Tear down before resetting access: removing test resources may need the elevated access
+	for _, svc := range f.services.all() {
+		if err := svc.TearDown(f.ctx); err != nil {
 			fmt.Printf("⚠️  TearDown failed: %v\n", err)
 		}
+		if err := svc.ResetAccess(f.ctx); err != nil {
+			fmt.Printf("⚠️  ResetAccess failed: %v\n", err)
+		}
 	}
 	return nil
 }
 
-// SetContext sets the context for this factory
+// SetContext sets the context used for this factory's own calls (elevation, TearDown) and
+// held by the clients of the services it has created
 func (f *AWSFactory) SetContext(ctx context.Context) {
-	f.ctx = ctx
+	f.ctx.Switch(ctx)
 }
@@ -1,7 +1,9 @@
 package factory
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
@@ -16,16 +18,33 @@ const (
 	ProviderAWS   CloudProvider = "aws"
 	ProviderAzure CloudProvider = "azure"
 	ProviderGCP   CloudProvider = "gcp"
+	ProviderLocal CloudProvider = "local"
 )
 
-// Cache for factories (one per provider)
-var factoryCache = make(map[CloudProvider]Factory)
+// factoryKey identifies a cached factory. Factories are cached per instance rather than
+// per provider so that two instances of the same provider (different accounts, projects
+// or regions) never share clients or IAM state.
+type factoryKey struct {
+	provider CloudProvider
+	instance string
+}
+
+// Cache for factories (one per instance). Guarded by factoryMu because scenarios
+// for different resources may run concurrently.
+var (
+	factoryCache = make(map[factoryKey]Factory)
+	factoryMu    sync.Mutex
+)
 
 // Factory creates cloud service API clients for different providers
 type Factory interface {
 	// GetServiceAPI returns a generic service API client for the given service ID
 	GetServiceAPI(serviceID string) (generic.Service, error)
 
+	// GetReadOnlyServiceAPI returns a service API client without elevating access or caching it,
+	// so nothing needs to be reset or torn down afterwards (used by --plan)
+	GetReadOnlyServiceAPI(serviceID string) (generic.Service, error)
+
 	// GetServiceAPIWithIdentity returns a service API client authenticated as the given identity
 	// If testAccess is true, validates that the identity's permissions have propagated before returning
 	GetServiceAPIWithIdentity(serviceID string, identity *iam.Identity, testAccess bool) (generic.Service, error)
@@ -33,22 +52,34 @@ type Factory interface {
 	// GetProvider returns the cloud provider this factory is configured for
 	GetProvider() CloudProvider
 
-	// TearDown calls TearDown on all cached services to remove test-created resources
+	// SetContext replaces the context used for the factory's own calls (access elevation,
+	// provisioning checks, TearDown) and held by the clients of its services, e.g. for token
+	// refresh. Switching to a fresh context after cancellation lets TearDown still clean up.
+	SetContext(ctx context.Context)
+
+	// TearDown calls TearDown on all cached services to remove test-created resources,
+	// then ResetAccess to undo any ElevateAccessForInspection
 	TearDown() error
 }
 
-// NewFactory creates a new factory for the specified cloud provider.
+// NewFactory returns the factory for the given instance, creating it on first use.
 // instance carries all environment configuration, including service-specific properties.
-// Factories are cached per provider to ensure IAM service caching works across calls.
+// Factories are cached per instance so IAM service caching works across calls; call
+// TearDownFactory when the instance is finished with to clean up and evict it.
 func NewFactory(provider CloudProvider, instance types.InstanceConfig) (Factory, error) {
+	factoryMu.Lock()
+	defer factoryMu.Unlock()
+
+	key := factoryKey{provider: provider, instance: instance.ID}
+
 	// Check cache first
-	if cachedFactory, exists := factoryCache[provider]; exists {
-		fmt.Printf("♻️  Using cached factory for provider: %s\n", provider)
+	if cachedFactory, exists := factoryCache[key]; exists {
+		fmt.Printf("♻️  Using cached factory for instance: %s (%s)\n", instance.ID, provider)
 		return cachedFactory, nil
 	}
 
 	// Create new factory
-	fmt.Printf("🏭 Creating new factory for provider: %s\n", provider)
+	fmt.Printf("🏭 Creating new factory for instance: %s (%s)\n", instance.ID, provider)
 	var factory Factory
 	switch provider {
 	case ProviderAWS:
@@ -57,23 +88,126 @@ func NewFactory(provider CloudProvider, instance types.InstanceConfig) (Factory,
 		factory = NewAzureFactory(instance)
 	case ProviderGCP:
 		factory = NewGCPFactory(instance)
+	case ProviderLocal:
+		factory = NewLocalFactory(instance)
 	default:
 		return nil, fmt.Errorf("unsupported cloud provider: %s", provider)
 	}
 
 	// Cache the factory
-	factoryCache[provider] = factory
+	factoryCache[key] = factory
 	return factory, nil
 }
 
+// TearDownFactory tears down the cached factory for the given instance and evicts it,
+// so the next NewFactory call for that instance starts with fresh clients.
+// It is a no-op if no factory is cached for the instance.
+func TearDownFactory(provider CloudProvider, instance types.InstanceConfig) error {
+	factory := EvictFactory(provider, instance)
+	if factory == nil {
+		return nil
+	}
+	return factory.TearDown()
+}
+
+// EvictFactory removes the cached factory for the given instance without tearing it down
+// and returns it (nil if none was cached).
+func EvictFactory(provider CloudProvider, instance types.InstanceConfig) Factory {
+	factoryMu.Lock()
+	defer factoryMu.Unlock()
+
+	key := factoryKey{provider: provider, instance: instance.ID}
+	factory, exists := factoryCache[key]
+	if !exists {
+		return nil
+	}
+	delete(factoryCache, key)
+	return factory
+}
+
+// serviceCache holds a factory's services by key. Services are built outside the cache lock,
+// since construction, elevation and provisioning checks can take a while, but under a lock
+// per key, so concurrent first calls build (and elevate access for) one service and share it.
+type serviceCache struct {
+	mu       sync.Mutex
+	services map[string]generic.Service
+	building map[string]*sync.Mutex
+}
+
+// newServiceCache creates an empty service cache
+func newServiceCache() *serviceCache {
+	return &serviceCache{
+		services: make(map[string]generic.Service),
+		building: make(map[string]*sync.Mutex),
+	}
+}
+
+// getOrCreate returns the service cached under key, calling create to build it on first use.
+// Errors are not cached, so a later call tries again.
+func (c *serviceCache) getOrCreate(key string, create func() (generic.Service, error)) (generic.Service, error) {
+	c.mu.Lock()
+	if cached, ok := c.services[key]; ok {
+		c.mu.Unlock()
+		return cached, nil
+	}
+	keyMu, ok := c.building[key]
+	if !ok {
+		keyMu = &sync.Mutex{}
+		c.building[key] = keyMu
+	}
+	c.mu.Unlock()
+
+	keyMu.Lock()
+	defer keyMu.Unlock()
+
+	// Another caller may have built the service while this one waited
+	c.mu.Lock()
+	cached, ok := c.services[key]
+	c.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	service, err := create()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.services[key] = service
+	c.mu.Unlock()
+	return service, nil
+}
+
+// all returns the cached services
+func (c *serviceCache) all() []generic.Service {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	services := make([]generic.Service, 0, len(c.services))
+	for _, svc := range c.services {
+		services = append(services, svc)
+	}
+	return services
+}
+
+// constructService calls a registered service constructor, adding the provider and service
+// type to its error
+func constructService(provider CloudProvider, serviceID string, construct generic.ServiceConstructor, env generic.ServiceEnv) (generic.Service, error) {
+	service, err := construct(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s service '%s': %w", provider, serviceID, err)
+	}
+	return service, nil
+}
+
 // waitForUserProvisioning validates that a user's permissions have propagated to the service
 // This is a shared helper used by all factories to handle IAM propagation delays
-func waitForUserProvisioning(service generic.Service) error {
+func waitForUserProvisioning(ctx context.Context, service generic.Service) error {
 	maxAttempts := 12 // 12 attempts * 5 seconds = 60 seconds max
 	fmt.Printf("   🔄 Validating user permissions have propagated to service...\n")
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		err := service.CheckUserProvisioned()
+		err := service.CheckUserProvisioned(ctx)
 		if err == nil {
 			fmt.Printf("   ✅ User permissions validated after %d attempt(s)\n", attempt)
 			return nil
@@ -3,85 +3,66 @@ package factory
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
-	"github.com/finos-labs/ccc-cfi-compliance/testing/api/logging"
-	objstorage "github.com/finos-labs/ccc-cfi-compliance/testing/api/object-storage"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
 // GCPFactory implements the Factory interface for GCP
 type GCPFactory struct {
-	ctx          context.Context
-	instance     types.InstanceConfig
-	iamService   generic.Service
-	serviceCache map[string]generic.Service
-	serviceMu    sync.Mutex
+	ctx      *generic.SwitchableContext // Passed to every service this factory creates; see SetContext
+	instance types.InstanceConfig
+	shared   *generic.SharedState // State shared by this factory's services, e.g. the IAM service
+	services *serviceCache
 }
 
 // NewGCPFactory creates a new GCP factory
 func NewGCPFactory(instance types.InstanceConfig) *GCPFactory {
-	ctx := context.Background()
-	cloudParams := instance.CloudParams()
-
-	// Create IAM service once and cache it
-	var iamService generic.Service
-	if cloudParams.GcpProjectId != "" {
-		var err error
-		iamService, err = iam.NewGCPIAMService(ctx, instance)
-		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to create GCP IAM service: %v\n", err)
-		}
-	}
-
 	return &GCPFactory{
-		ctx:          ctx,
-		instance:     instance,
-		iamService:   iamService,
-		serviceCache: make(map[string]generic.Service),
+		ctx:      generic.NewSwitchableContext(context.Background()),
+		instance: instance,
+		shared:   generic.NewSharedState(),
+		services: newServiceCache(),
 	}
 }
 
+// env returns the environment passed to the constructors of this factory's services
+func (f *GCPFactory) env(identity *iam.Identity) generic.ServiceEnv {
+	return generic.NewServiceEnv(f.ctx, &f.instance, identity, f.shared)
+}
+
 // GetServiceAPI returns a generic service API client for the given service type
 func (f *GCPFactory) GetServiceAPI(serviceID string) (generic.Service, error) {
-	key := serviceID
-	f.serviceMu.Lock()
-	if cached, ok := f.serviceCache[key]; ok {
-		f.serviceMu.Unlock()
-		return cached, nil
+	def, impl, err := generic.ServiceImplementation(serviceID, string(ProviderGCP))
+	if err != nil {
+		return nil, err
+	}
+	if impl.Uncached {
+		return constructService(ProviderGCP, serviceID, impl.New, f.env(nil))
 	}
-	f.serviceMu.Unlock()
-
-	var service generic.Service
-	var err error
-	switch serviceID {
-	case "iam":
-		service = f.iamService
 
-	case "object-storage":
-		service, err = objstorage.NewGCPStorageService(f.ctx, f.instance)
+	return f.services.getOrCreate(serviceID, func() (generic.Service, error) {
+		service, err := constructService(ProviderGCP, serviceID, impl.New, f.env(nil))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create GCP service '%s': %w", serviceID, err)
+			return nil, err
 		}
-
-	case "logging":
-		service, err = logging.NewGCPLoggingService(f.ctx, &f.instance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create GCP logging service: %w", err)
+		if def.ElevateAccess {
+			if err := service.ElevateAccessForInspection(f.ctx); err != nil {
+				fmt.Printf("⚠️  Warning: Failed to elevate access for %s: %v\n", serviceID, err)
+			}
 		}
+		return service, nil
+	})
+}
 
-	default:
-		return nil, fmt.Errorf("unsupported service type for GCP: %s", serviceID)
-	}
-
-	if service != nil {
-		f.serviceMu.Lock()
-		f.serviceCache[key] = service
-		f.serviceMu.Unlock()
+// GetReadOnlyServiceAPI returns a service API client without elevating access or caching it
+func (f *GCPFactory) GetReadOnlyServiceAPI(serviceID string) (generic.Service, error) {
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderGCP))
+	if err != nil {
+		return nil, err
 	}
-	return service, nil
+	return constructService(ProviderGCP, serviceID, impl.New, f.env(nil))
 }
 
 // GetServiceAPIWithIdentity returns a service API client authenticated as the given identity
@@ -90,41 +71,27 @@ func (f *GCPFactory) GetServiceAPIWithIdentity(serviceID string, identity *iam.I
 		return nil, fmt.Errorf("identity is not for GCP provider: %s", identity.Provider)
 	}
 
-	key := serviceID + ":" + identity.UserName
-	f.serviceMu.Lock()
-	if cached, ok := f.serviceCache[key]; ok {
-		f.serviceMu.Unlock()
-		return cached, nil
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderGCP))
+	if err != nil {
+		return nil, err
+	}
+	if impl.NewWithIdentity == nil {
+		return nil, fmt.Errorf("%s with identity not yet implemented for GCP", serviceID)
 	}
-	f.serviceMu.Unlock()
-
-	var service generic.Service
-	var err error
-	switch serviceID {
-	case "iam":
-		service = f.iamService
 
-	case "object-storage":
-		service, err = objstorage.NewGCPStorageServiceWithCredentials(f.ctx, f.instance, identity)
+	key := serviceID + ":" + identity.UserName
+	return f.services.getOrCreate(key, func() (generic.Service, error) {
+		service, err := constructService(ProviderGCP, serviceID, impl.NewWithIdentity, f.env(identity))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create GCS service with credentials: %w", err)
+			return nil, err
 		}
 		if testAccess {
-			if err := service.CheckUserProvisioned(); err != nil {
+			if err := service.CheckUserProvisioned(f.ctx); err != nil {
 				return nil, fmt.Errorf("credentials not ready: %w", err)
 			}
 		}
-
-	default:
-		return nil, fmt.Errorf("unsupported service type for GCP: %s", serviceID)
-	}
-
-	if service != nil {
-		f.serviceMu.Lock()
-		f.serviceCache[key] = service
-		f.serviceMu.Unlock()
-	}
-	return service, nil
+		return service, nil
+	})
 }
 
 // GetProvider returns the cloud provider
@@ -132,24 +99,22 @@ func (f *GCPFactory) GetProvider() CloudProvider {
 	return ProviderGCP
 }
 
-// TearDown calls TearDown on all cached services
+// TearDown calls TearDown and then ResetAccess on all cached services
 func (f *GCPFactory) TearDown() error {
-	f.serviceMu.Lock()
-	services := make([]generic.Service, 0, len(f.serviceCache))
-	for _, svc := range f.serviceCache {
-		services = append(services, svc)
-	}
-	f.serviceMu.Unlock()
-
-	for _, svc := range services {
-		if err := svc.TearDown(); err != nil {
+	// Tear down before resetting access: removing test resources may need the elevated access
+	for _, svc := range f.services.all() {
+		if err := svc.TearDown(f.ctx); err != nil {
 			fmt.Printf("⚠️  TearDown failed: %v\n", err)
 		}
+		if err := svc.ResetAccess(f.ctx); err != nil {
+			fmt.Printf("⚠️  ResetAccess failed: %v\n", err)
+		}
 	}
 	return nil
 }
 
-// SetContext sets the context for this factory
+// SetContext sets the context used for this factory's own calls (elevation, TearDown) and
+// held by the clients of the services it has created
 func (f *GCPFactory) SetContext(ctx context.Context) {
-	f.ctx = ctx
+	f.ctx.Switch(ctx)
 }
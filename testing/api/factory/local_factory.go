@@ -0,0 +1,132 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// LocalFactory implements the Factory interface for the offline "local" provider.
+// All services are in-memory fakes sharing one store, audit log and IAM service (held in
+// the factory's shared state), so grants, writes and log queries made through different
+// clients see each other.
+type LocalFactory struct {
+	ctx          context.Context
+	instance     types.InstanceConfig
+	shared       *generic.SharedState
+	serviceCache map[string]generic.Service
+	serviceMu    sync.Mutex
+}
+
+// NewLocalFactory creates a new local factory
+func NewLocalFactory(instance types.InstanceConfig) *LocalFactory {
+	return &LocalFactory{
+		ctx:          context.Background(),
+		instance:     instance,
+		shared:       generic.NewSharedState(),
+		serviceCache: make(map[string]generic.Service),
+	}
+}
+
+// env returns the environment passed to the constructors of this factory's services
+func (f *LocalFactory) env(identity *iam.Identity) generic.ServiceEnv {
+	return generic.NewServiceEnv(f.ctx, &f.instance, identity, f.shared)
+}
+
+// GetServiceAPI returns a generic service API client for the given service type
+func (f *LocalFactory) GetServiceAPI(serviceID string) (generic.Service, error) {
+	key := serviceID
+	f.serviceMu.Lock()
+	defer f.serviceMu.Unlock()
+
+	if cached, ok := f.serviceCache[key]; ok {
+		return cached, nil
+	}
+
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderLocal))
+	if err != nil {
+		return nil, err
+	}
+	service, err := constructService(ProviderLocal, serviceID, impl.New, f.env(nil))
+	if err != nil {
+		return nil, err
+	}
+	if !impl.Uncached {
+		f.serviceCache[key] = service
+	}
+	return service, nil
+}
+
+// GetReadOnlyServiceAPI returns a service API client without elevating access or caching it
+func (f *LocalFactory) GetReadOnlyServiceAPI(serviceID string) (generic.Service, error) {
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderLocal))
+	if err != nil {
+		return nil, err
+	}
+	return constructService(ProviderLocal, serviceID, impl.New, f.env(nil))
+}
+
+// GetServiceAPIWithIdentity returns a service API client authorized as the given identity.
+// Grants take effect immediately, so there is no propagation to wait for when testAccess is set.
+func (f *LocalFactory) GetServiceAPIWithIdentity(serviceID string, identity *iam.Identity, testAccess bool) (generic.Service, error) {
+	if identity.Provider != string(ProviderLocal) {
+		return nil, fmt.Errorf("identity is not for local provider: %s", identity.Provider)
+	}
+
+	key := serviceID + ":" + identity.UserName
+	f.serviceMu.Lock()
+	defer f.serviceMu.Unlock()
+
+	if cached, ok := f.serviceCache[key]; ok {
+		return cached, nil
+	}
+
+	_, impl, err := generic.ServiceImplementation(serviceID, string(ProviderLocal))
+	if err != nil {
+		return nil, err
+	}
+	if impl.NewWithIdentity == nil {
+		return nil, fmt.Errorf("%s with identity not yet implemented for local", serviceID)
+	}
+	service, err := constructService(ProviderLocal, serviceID, impl.NewWithIdentity, f.env(identity))
+	if err != nil {
+		return nil, err
+	}
+	f.serviceCache[key] = service
+	return service, nil
+}
+
+// GetProvider returns the cloud provider
+func (f *LocalFactory) GetProvider() CloudProvider {
+	return ProviderLocal
+}
+
+// TearDown calls TearDown and then ResetAccess on all cached services
+func (f *LocalFactory) TearDown() error {
+	f.serviceMu.Lock()
+	services := make([]generic.Service, 0, len(f.serviceCache))
+	for _, svc := range f.serviceCache {
+		services = append(services, svc)
+	}
+	f.serviceMu.Unlock()
+
+	// Tear down before resetting access: removing test resources may need the elevated access
+	for _, svc := range services {
+		if err := svc.TearDown(f.ctx); err != nil {
+			fmt.Printf("⚠️  TearDown failed: %v\n", err)
+		}
+		if err := svc.ResetAccess(f.ctx); err != nil {
+			fmt.Printf("⚠️  ResetAccess failed: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// SetContext sets the context passed to the local services
+func (f *LocalFactory) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
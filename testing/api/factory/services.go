@@ -0,0 +1,10 @@
+package factory
+
+// Each service package registers its service type, catalog types, tag filter and
+// per-provider constructors with generic.RegisterService when it is imported. To add a
+// service, add its package here; iam is imported by the factories themselves.
+import (
+	_ "github.com/finos-labs/ccc-cfi-compliance/testing/api/logging"
+	_ "github.com/finos-labs/ccc-cfi-compliance/testing/api/object-storage"
+	_ "github.com/finos-labs/ccc-cfi-compliance/testing/api/vpc"
+)
@@ -0,0 +1,43 @@
+package generic
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// AWSLoadOptions returns opts for config.LoadDefaultConfig with the endpoint-url and
+// insecure-skip-verify overrides from the instance's serviceType block appended.
+// When the HTTP recorder is on, the client records to or replays from the
+// "aws-<serviceType>" cassette, and replays use static placeholder credentials.
+// opts are returned unchanged when the service uses the default AWS endpoints.
+func AWSLoadOptions(instance types.InstanceConfig, serviceType string, opts ...func(*config.LoadOptions) error) []func(*config.LoadOptions) error {
+	endpoint := instance.EndpointConfig(serviceType)
+
+	if endpoint.EndpointURL != "" {
+		opts = append(opts, config.WithBaseEndpoint(endpoint.EndpointURL))
+	}
+	if recorder.Enabled() {
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		if endpoint.InsecureSkipVerify {
+			base.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		opts = append(opts, config.WithHTTPClient(&http.Client{Transport: recorder.Transport("aws-"+serviceType, base)}))
+		if recorder.Replaying() {
+			opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("REPLAY", "REPLAY", "")))
+		}
+	} else if endpoint.InsecureSkipVerify {
+		opts = append(opts, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{}
+			}
+			tr.TLSClientConfig.InsecureSkipVerify = true
+		})))
+	}
+	return opts
+}
@@ -0,0 +1,139 @@
+package generic
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// Recorder metadata keys for the identity claims of the recording principal
+const (
+	recordedAzureTenantID = "azure-tenant-id"
+	recordedAzureObjectID = "azure-object-id"
+)
+
+// AzureClientOptions returns azcore client options that record to or replay from the
+// named cassette. Returns zero-value options when the HTTP recorder is off.
+func AzureClientOptions(name string) azcore.ClientOptions {
+	if !recorder.Enabled() {
+		return azcore.ClientOptions{}
+	}
+	return azcore.ClientOptions{Transport: recorder.Client(name)}
+}
+
+// AzureARMOptions returns ARM client options for the named cassette, or nil when the
+// HTTP recorder is off (the SDK defaults)
+func AzureARMOptions(name string) *arm.ClientOptions {
+	if !recorder.Enabled() {
+		return nil
+	}
+	return &arm.ClientOptions{ClientOptions: AzureClientOptions(name)}
+}
+
+// AzureDefaultCredential returns the default Azure credential chain, or a placeholder
+// credential when replaying recorded traffic
+func AzureDefaultCredential() (azcore.TokenCredential, error) {
+	if recorder.Replaying() {
+		return replayCredential{}, nil
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return recordingCredential(cred), nil
+}
+
+// AzureClientSecretCredential returns a service principal credential, or a placeholder
+// credential when replaying recorded traffic
+func AzureClientSecretCredential(tenantID, clientID, clientSecret string) (azcore.TokenCredential, error) {
+	if recorder.Replaying() {
+		return replayCredential{}, nil
+	}
+	return azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+}
+
+// recordingCredential wraps cred so that the tenant and object IDs of the recording
+// principal are saved with the cassettes; replayCredential puts them back in its tokens
+func recordingCredential(cred azcore.TokenCredential) azcore.TokenCredential {
+	if recorder.CurrentMode() != recorder.ModeRecord {
+		return cred
+	}
+	return claimRecorder{cred}
+}
+
+// claimRecorder remembers the tid and oid claims of the tokens it returns
+type claimRecorder struct {
+	azcore.TokenCredential
+}
+
+// GetToken returns the wrapped credential's token
+func (c claimRecorder) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	token, err := c.TokenCredential.GetToken(ctx, opts)
+	if err != nil {
+		return token, err
+	}
+	parts := strings.Split(token.Token, ".")
+	if len(parts) < 2 {
+		return token, nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return token, nil
+	}
+	var claims struct {
+		TenantID string `json:"tid"`
+		ObjectID string `json:"oid"`
+	}
+	if json.Unmarshal(payload, &claims) == nil {
+		if claims.TenantID != "" {
+			recorder.Remember(recordedAzureTenantID, claims.TenantID)
+		}
+		if claims.ObjectID != "" {
+			recorder.Remember(recordedAzureObjectID, claims.ObjectID)
+		}
+	}
+	return token, nil
+}
+
+// replayCredential issues unsigned tokens carrying the recorded tid and oid claims,
+// so code that reads them from the token sees the same principal as the recording
+type replayCredential struct{}
+
+// GetToken returns a placeholder token valid for an hour
+func (replayCredential) GetToken(ctx context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	claims, err := json.Marshal(map[string]string{
+		"tid": recorder.Recall(recordedAzureTenantID),
+		"oid": recorder.Recall(recordedAzureObjectID),
+	})
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("failed to build replay token: %w", err)
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	return azcore.AccessToken{
+		Token:     header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".replay",
+		ExpiresOn: time.Now().Add(time.Hour),
+	}, nil
+}
+
+// AzureTagLabels converts Azure resource tags to TestParams labels
+func AzureTagLabels(tags map[string]*string) []string {
+	values := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if value != nil {
+			values[key] = *value
+		} else {
+			values[key] = ""
+		}
+	}
+	return types.LabelsFromMap(values)
+}
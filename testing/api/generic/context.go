@@ -0,0 +1,55 @@
+package generic
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SwitchableContext is a context.Context whose underlying context can be replaced.
+// Factories and the SDK clients they construct hold on to a context (e.g. for token
+// refresh); giving them a SwitchableContext lets the runner switch to a fresh teardown
+// context once the run context has been cancelled, so the same clients can still clean up.
+type SwitchableContext struct {
+	mu    sync.RWMutex
+	inner context.Context
+}
+
+// NewSwitchableContext returns a SwitchableContext initially delegating to ctx
+func NewSwitchableContext(ctx context.Context) *SwitchableContext {
+	return &SwitchableContext{inner: ctx}
+}
+
+// Switch replaces the underlying context. Calls already in flight keep the old one.
+func (c *SwitchableContext) Switch(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inner = ctx
+}
+
+// current returns the underlying context
+func (c *SwitchableContext) current() context.Context {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.inner
+}
+
+// Deadline returns the deadline of the underlying context
+func (c *SwitchableContext) Deadline() (time.Time, bool) {
+	return c.current().Deadline()
+}
+
+// Done returns the done channel of the underlying context
+func (c *SwitchableContext) Done() <-chan struct{} {
+	return c.current().Done()
+}
+
+// Err returns the error of the underlying context
+func (c *SwitchableContext) Err() error {
+	return c.current().Err()
+}
+
+// Value returns the value stored for key in the underlying context
+func (c *SwitchableContext) Value(key any) any {
+	return c.current().Value(key)
+}
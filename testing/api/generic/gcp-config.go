@@ -0,0 +1,49 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+)
+
+// GCPClientOptions returns opts with the HTTP recorder installed for the named cassette.
+// When recording, requests are authenticated from opts before reaching the recorder, so
+// tokens never reach the cassette; when replaying, no credentials are used at all.
+// opts are returned unchanged when the recorder is off.
+func GCPClientOptions(ctx context.Context, name string, opts ...option.ClientOption) ([]option.ClientOption, error) {
+	if !recorder.Enabled() {
+		return opts, nil
+	}
+	if recorder.Replaying() {
+		return append(opts, option.WithHTTPClient(recorder.Client(name))), nil
+	}
+
+	transport, err := htransport.NewTransport(ctx, recorder.Transport(name, nil), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording GCP transport: %w", err)
+	}
+	return append(opts, option.WithHTTPClient(&http.Client{Transport: transport})), nil
+}
+
+// GCPGRPCClientOptions returns opts with the gRPC recorder installed for the named cassette,
+// for the gRPC-based GCP clients (IAM admin, Cloud Logging). When replaying, the client is
+// given a connection that serves calls from the cassette and uses no credentials. opts are
+// returned unchanged when the recorder is off.
+func GCPGRPCClientOptions(name string, opts ...option.ClientOption) ([]option.ClientOption, error) {
+	switch recorder.CurrentMode() {
+	case recorder.ModeReplay:
+		conn, err := recorder.ReplayConn(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create replaying gRPC connection: %w", err)
+		}
+		return append(opts, option.WithGRPCConn(conn)), nil
+	case recorder.ModeRecord:
+		return append(opts, option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(recorder.UnaryClientInterceptor(name)))), nil
+	}
+	return opts, nil
+}
@@ -0,0 +1,89 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admin "cloud.google.com/go/iam/admin/apiv1"
+	"cloud.google.com/go/iam/admin/apiv1/adminpb"
+	"cloud.google.com/go/storage"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
+)
+
+// The cassettes in testdata/replay hold one GCS (HTTP) and two IAM admin (gRPC) exchanges.
+// Replay needs no credentials or network, so these run anywhere.
+
+const replayServiceAccount = "projects/ccc-replay/serviceAccounts/ccc-reader@ccc-replay.iam.gserviceaccount.com"
+
+func replay(t *testing.T) {
+	t.Helper()
+	if err := recorder.Configure(recorder.ModeReplay, "testdata/replay"); err != nil {
+		t.Fatalf("configure replay: %v", err)
+	}
+	t.Cleanup(func() { recorder.Configure(recorder.ModeOff, "") })
+}
+
+func TestReplayGCSBucketAttrs(t *testing.T) {
+	replay(t)
+	ctx := context.Background()
+
+	opts, err := GCPClientOptions(ctx, "gcp-storage")
+	if err != nil {
+		t.Fatalf("GCPClientOptions: %v", err)
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		t.Fatalf("storage.NewClient: %v", err)
+	}
+	defer client.Close()
+
+	attrs, err := client.Bucket("ccc-replay-bucket").Attrs(ctx)
+	if err != nil {
+		t.Fatalf("Attrs: %v", err)
+	}
+	if attrs.Location != "US-CENTRAL1" || !attrs.VersioningEnabled || attrs.Labels["env"] != "test" {
+		t.Errorf("unexpected attributes: location %s, versioning %v, labels %v", attrs.Location, attrs.VersioningEnabled, attrs.Labels)
+	}
+
+	if _, err := client.Bucket("not-recorded").Attrs(ctx); err == nil {
+		t.Error("expected an error for a request that was not recorded")
+	}
+}
+
+func TestReplayGCPIAMAdmin(t *testing.T) {
+	replay(t)
+	ctx := context.Background()
+
+	opts, err := GCPGRPCClientOptions("gcp-iam")
+	if err != nil {
+		t.Fatalf("GCPGRPCClientOptions: %v", err)
+	}
+	client, err := admin.NewIamClient(ctx, opts...)
+	if err != nil {
+		t.Fatalf("admin.NewIamClient: %v", err)
+	}
+	defer client.Close()
+
+	account, err := client.GetServiceAccount(ctx, &adminpb.GetServiceAccountRequest{Name: replayServiceAccount})
+	if err != nil {
+		t.Fatalf("GetServiceAccount: %v", err)
+	}
+	if account.Email != "ccc-reader@ccc-replay.iam.gserviceaccount.com" {
+		t.Errorf("Email = %s", account.Email)
+	}
+
+	// The recorded key material is redacted to an empty JSON object
+	key, err := client.CreateServiceAccountKey(ctx, &adminpb.CreateServiceAccountKeyRequest{Name: replayServiceAccount})
+	if err != nil {
+		t.Fatalf("CreateServiceAccountKey: %v", err)
+	}
+	var keyData map[string]interface{}
+	if err := json.Unmarshal(key.PrivateKeyData, &keyData); err != nil || len(keyData) != 0 {
+		t.Errorf("PrivateKeyData = %q, want the redacted {}", key.PrivateKeyData)
+	}
+
+	if err := client.DeleteServiceAccount(ctx, &adminpb.DeleteServiceAccountRequest{Name: replayServiceAccount}); err == nil {
+		t.Error("expected an error for a call that was not recorded")
+	}
+}
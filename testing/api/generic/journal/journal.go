@@ -0,0 +1,302 @@
+// Package journal records every cloud resource created by a test run in an on-disk,
+// append-only file, so resources leaked by a crashed or killed run can be found and
+// deleted later by `ccc-compliance cleanup`.
+package journal
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// TagKey is the tag (or label) carrying the run ID on every resource a run creates
+const TagKey = "ccc-run-id"
+
+// Resource kinds recorded in the journal
+const (
+	KindIAMUser          = "iam-user"          // AWS IAM user, with its access keys and policies
+	KindApplication      = "application"       // Azure app registration, with its credentials
+	KindServicePrincipal = "service-principal" // Azure service principal
+	KindServiceAccount   = "service-account"   // GCP service account, with its keys
+	KindRoleAssignment   = "role-assignment"   // Azure RBAC role assignment
+	KindBucket           = "bucket"            // S3 bucket, Azure container or GCS bucket
+	KindObject           = "object"            // Object in a bucket; ID is "<bucket>/<object>"
+	KindInstance         = "instance"          // EC2 test instance
+)
+
+// Entry is one journal line: a resource created by a run, or (Deleted) its removal
+type Entry struct {
+	RunID    string            `json:"run-id"`
+	Time     time.Time         `json:"time"`
+	Instance string            `json:"instance"` // Instance ID from environment.yaml
+	Provider string            `json:"provider"`
+	Service  string            `json:"service"` // Service type that created it, used to delete it
+	Kind     string            `json:"kind"`
+	ID       string            `json:"id"`
+	Details  map[string]string `json:"details,omitempty"` // Anything else needed to delete it, e.g. region
+	Deleted  bool              `json:"deleted,omitempty"`
+}
+
+// key identifies the resource an entry refers to
+func (e Entry) key() string {
+	return strings.Join([]string{e.Instance, e.Provider, e.Kind, e.ID}, "|")
+}
+
+// Sweeper is implemented by services that can delete the resources they journal.
+// Sweep returns nil if the resource no longer exists.
+type Sweeper interface {
+	Sweep(ctx context.Context, entry Entry) error
+}
+
+// Finder is implemented by services that can list the resources carrying the run-ID tag, so
+// cleanup also finds leaks missing from the local journal (e.g. of a run on another machine)
+type Finder interface {
+	FindTagged(ctx context.Context) ([]Entry, error)
+}
+
+// state is the run-wide journal configuration, set once by Configure
+var state = struct {
+	mu    sync.Mutex
+	dir   string
+	runID string
+}{}
+
+// runIDLayout is the time layout that starts every run ID
+const runIDLayout = "20060102-150405"
+
+// NewRunID returns a sortable, unique run ID, e.g. 20250102-150405-1a2b3c
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().UTC().Format(runIDLayout) + "-" + hex.EncodeToString(suffix)
+}
+
+// Configure enables journaling of this run's resources to <dir>/<runID>.jsonl
+func Configure(dir, runID string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.dir = dir
+	state.runID = runID
+	return nil
+}
+
+// RunID returns the configured run ID ("" when journaling is off)
+func RunID() string {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.runID
+}
+
+// Tags returns the run-ID tag to apply to created resources (empty when journaling is off)
+func Tags() map[string]string {
+	runID := RunID()
+	if runID == "" {
+		return map[string]string{}
+	}
+	return map[string]string{TagKey: runID}
+}
+
+// Created records a resource created by this run. It is a no-op when journaling is off,
+// and a failure to write is only a warning: the resource is still torn down in memory.
+func Created(instance types.InstanceConfig, service, kind, id string, details map[string]string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.runID == "" {
+		return
+	}
+	write(state.dir, Entry{
+		RunID:    state.runID,
+		Time:     time.Now().UTC(),
+		Instance: instance.ID,
+		Provider: instance.Properties.Provider,
+		Service:  service,
+		Kind:     kind,
+		ID:       id,
+		Details:  details,
+	})
+}
+
+// Found returns the entry for a resource found by its run-ID tag. It is dated by the run ID,
+// since the tag is all that records when the resource was created.
+func Found(instance types.InstanceConfig, service, kind, id, runID string) Entry {
+	return Entry{
+		RunID:    runID,
+		Time:     RunTime(runID),
+		Instance: instance.ID,
+		Provider: instance.Properties.Provider,
+		Service:  service,
+		Kind:     kind,
+		ID:       id,
+	}
+}
+
+// RunTime returns the start time encoded in a run ID, or the zero time if it has none
+func RunTime(runID string) time.Time {
+	if len(runID) < len(runIDLayout) {
+		return time.Time{}
+	}
+	t, err := time.Parse(runIDLayout, runID[:len(runIDLayout)])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Merge returns entries followed by the found entries for resources not already in entries.
+// Instances sharing an account find the same resources, so instances are not compared.
+func Merge(entries, found []Entry) []Entry {
+	resource := func(e Entry) string {
+		return strings.Join([]string{e.Provider, e.Kind, e.ID}, "|")
+	}
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		seen[resource(e)] = true
+	}
+	merged := entries
+	for _, e := range found {
+		if !seen[resource(e)] {
+			seen[resource(e)] = true
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// Deleted records that a resource created by this run has been removed
+func Deleted(instance types.InstanceConfig, kind, id string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.runID == "" {
+		return
+	}
+	write(state.dir, Entry{
+		RunID:    state.runID,
+		Time:     time.Now().UTC(),
+		Instance: instance.ID,
+		Provider: instance.Properties.Provider,
+		Kind:     kind,
+		ID:       id,
+		Deleted:  true,
+	})
+}
+
+// MarkSwept records in the entry's own run journal in dir that the resource has been
+// removed by cleanup. Resources found by their tag get a journal if their run has none here.
+func MarkSwept(dir string, entry Entry) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	swept := entry
+	swept.Time = time.Now().UTC()
+	swept.Details = nil
+	swept.Deleted = true
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	return appendEntry(filepath.Join(dir, entry.RunID+".jsonl"), swept)
+}
+
+// write appends e to the current run's journal, warning on failure
+func write(dir string, e Entry) {
+	if err := appendEntry(filepath.Join(dir, e.RunID+".jsonl"), e); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to write teardown journal: %v\n", err)
+	}
+}
+
+// appendEntry appends e as one JSON line to path. Each line is written with a single
+// call so a run killed mid-write leaves at most one truncated line.
+func appendEntry(path string, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Outstanding reads every run journal in dir and returns the resources that were created
+// but never deleted, oldest first
+func Outstanding(dir string) ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journals: %w", err)
+	}
+
+	var outstanding []Entry
+	for _, file := range files {
+		entries, err := readJournal(file)
+		if err != nil {
+			return nil, err
+		}
+		live := make(map[string]Entry)
+		var order []string
+		for _, e := range entries {
+			if e.Deleted {
+				delete(live, e.key())
+				continue
+			}
+			if _, ok := live[e.key()]; !ok {
+				order = append(order, e.key())
+			}
+			live[e.key()] = e
+		}
+		for _, k := range order {
+			if e, ok := live[k]; ok {
+				outstanding = append(outstanding, e)
+				delete(live, k)
+			}
+		}
+	}
+
+	sort.SliceStable(outstanding, func(i, j int) bool {
+		return outstanding[i].Time.Before(outstanding[j].Time)
+	})
+	return outstanding, nil
+}
+
+// readJournal parses one run journal, skipping a truncated final line
+func readJournal(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			fmt.Printf("⚠️  Warning: Skipping unreadable line %d of %s: %v\n", line, filepath.Base(path), err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal %s: %w", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,186 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// configureTest journals to a temporary directory for runID, turning journaling off afterwards
+func configureTest(t *testing.T, runID string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := Configure(dir, runID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		state.mu.Lock()
+		state.dir, state.runID = "", ""
+		state.mu.Unlock()
+	})
+	return dir
+}
+
+// ids returns the kind and ID of each entry, e.g. "bucket:b1"
+func ids(entries []Entry) []string {
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Kind+":"+e.ID)
+	}
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var testInstance = types.InstanceConfig{ID: "main-aws", Properties: types.CloudParams{Provider: "aws"}}
+
+func TestOutstanding(t *testing.T) {
+	dir := configureTest(t, "20250102-150405-aaaaaa")
+
+	Created(testInstance, "object-storage", KindBucket, "b1", nil)
+	Created(testInstance, "object-storage", KindObject, "b1/o1", nil)
+	Created(testInstance, "iam", KindIAMUser, "u1", nil)
+	Deleted(testInstance, KindObject, "b1/o1")
+	// A retried create records the resource twice; the later details win
+	Created(testInstance, "object-storage", KindBucket, "b1", map[string]string{"region": "eu-west-1"})
+	// The same ID on another instance is another resource
+	other := types.InstanceConfig{ID: "other-aws", Properties: types.CloudParams{Provider: "aws"}}
+	Created(other, "iam", KindIAMUser, "u1", nil)
+	Deleted(testInstance, KindIAMUser, "u1")
+
+	outstanding, err := Outstanding(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(outstanding), []string{"bucket:b1", "iam-user:u1"}; !equalStrings(got, want) {
+		t.Fatalf("outstanding = %v, want %v", got, want)
+	}
+	if outstanding[0].Details["region"] != "eu-west-1" {
+		t.Errorf("bucket details = %v, want those of the later create", outstanding[0].Details)
+	}
+	if outstanding[1].Instance != "other-aws" {
+		t.Errorf("outstanding user is on %s, want other-aws", outstanding[1].Instance)
+	}
+}
+
+func TestOutstandingRecreatedAfterDelete(t *testing.T) {
+	dir := configureTest(t, "20250102-150405-aaaaaa")
+
+	Created(testInstance, "object-storage", KindBucket, "b1", nil)
+	Deleted(testInstance, KindBucket, "b1")
+	Created(testInstance, "object-storage", KindBucket, "b1", nil)
+
+	outstanding, err := Outstanding(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(outstanding), []string{"bucket:b1"}; !equalStrings(got, want) {
+		t.Errorf("outstanding = %v, want %v", got, want)
+	}
+}
+
+func TestOutstandingSkipsTruncatedLine(t *testing.T) {
+	runID := "20250102-150405-aaaaaa"
+	dir := configureTest(t, runID)
+
+	Created(testInstance, "object-storage", KindBucket, "b1", nil)
+	Created(testInstance, "object-storage", KindBucket, "b2", nil)
+	// A run killed mid-write leaves a partial final line
+	f, err := os.OpenFile(filepath.Join(dir, runID+".jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"run-id":"` + runID + `","kind":"bucket","id":"b`)
+	f.Close()
+
+	outstanding, err := Outstanding(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(outstanding), []string{"bucket:b1", "bucket:b2"}; !equalStrings(got, want) {
+		t.Errorf("outstanding = %v, want %v", got, want)
+	}
+}
+
+func TestOutstandingReadsEveryRun(t *testing.T) {
+	dir := t.TempDir()
+	older := Entry{RunID: "20250101-000000-aaaaaa", Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Instance: "main-aws", Provider: "aws", Kind: KindBucket, ID: "old"}
+	newer := Entry{RunID: "20250102-000000-bbbbbb", Time: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Instance: "main-aws", Provider: "aws", Kind: KindBucket, ID: "new"}
+	gone := newer
+	gone.ID = "gone"
+	// Written newest run first, to check the result is ordered by time
+	for _, e := range []Entry{newer, gone, older} {
+		if err := appendEntry(filepath.Join(dir, e.RunID+".jsonl"), e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Cleanup records a sweep in the resource's own run journal
+	if err := MarkSwept(dir, gone); err != nil {
+		t.Fatal(err)
+	}
+	// Other files in the directory are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a journal"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	outstanding, err := Outstanding(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(outstanding), []string{"bucket:old", "bucket:new"}; !equalStrings(got, want) {
+		t.Errorf("outstanding = %v, want %v", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	journaled := []Entry{
+		{Instance: "main-aws", Provider: "aws", Kind: KindBucket, ID: "b1"},
+		{Instance: "main-aws", Provider: "aws", Kind: KindIAMUser, ID: "u1"},
+	}
+	found := []Entry{
+		// Already journaled, possibly by another instance on the same account
+		{Instance: "other-aws", Provider: "aws", Kind: KindBucket, ID: "b1"},
+		{Instance: "main-aws", Provider: "aws", Kind: KindBucket, ID: "b2"},
+		// Found by two instances sharing an account
+		{Instance: "main-aws", Provider: "aws", Kind: KindBucket, ID: "b3"},
+		{Instance: "other-aws", Provider: "aws", Kind: KindBucket, ID: "b3"},
+		// The same ID on another provider is another resource
+		{Instance: "main-gcp", Provider: "gcp", Kind: KindBucket, ID: "b1"},
+	}
+
+	merged := Merge(journaled, found)
+	want := []string{"aws/main-aws/b1", "aws/main-aws/u1", "aws/main-aws/b2", "aws/main-aws/b3", "gcp/main-gcp/b1"}
+	var got []string
+	for _, e := range merged {
+		got = append(got, e.Provider+"/"+e.Instance+"/"+e.ID)
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("merged = %v, want %v", got, want)
+	}
+}
+
+func TestRunTime(t *testing.T) {
+	if got, want := RunTime("20250102-150405-1a2b3c"), time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("RunTime = %v, want %v", got, want)
+	}
+	for _, runID := range []string{"", "latest", "2025-01-02"} {
+		if got := RunTime(runID); !got.IsZero() {
+			t.Errorf("RunTime(%q) = %v, want the zero time", runID, got)
+		}
+	}
+}
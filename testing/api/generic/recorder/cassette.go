@@ -0,0 +1,281 @@
+package recorder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// redacted replaces secret values in cassettes
+const redacted = "REDACTED"
+
+// metadataFile holds the values stored with Remember
+const metadataFile = "metadata.json"
+
+// sensitiveHeaders are dropped from recorded responses
+var sensitiveHeaders = map[string]bool{
+	"Authorization":        true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Amz-Security-Token": true,
+	"X-Ms-Client-Secret":   true,
+}
+
+// sensitiveQueryParams are redacted from recorded URLs (SigV4 presigning, SAS tokens, API keys)
+var sensitiveQueryParams = map[string]bool{
+	"x-amz-credential":     true,
+	"x-amz-signature":      true,
+	"x-amz-security-token": true,
+	"sig":                  true,
+	"signature":            true,
+	"access_token":         true,
+	"key":                  true,
+}
+
+// sensitiveFields are redacted from JSON, XML and form-encoded bodies
+var sensitiveFields = []string{
+	"AccessKeyId", "SecretAccessKey", "SessionToken", "secretText", "password", "clientSecret",
+	"client_secret", "access_token", "refresh_token", "id_token", "privateKeyData", "private_key",
+}
+
+// redaction is a compiled pattern for one sensitive field in one body format
+type redaction struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// bodyRedactions are the JSON, XML and form-encoded patterns for sensitiveFields
+var bodyRedactions = func() []redaction {
+	var rs []redaction
+	for _, f := range sensitiveFields {
+		q := regexp.QuoteMeta(f)
+		rs = append(rs,
+			redaction{regexp.MustCompile(`("` + q + `"\s*:\s*)"[^"]*"`), `${1}"` + redacted + `"`},
+			redaction{regexp.MustCompile(`(<` + q + `>)[^<]*(</` + q + `>)`), `${1}` + redacted + `${2}`},
+			redaction{regexp.MustCompile(`((?:^|&)` + q + `=)[^&]*`), `${1}` + redacted},
+		)
+	}
+	return rs
+}()
+
+// interaction is one recorded request/response pair
+type interaction struct {
+	Key      string            `json:"key"` // Method, sanitized URL and body hash used to match on replay
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Request  string            `json:"request,omitempty"`
+	Status   int               `json:"status"`
+	Header   map[string]string `json:"header,omitempty"`
+	Body     string            `json:"body,omitempty"`
+	Encoding string            `json:"encoding,omitempty"` // "base64" for binary bodies
+	used     bool
+}
+
+// cassette is the ordered list of interactions recorded for one client
+type cassette struct {
+	path         string
+	mu           sync.Mutex
+	Interactions []*interaction `json:"interactions"`
+}
+
+// load reads the cassette from disk
+func (c *cassette) load() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse cassette %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// record appends an interaction and rewrites the cassette, so a run that is
+// interrupted still leaves every completed interaction on disk
+func (c *cassette) record(i *interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Interactions = append(c.Interactions, i)
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// next returns the first unused interaction matching key. Once all matches are used the
+// last one is served again, so polling loops replay against their final recorded state.
+func (c *cassette) next(key string) (*interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var last *interaction
+	for _, i := range c.Interactions {
+		if i.Key != key {
+			continue
+		}
+		if !i.used {
+			i.used = true
+			return i, true
+		}
+		last = i
+	}
+	return last, last != nil
+}
+
+// newInteraction builds a sanitized interaction from a live exchange
+func newInteraction(key string, req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) *interaction {
+	i := &interaction{
+		Key:     key,
+		Method:  req.Method,
+		URL:     sanitizeURL(req.URL),
+		Request: sanitizeBody(reqBody),
+		Status:  resp.StatusCode,
+		Header:  make(map[string]string),
+	}
+	for name, values := range resp.Header {
+		if !sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			i.Header[name] = strings.Join(values, ", ")
+		}
+	}
+	if utf8.Valid(respBody) {
+		i.Body = sanitizeBody(respBody)
+	} else {
+		i.Body = base64.StdEncoding.EncodeToString(respBody)
+		i.Encoding = "base64"
+	}
+	return i
+}
+
+// response rebuilds the recorded response for req
+func (i *interaction) response(req *http.Request) (*http.Response, error) {
+	body := []byte(i.Body)
+	if i.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(i.Body)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: invalid body for %s %s: %w", i.Method, i.URL, err)
+		}
+		body = decoded
+	}
+
+	header := make(http.Header)
+	for name, value := range i.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// interactionKey identifies a request independently of credentials, signatures and
+// multipart boundaries, so the same call made on replay matches its recording
+func interactionKey(req *http.Request, body []byte) string {
+	normalized := sanitizeBody(body)
+	if _, params, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil && params["boundary"] != "" {
+		normalized = strings.ReplaceAll(normalized, params["boundary"], "BOUNDARY")
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return req.Method + " " + sanitizeURL(req.URL) + " " + hex.EncodeToString(sum[:8])
+}
+
+// sanitizeURL returns u with sensitive query parameters redacted and the rest sorted
+func sanitizeURL(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range query[k] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			if sensitiveQueryParams[strings.ToLower(k)] {
+				v = redacted
+			}
+			b.WriteString(url.QueryEscape(k) + "=" + url.QueryEscape(v))
+		}
+	}
+
+	clean := *u
+	clean.User = nil
+	clean.RawQuery = b.String()
+	return clean.String()
+}
+
+// sanitizeBody redacts sensitive fields from a text body
+func sanitizeBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !utf8.Valid(body) {
+		sum := sha256.Sum256(body)
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+	s := string(body)
+	for _, r := range bodyRedactions {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+// sanitizeName makes a cassette name safe to use as a file name
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, name)
+}
+
+// loadMetadata reads the values stored with Remember (empty if none were recorded)
+func loadMetadata(dir string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if os.IsNotExist(err) {
+		return metadata, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorder metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse recorder metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// saveMetadata writes the values stored with Remember
+func saveMetadata(dir string, metadata map[string]string) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, metadataFile), data, 0644)
+}
@@ -0,0 +1,196 @@
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode selects what the recorder does with provider SDK HTTP traffic
+type Mode string
+
+const (
+	ModeOff    Mode = ""       // Pass requests straight through (default)
+	ModeRecord Mode = "record" // Pass requests through and save sanitized interactions to cassettes
+	ModeReplay Mode = "replay" // Serve responses from cassettes; never touch the network
+)
+
+// state is the run-wide recorder configuration, set once by Configure
+var state = struct {
+	mu        sync.Mutex
+	mode      Mode
+	dir       string
+	cassettes map[string]*cassette
+	metadata  map[string]string
+}{
+	cassettes: make(map[string]*cassette),
+	metadata:  make(map[string]string),
+}
+
+// Configure sets the recorder mode and cassette directory for this run.
+// In record mode the directory is created; in replay mode it must already exist.
+func Configure(mode Mode, dir string) error {
+	switch mode {
+	case ModeOff:
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+	case ModeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("cassette directory not found: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported recorder mode: %s", mode)
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.mode = mode
+	state.dir = dir
+	state.cassettes = make(map[string]*cassette)
+	state.metadata = make(map[string]string)
+
+	if mode == ModeReplay {
+		metadata, err := loadMetadata(dir)
+		if err != nil {
+			return err
+		}
+		state.metadata = metadata
+	}
+	return nil
+}
+
+// CurrentMode returns the configured mode
+func CurrentMode() Mode {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.mode
+}
+
+// Enabled reports whether HTTP traffic is being recorded or replayed
+func Enabled() bool {
+	return CurrentMode() != ModeOff
+}
+
+// Replaying reports whether responses are served from cassettes
+func Replaying() bool {
+	return CurrentMode() == ModeReplay
+}
+
+// Transport wraps base so that requests are recorded to, or replayed from, the cassette
+// called name. A nil base means http.DefaultTransport. base is returned unchanged when
+// the recorder is off.
+func Transport(name string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !Enabled() {
+		return base
+	}
+	return &transport{name: name, base: base}
+}
+
+// Client returns an *http.Client using Transport(name, nil)
+func Client(name string) *http.Client {
+	return &http.Client{Transport: Transport(name, nil)}
+}
+
+// Remember stores a non-secret value needed to replay the run (e.g. the tenant ID a
+// credential resolved to). Values are saved alongside the cassettes in record mode.
+func Remember(key, value string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.mode != ModeRecord || state.metadata[key] == value {
+		return
+	}
+	state.metadata[key] = value
+	if err := saveMetadata(state.dir, state.metadata); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to save recorder metadata: %v\n", err)
+	}
+}
+
+// Recall returns a value stored with Remember during recording
+func Recall(key string) string {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.metadata[key]
+}
+
+// cassetteFor returns the cassette called name, loading it from disk in replay mode
+func cassetteFor(name string) (*cassette, error) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if c, ok := state.cassettes[name]; ok {
+		return c, nil
+	}
+	c := &cassette{path: filepath.Join(state.dir, sanitizeName(name)+".json")}
+	if state.mode == ModeReplay {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+	state.cassettes[name] = c
+	return c, nil
+}
+
+// transport is the http.RoundTripper installed by Transport
+type transport struct {
+	name string
+	base http.RoundTripper
+}
+
+// RoundTrip records or replays a single request
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c, err := cassetteFor(t.name)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read request body: %w", err)
+	}
+	key := interactionKey(req, reqBody)
+
+	if Replaying() {
+		recorded, ok := c.next(key)
+		if !ok {
+			return nil, fmt.Errorf("recorder: no recorded interaction for %s %s in cassette %s", req.Method, sanitizeURL(req.URL), c.path)
+		}
+		return recorded.response(req)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read response body: %w", err)
+	}
+
+	if err := c.record(newInteraction(key, req, reqBody, resp, respBody)); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to save cassette %s: %v\n", c.path, err)
+	}
+	return resp, nil
+}
+
+// readBody drains *body and replaces it with an equivalent reader so it can be sent or returned
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
@@ -0,0 +1,153 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// Identity represents the identity of a user or service principal
+type Identity struct {
+	UserName    string            // Username or principal name
+	Provider    string            // Cloud provider (aws, azure, gcp)
+	Credentials map[string]string // Provider-specific credentials
+	Policy      string            // IAM policy document (JSON) for the current access level
+}
+
+// ServiceEnv is what a factory passes to a service constructor
+type ServiceEnv struct {
+	Ctx      context.Context // Held by the client for its calls; switched by the factory's SetContext
+	Instance *types.InstanceConfig
+	Identity *Identity // Identity to authenticate as; nil for the runner's own credentials
+	shared   *SharedState
+}
+
+// NewServiceEnv returns the environment for constructing a service of instance; shared is
+// the factory's state, common to every service it creates
+func NewServiceEnv(ctx context.Context, instance *types.InstanceConfig, identity *Identity, shared *SharedState) ServiceEnv {
+	return ServiceEnv{Ctx: ctx, Instance: instance, Identity: identity, shared: shared}
+}
+
+// SharedState holds values shared by the services of one factory, such as the IAM service
+// or the in-memory stores of the local provider
+type SharedState struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// NewSharedState returns empty shared state for a new factory
+func NewSharedState() *SharedState {
+	return &SharedState{values: make(map[string]interface{})}
+}
+
+// Shared returns the factory-wide value stored under key, calling create on first use.
+// A failed create is not stored, so the next call tries again.
+func (e ServiceEnv) Shared(key string, create func() (interface{}, error)) (interface{}, error) {
+	e.shared.mu.Lock()
+	defer e.shared.mu.Unlock()
+	if v, ok := e.shared.values[key]; ok {
+		return v, nil
+	}
+	v, err := create()
+	if err != nil {
+		return nil, err
+	}
+	e.shared.values[key] = v
+	return v, nil
+}
+
+// ServiceConstructor creates a client for a service
+type ServiceConstructor func(env ServiceEnv) (Service, error)
+
+// ProviderImplementation is a service's client for one cloud provider
+type ProviderImplementation struct {
+	New             ServiceConstructor // Client using the runner's own credentials
+	NewWithIdentity ServiceConstructor // Client authenticated as env.Identity; nil if not supported
+	Uncached        bool               // The factory constructs a new client on every GetServiceAPI call
+}
+
+// ServiceDefinition describes a service type: the catalogs its resources are tested against,
+// its environment.yaml block and how to construct its clients on each provider
+type ServiceDefinition struct {
+	Name          string      // Service type in environment.yaml and -service, e.g. "object-storage"
+	CatalogTypes  []string    // CCC catalog types its resources are tested with, e.g. "CCC.ObjStor"
+	TagFilter     []string    // Tag filter for resources that do not set their own
+	ElevateAccess bool        // Elevate access for inspection when the factory creates a client
+	Config        interface{} // Struct its environment.yaml block decodes into; nil for none
+	Providers     map[string]ProviderImplementation
+}
+
+// serviceRegistry holds the services registered by the api packages
+var serviceRegistry = struct {
+	mu       sync.RWMutex
+	services map[string]ServiceDefinition
+}{services: make(map[string]ServiceDefinition)}
+
+// RegisterService adds a service type, normally from the init function of its package.
+// Registering the same name twice panics.
+func RegisterService(def ServiceDefinition) {
+	serviceRegistry.mu.Lock()
+	defer serviceRegistry.mu.Unlock()
+	if _, dup := serviceRegistry.services[def.Name]; dup {
+		panic(fmt.Sprintf("service %s registered twice", def.Name))
+	}
+	serviceRegistry.services[def.Name] = def
+	if def.Config != nil {
+		types.RegisterServiceConfig(def.Name, def.Config)
+	}
+}
+
+// LookupService returns the definition of a registered service type
+func LookupService(name string) (ServiceDefinition, bool) {
+	serviceRegistry.mu.RLock()
+	defer serviceRegistry.mu.RUnlock()
+	def, ok := serviceRegistry.services[name]
+	return def, ok
+}
+
+// RegisteredServices returns every registered service definition, sorted by name
+func RegisteredServices() []ServiceDefinition {
+	serviceRegistry.mu.RLock()
+	defer serviceRegistry.mu.RUnlock()
+	defs := make([]ServiceDefinition, 0, len(serviceRegistry.services))
+	for _, def := range serviceRegistry.services {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// ServiceNames returns the names of the registered service types, sorted
+func ServiceNames() []string {
+	var names []string
+	for _, def := range RegisteredServices() {
+		names = append(names, def.Name)
+	}
+	return names
+}
+
+// ProviderNames returns the providers the service has a client for, sorted
+func (d ServiceDefinition) ProviderNames() []string {
+	names := make([]string, 0, len(d.Providers))
+	for provider := range d.Providers {
+		names = append(names, provider)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServiceImplementation returns the client of a registered service type for a provider
+func ServiceImplementation(name, provider string) (ServiceDefinition, ProviderImplementation, error) {
+	def, ok := LookupService(name)
+	if !ok {
+		return ServiceDefinition{}, ProviderImplementation{}, fmt.Errorf("unknown service type: %s", name)
+	}
+	impl, ok := def.Providers[provider]
+	if !ok || impl.New == nil {
+		return def, ProviderImplementation{}, fmt.Errorf("unsupported service type for %s: %s", provider, name)
+	}
+	return def, impl, nil
+}
@@ -2,6 +2,8 @@ package retry
 
 import (
 	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
 )
 
 // IsRetryable returns true if the error indicates a transient condition (e.g. RBAC propagation)
@@ -22,7 +24,7 @@ func Do[T any](attempts int, delay time.Duration, fn func() (T, error), isRetrya
 			return result, err
 		}
 		if i < attempts-1 {
-			time.Sleep(delay)
+			sleep(delay)
 		}
 	}
 	return result, err
@@ -41,8 +43,17 @@ func DoVoid(attempts int, delay time.Duration, fn func() error, isRetryable IsRe
 			return err
 		}
 		if i < attempts-1 {
-			time.Sleep(delay)
+			sleep(delay)
 		}
 	}
 	return err
 }
+
+// sleep waits between attempts, except when replaying recorded traffic: the recorded
+// responses already reflect the propagation delay, so replays retry immediately
+func sleep(delay time.Duration) {
+	if recorder.Replaying() {
+		return
+	}
+	time.Sleep(delay)
+}
@@ -1,9 +1,18 @@
 package generic
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
+// ErrNotApplicable is wrapped by errors from operations the current backend cannot support,
+// e.g. management-plane calls against a storage emulator. Check with errors.Is.
+var ErrNotApplicable = errors.New("not applicable")
+
 // LocationRegion represents a replication region for assertion compatibility.
 // The Value field matches the "value" column used in "array of objects with at least" steps.
 type LocationRegion struct {
@@ -22,50 +31,72 @@ type ReplicationStatus struct {
 
 // Service is the generic interface for cloud services
 // This interface can be extended in the future with common methods
-// that all cloud services should implement
+// that all cloud services should implement.
+// Every method takes the caller's context: cloud calls made by the method use it, so a
+// deadline or cancellation on ctx aborts them. Feature steps receive the scenario context.
 type Service interface {
 
 	// For a given service type, return all the resources that can be tested within it,
 	// as a set of TestParams. If no resources exist, create default ones.
-	GetOrProvisionTestableResources() ([]types.TestParams, error)
+	GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error)
+
+	// DiscoverTestableResources returns the resources that already exist, as TestParams,
+	// without provisioning defaults, elevating access or otherwise changing cloud state.
+	// Used by --plan to preview a run.
+	DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error)
 
 	// CheckUserProvisioned validates that the service's identity is properly provisioned
 	// and usable. Returns nil if the user is ready, error otherwise.
 	// This is used in a retry loop to ensure credentials have propagated before use.
-	CheckUserProvisioned() error
+	CheckUserProvisioned(ctx context.Context) error
 
 	// ElevateAccessForInspection temporarily elevates access permissions to allow testing
 	// For example, Azure storage might enable public network access
 	// The original access level is stored internally for later reset
-	ElevateAccessForInspection() error
+	ElevateAccessForInspection(ctx context.Context) error
 
 	// ResetAccess restores the original access permissions that were in place
 	// before ElevateAccessForInspection was called
-	ResetAccess() error
+	ResetAccess(ctx context.Context) error
 
 	// UpdateResourcePolicy updates the resource's policy in a way that triggers logging
 	// without changing the policy's functional behavior.
 	// AWS: Modifies the SID field
 	// Azure: Changes the description
 	// GCP: Changes the description
-	UpdateResourcePolicy() error
+	UpdateResourcePolicy(ctx context.Context) error
 
 	// TriggerDataWrite performs a logged data modification (create/update/delete).
 	// Service-specific: object-storage creates/deletes an object; RDMS inserts a row; etc.
 	// Used for CN04.AR02 behavioural tests (data write logging verification).
-	TriggerDataWrite(resourceID string) error
+	TriggerDataWrite(ctx context.Context, resourceID string) error
 
 	// GetResourceRegion returns the region/availability zone of the resource.
 	// Used for CN06.AR01 (resource location compliance).
-	GetResourceRegion(resourceID string) (string, error)
+	GetResourceRegion(ctx context.Context, resourceID string) (string, error)
 
 	// GetReplicationStatus returns replication/sync status for the resource.
 	// Used for CN08.AR01 (locations) and CN08.AR02 (status visibility).
 	// Object storage returns *types.ReplicationStatus; other services return nil with error.
-	GetReplicationStatus(resourceID string) (*ReplicationStatus, error)
+	GetReplicationStatus(ctx context.Context, resourceID string) (*ReplicationStatus, error)
 
 	// TearDown removes resources created during testing (objects, buckets, users, etc.).
 	// Each service tracks what it creates and removes them here.
 	// No-op for services that do not create resources (e.g. logging).
-	TearDown() error
+	TearDown(ctx context.Context) error
+}
+
+// SDKClientProvider is implemented by services whose provider SDK clients can be called by
+// policy `sdk` queries, so the queries reuse the clients (and credentials, endpoints and
+// recorder) of the service instead of shelling out to a CLI
+type SDKClientProvider interface {
+	// SDKClient returns the named client, e.g. "s3" for *s3.Client, for querying the
+	// resource resourceID (empty if the policy has none). Operations are called by method
+	// name with the context first.
+	SDKClient(ctx context.Context, name, resourceID string) (interface{}, error)
+}
+
+// UnknownSDKClient returns the error for an SDK client name a service does not have
+func UnknownSDKClient(service interface{}, name string, known ...string) error {
+	return fmt.Errorf("%T has no SDK client %q (available: %s)", service, name, strings.Join(known, ", "))
 }
@@ -3,8 +3,10 @@ package iam
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,28 +14,28 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
 // AWSIAMService implements IAMService for AWS
 type AWSIAMService struct {
 	client           *iam.Client
-	ctx              context.Context
 	instance         types.InstanceConfig
+	mu               sync.Mutex           // Guards the caches: parallel resources share this service
 	provisionedUsers map[string]*Identity // Cache of provisioned users by userName
 	accessLevels     map[string]string    // Cache of access levels by "userName:serviceID"
 }
 
 // NewAWSIAMService creates a new AWS IAM service using default credentials
 func NewAWSIAMService(ctx context.Context, instance types.InstanceConfig) (*AWSIAMService, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, generic.AWSLoadOptions(instance, "iam")...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	return &AWSIAMService{
 		client:           iam.NewFromConfig(cfg),
-		ctx:              ctx,
 		instance:         instance,
 		provisionedUsers: make(map[string]*Identity),
 		accessLevels:     make(map[string]string),
@@ -42,24 +44,28 @@ func NewAWSIAMService(ctx context.Context, instance types.InstanceConfig) (*AWSI
 
 // ProvisionUser creates a new IAM user with access keys
 // ProvisionUserWithAccess creates a user and sets their access level in a single operation
-func (s *AWSIAMService) ProvisionUserWithAccess(userName string, serviceID string, level string) (*Identity, error) {
+func (s *AWSIAMService) ProvisionUserWithAccess(ctx context.Context, userName string, serviceID string, level string) (*Identity, error) {
+	userName = resourceUserName(userName, serviceID)
+
 	// Check cache first for both user and access level
 	cacheKey := fmt.Sprintf("%s:%s", userName, serviceID)
-	if cachedIdentity, exists := s.provisionedUsers[userName]; exists {
-		if cachedLevel, levelExists := s.accessLevels[cacheKey]; levelExists && cachedLevel == level {
-			fmt.Printf("♻️  Using cached identity for user %s with %s access (skipping all delays)\n", userName, level)
-			return cachedIdentity, nil
-		}
+	s.mu.Lock()
+	cachedIdentity, exists := s.provisionedUsers[userName]
+	cachedLevel, levelExists := s.accessLevels[cacheKey]
+	s.mu.Unlock()
+	if exists && levelExists && cachedLevel == level {
+		fmt.Printf("♻️  Using cached identity for user %s with %s access (skipping all delays)\n", userName, level)
+		return cachedIdentity, nil
 	}
 
 	// Step 1: Provision the user (or retrieve existing) - no waiting needed for AWS credentials
-	identity, err := s.provisionUserInternal(userName)
+	identity, err := s.provisionUserInternal(ctx, userName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Step 2: Set access level - this will wait for IAM policy propagation
-	policyDoc, err := s.setAccessInternal(identity, serviceID, level)
+	policyDoc, err := s.setAccessInternal(ctx, identity, serviceID, level)
 	if err != nil {
 		return nil, err
 	}
@@ -68,21 +74,23 @@ func (s *AWSIAMService) ProvisionUserWithAccess(userName string, serviceID strin
 	identity.Policy = policyDoc
 
 	// Cache the identity and access level AFTER validation completes
+	s.mu.Lock()
 	s.provisionedUsers[userName] = identity
 	s.accessLevels[cacheKey] = level
+	s.mu.Unlock()
 
 	return identity, nil
 }
 
 // provisionUserInternal is the internal implementation of ProvisionUser
 // Note: This does NOT interact with cache - all caching is handled by ProvisionUserWithAccess
-func (s *AWSIAMService) provisionUserInternal(userName string) (*Identity, error) {
+func (s *AWSIAMService) provisionUserInternal(ctx context.Context, userName string) (*Identity, error) {
 
 	var createUserOutput *iam.CreateUserOutput
 	var userAlreadyExists bool
 
 	// Check if user already exists
-	getUserOutput, err := s.client.GetUser(s.ctx, &iam.GetUserInput{
+	getUserOutput, err := s.client.GetUser(ctx, &iam.GetUserInput{
 		UserName: aws.String(userName),
 	})
 	if err == nil {
@@ -93,22 +101,27 @@ func (s *AWSIAMService) provisionUserInternal(userName string) (*Identity, error
 	} else {
 		// User doesn't exist - create it
 		fmt.Printf("👤 Creating user %s...\n", userName)
-		createUserOutput, err = s.client.CreateUser(s.ctx, &iam.CreateUserInput{
-			UserName: aws.String(userName),
-			Tags: []iamtypes.Tag{
-				{
-					Key:   aws.String("Purpose"),
-					Value: aws.String("CCC-Testing"),
-				},
-				{
-					Key:   aws.String("ManagedBy"),
-					Value: aws.String("CCC-CFI-Compliance-Framework"),
-				},
+		tags := []iamtypes.Tag{
+			{
+				Key:   aws.String("Purpose"),
+				Value: aws.String("CCC-Testing"),
+			},
+			{
+				Key:   aws.String("ManagedBy"),
+				Value: aws.String("CCC-CFI-Compliance-Framework"),
 			},
+		}
+		for key, value := range journal.Tags() {
+			tags = append(tags, iamtypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+		createUserOutput, err = s.client.CreateUser(ctx, &iam.CreateUserInput{
+			UserName: aws.String(userName),
+			Tags:     tags,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create IAM user %s: %w", userName, err)
 		}
+		journal.Created(s.instance, "iam", journal.KindIAMUser, userName, nil)
 	}
 
 	// Create access key for the user
@@ -117,13 +130,13 @@ func (s *AWSIAMService) provisionUserInternal(userName string) (*Identity, error
 
 	// If user already exists, delete any existing access keys to avoid hitting the limit (AWS allows max 2 keys)
 	if userAlreadyExists {
-		listKeysOutput, err := s.client.ListAccessKeys(s.ctx, &iam.ListAccessKeysInput{
+		listKeysOutput, err := s.client.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
 			UserName: aws.String(userName),
 		})
 		if err == nil {
 			for _, keyMetadata := range listKeysOutput.AccessKeyMetadata {
 				fmt.Printf("   🗑️  Deleting old access key: %s\n", aws.ToString(keyMetadata.AccessKeyId))
-				_, err := s.client.DeleteAccessKey(s.ctx, &iam.DeleteAccessKeyInput{
+				_, err := s.client.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
 					UserName:    aws.String(userName),
 					AccessKeyId: keyMetadata.AccessKeyId,
 				})
@@ -135,13 +148,13 @@ func (s *AWSIAMService) provisionUserInternal(userName string) (*Identity, error
 	}
 
 	// Create new access key
-	createKeyOutput, err := s.client.CreateAccessKey(s.ctx, &iam.CreateAccessKeyInput{
+	createKeyOutput, err := s.client.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{
 		UserName: aws.String(userName),
 	})
 	if err != nil {
 		// Cleanup: delete the user if key creation fails (only if we just created it)
 		if !userAlreadyExists {
-			s.client.DeleteUser(s.ctx, &iam.DeleteUserInput{
+			s.client.DeleteUser(ctx, &iam.DeleteUserInput{
 				UserName: aws.String(userName),
 			})
 		}
@@ -192,9 +205,9 @@ func (s *AWSIAMService) provisionUserInternal(userName string) (*Identity, error
 // setAccessInternal grants an identity access to a specific AWS service/resource at the specified level
 // This is the internal implementation called by ProvisionUserWithAccess
 // Note: This does NOT interact with cache - all caching is handled by ProvisionUserWithAccess
-func (s *AWSIAMService) setAccessInternal(identity *Identity, serviceID string, level string) (string, error) {
+func (s *AWSIAMService) setAccessInternal(ctx context.Context, identity *Identity, serviceID string, level string) (string, error) {
 	// Check current access level
-	currentLevel, currentPolicy, err := s.GetAccess(identity, serviceID)
+	currentLevel, currentPolicy, err := s.GetAccess(ctx, identity, serviceID)
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Could not retrieve current access level: %v\n", err)
 	} else {
@@ -217,7 +230,7 @@ func (s *AWSIAMService) setAccessInternal(identity *Identity, serviceID string,
 	policyName := fmt.Sprintf("CCC-Test-%s-%s", sanitizeForPolicyName(serviceID), level)
 
 	// Attach inline policy to user
-	_, err = s.client.PutUserPolicy(s.ctx, &iam.PutUserPolicyInput{
+	_, err = s.client.PutUserPolicy(ctx, &iam.PutUserPolicyInput{
 		UserName:       aws.String(identity.UserName),
 		PolicyName:     aws.String(policyName),
 		PolicyDocument: aws.String(policyDocument),
@@ -238,9 +251,9 @@ func (s *AWSIAMService) setAccessInternal(identity *Identity, serviceID string,
 }
 
 // GetAccess retrieves the current access level for a user and service
-func (s *AWSIAMService) GetAccess(identity *Identity, serviceID string) (string, string, error) {
+func (s *AWSIAMService) GetAccess(ctx context.Context, identity *Identity, serviceID string) (string, string, error) {
 	// List all inline policies for the user
-	listPoliciesOutput, err := s.client.ListUserPolicies(s.ctx, &iam.ListUserPoliciesInput{
+	listPoliciesOutput, err := s.client.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
 		UserName: aws.String(identity.UserName),
 	})
 	if err != nil {
@@ -254,7 +267,7 @@ func (s *AWSIAMService) GetAccess(identity *Identity, serviceID string) (string,
 		// Check if this policy matches our service
 		if len(policyName) >= len(policyPrefix) && policyName[:len(policyPrefix)] == policyPrefix {
 			// Get the policy document
-			getPolicyOutput, err := s.client.GetUserPolicy(s.ctx, &iam.GetUserPolicyInput{
+			getPolicyOutput, err := s.client.GetUserPolicy(ctx, &iam.GetUserPolicyInput{
 				UserName:   aws.String(identity.UserName),
 				PolicyName: aws.String(policyName),
 			})
@@ -287,11 +300,11 @@ func (s *AWSIAMService) GetAccess(identity *Identity, serviceID string) (string,
 }
 
 // DestroyUser removes an IAM user and all associated resources
-func (s *AWSIAMService) DestroyUser(identity *Identity) error {
+func (s *AWSIAMService) DestroyUser(ctx context.Context, identity *Identity) error {
 	userName := identity.UserName
 
 	// List and delete access keys
-	listKeysOutput, err := s.client.ListAccessKeys(s.ctx, &iam.ListAccessKeysInput{
+	listKeysOutput, err := s.client.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
 		UserName: aws.String(userName),
 	})
 	if err != nil {
@@ -299,7 +312,7 @@ func (s *AWSIAMService) DestroyUser(identity *Identity) error {
 	}
 
 	for _, key := range listKeysOutput.AccessKeyMetadata {
-		_, err := s.client.DeleteAccessKey(s.ctx, &iam.DeleteAccessKeyInput{
+		_, err := s.client.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
 			UserName:    aws.String(userName),
 			AccessKeyId: key.AccessKeyId,
 		})
@@ -309,7 +322,7 @@ func (s *AWSIAMService) DestroyUser(identity *Identity) error {
 	}
 
 	// List and delete inline policies
-	listPoliciesOutput, err := s.client.ListUserPolicies(s.ctx, &iam.ListUserPoliciesInput{
+	listPoliciesOutput, err := s.client.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
 		UserName: aws.String(userName),
 	})
 	if err != nil {
@@ -317,7 +330,7 @@ func (s *AWSIAMService) DestroyUser(identity *Identity) error {
 	}
 
 	for _, policyName := range listPoliciesOutput.PolicyNames {
-		_, err := s.client.DeleteUserPolicy(s.ctx, &iam.DeleteUserPolicyInput{
+		_, err := s.client.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
 			UserName:   aws.String(userName),
 			PolicyName: aws.String(policyName),
 		})
@@ -327,7 +340,7 @@ func (s *AWSIAMService) DestroyUser(identity *Identity) error {
 	}
 
 	// List and detach managed policies
-	listAttachedOutput, err := s.client.ListAttachedUserPolicies(s.ctx, &iam.ListAttachedUserPoliciesInput{
+	listAttachedOutput, err := s.client.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
 		UserName: aws.String(userName),
 	})
 	if err != nil {
@@ -335,7 +348,7 @@ func (s *AWSIAMService) DestroyUser(identity *Identity) error {
 	}
 
 	for _, policy := range listAttachedOutput.AttachedPolicies {
-		_, err := s.client.DetachUserPolicy(s.ctx, &iam.DetachUserPolicyInput{
+		_, err := s.client.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
 			UserName:  aws.String(userName),
 			PolicyArn: policy.PolicyArn,
 		})
@@ -345,16 +358,55 @@ func (s *AWSIAMService) DestroyUser(identity *Identity) error {
 	}
 
 	// Finally, delete the user
-	_, err = s.client.DeleteUser(s.ctx, &iam.DeleteUserInput{
+	_, err = s.client.DeleteUser(ctx, &iam.DeleteUserInput{
 		UserName: aws.String(userName),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete user %s: %w", userName, err)
 	}
+	journal.Deleted(s.instance, journal.KindIAMUser, userName)
 
 	return nil
 }
 
+// Sweep deletes an IAM user journaled by an earlier run
+func (s *AWSIAMService) Sweep(ctx context.Context, entry journal.Entry) error {
+	if entry.Kind != journal.KindIAMUser {
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	err := s.DestroyUser(ctx, &Identity{UserName: entry.ID, Provider: "aws"})
+	var noSuchEntity *iamtypes.NoSuchEntityException
+	if errors.As(err, &noSuchEntity) {
+		return nil
+	}
+	return err
+}
+
+// FindTagged lists the IAM users carrying the run-ID tag
+func (s *AWSIAMService) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	var found []journal.Entry
+	pager := iam.NewListUsersPaginator(s.client, &iam.ListUsersInput{})
+	for pager.HasMorePages() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM users: %w", err)
+		}
+		for _, user := range page.Users {
+			userName := aws.ToString(user.UserName)
+			tags, err := s.client.ListUserTags(ctx, &iam.ListUserTagsInput{UserName: user.UserName})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list tags of IAM user %s: %w", userName, err)
+			}
+			for _, tag := range tags.Tags {
+				if aws.ToString(tag.Key) == journal.TagKey {
+					found = append(found, journal.Found(s.instance, "iam", journal.KindIAMUser, userName, aws.ToString(tag.Value)))
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
 // generatePolicyDocument creates an IAM policy document for the given resource and access level
 func (s *AWSIAMService) generatePolicyDocument(resourceIdentifier string, level string) (string, error) {
 	var statements []map[string]interface{}
@@ -476,40 +528,45 @@ func sanitizeForPolicyName(s string) string {
 }
 
 // Fill this later when we are writing tests for IAM
-func (s *AWSIAMService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *AWSIAMService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return []types.TestParams{}, nil
+}
+
+// DiscoverTestableResources returns no resources until IAM tests exist
+func (s *AWSIAMService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	return []types.TestParams{}, nil
 }
 
 // CheckUserProvisioned is a no-op for IAM services (no service-specific validation needed)
-func (s *AWSIAMService) CheckUserProvisioned() error {
+func (s *AWSIAMService) CheckUserProvisioned(ctx context.Context) error {
 	// No-op: IAM services don't require additional credential validation
 	return nil
 }
 
 // ElevateAccessForInspection is a no-op for IAM services
-func (s *AWSIAMService) ElevateAccessForInspection() error {
+func (s *AWSIAMService) ElevateAccessForInspection(ctx context.Context) error {
 	// No-op: IAM services don't have network-level access controls to elevate
 	return nil
 }
 
 // ResetAccess is a no-op for IAM services
-func (s *AWSIAMService) ResetAccess() error {
+func (s *AWSIAMService) ResetAccess(ctx context.Context) error {
 	// No-op: IAM services don't have network-level access controls to reset
 	return nil
 }
 
 // UpdateResourcePolicy is not applicable for IAM service
-func (s *AWSIAMService) UpdateResourcePolicy() error {
+func (s *AWSIAMService) UpdateResourcePolicy(ctx context.Context) error {
 	return nil
 }
 
 // TriggerDataWrite is not applicable for IAM service
-func (s *AWSIAMService) TriggerDataWrite(resourceID string) error {
+func (s *AWSIAMService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not supported for IAM service")
 }
 
 // GetResourceRegion is not applicable for IAM service
-func (s *AWSIAMService) GetResourceRegion(resourceID string) (string, error) {
+func (s *AWSIAMService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not supported for IAM service")
 }
 
@@ -519,18 +576,22 @@ func (s *AWSIAMService) IsDataReplicatedToSeparateLocation(resourceID string) (b
 }
 
 // GetReplicationStatus is not applicable for IAM service
-func (s *AWSIAMService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *AWSIAMService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not supported for IAM service")
 }
 
 // TearDown removes all provisioned test users
-func (s *AWSIAMService) TearDown() error {
-	for userName, identity := range s.provisionedUsers {
-		if err := s.DestroyUser(identity); err != nil {
+func (s *AWSIAMService) TearDown(ctx context.Context) error {
+	s.mu.Lock()
+	users := s.provisionedUsers
+	s.provisionedUsers = make(map[string]*Identity)
+	s.accessLevels = make(map[string]string)
+	s.mu.Unlock()
+
+	for userName, identity := range users {
+		if err := s.DestroyUser(ctx, identity); err != nil {
 			fmt.Printf("⚠️  Failed to destroy user %s: %v\n", userName, err)
 		}
 	}
-	s.provisionedUsers = make(map[string]*Identity)
-	s.accessLevels = make(map[string]string)
 	return nil
 }
@@ -10,13 +10,15 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/retry"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 	"github.com/google/uuid"
@@ -25,18 +27,18 @@ import (
 // AzureIAMService implements IAMService for Azure using Service Principals
 type AzureIAMService struct {
 	authClient       *armauthorization.RoleAssignmentsClient
-	ctx              context.Context
 	credential       azcore.TokenCredential
 	instance         types.InstanceConfig
 	httpClient       *http.Client
 	tenantID         string
+	mu               sync.Mutex           // Guards the caches: parallel resources share this service
 	provisionedUsers map[string]*Identity // Cache of provisioned users by userName
 	accessLevels     map[string]string    // Cache of access levels by "userName:serviceID"
 }
 
 // NewAzureIAMService creates a new Azure IAM service using default credentials
 func NewAzureIAMService(ctx context.Context, instance types.InstanceConfig) (*AzureIAMService, error) {
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	cred, err := generic.AzureDefaultCredential()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
@@ -51,7 +53,7 @@ func NewAzureIAMServiceWithCredentials(ctx context.Context, instance types.Insta
 
 func newAzureIAMServiceInternal(ctx context.Context, instance types.InstanceConfig, cred azcore.TokenCredential) (*AzureIAMService, error) {
 	cloudParams := instance.CloudParams()
-	authClient, err := armauthorization.NewRoleAssignmentsClient(cloudParams.AzureSubscriptionID, cred, nil)
+	authClient, err := armauthorization.NewRoleAssignmentsClient(cloudParams.AzureSubscriptionID, cred, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorization client: %w", err)
 	}
@@ -64,10 +66,9 @@ func newAzureIAMServiceInternal(ctx context.Context, instance types.InstanceConf
 
 	return &AzureIAMService{
 		authClient:       authClient,
-		ctx:              ctx,
 		credential:       cred,
 		instance:         instance,
-		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		httpClient:       &http.Client{Timeout: 30 * time.Second, Transport: recorder.Transport("azure-graph", nil)},
 		tenantID:         tenantID,
 		provisionedUsers: make(map[string]*Identity),
 		accessLevels:     make(map[string]string),
@@ -106,24 +107,28 @@ func getAzureTenantID() string {
 
 // ProvisionUser creates a new service principal with a client secret, or returns existing one
 // ProvisionUserWithAccess creates a user and sets their access level in a single operation
-func (s *AzureIAMService) ProvisionUserWithAccess(userName string, serviceID string, level string) (*Identity, error) {
+func (s *AzureIAMService) ProvisionUserWithAccess(ctx context.Context, userName string, serviceID string, level string) (*Identity, error) {
+	userName = resourceUserName(userName, serviceID)
+
 	// Check cache first for both user and access level
 	cacheKey := fmt.Sprintf("%s:%s", userName, serviceID)
-	if cachedIdentity, exists := s.provisionedUsers[userName]; exists {
-		if cachedLevel, levelExists := s.accessLevels[cacheKey]; levelExists && cachedLevel == level {
-			fmt.Printf("♻️  Using cached identity for user %s with %s access (skipping all delays)\n", userName, level)
-			return cachedIdentity, nil
-		}
+	s.mu.Lock()
+	cachedIdentity, exists := s.provisionedUsers[userName]
+	cachedLevel, levelExists := s.accessLevels[cacheKey]
+	s.mu.Unlock()
+	if exists && levelExists && cachedLevel == level {
+		fmt.Printf("♻️  Using cached identity for user %s with %s access (skipping all delays)\n", userName, level)
+		return cachedIdentity, nil
 	}
 
 	// Step 1: Provision the user (or retrieve existing) - no waiting yet
-	identity, err := s.provisionUserInternal(userName)
+	identity, err := s.provisionUserInternal(ctx, userName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Step 2: Set access level - this will wait for both credential and RBAC propagation together
-	policyDoc, err := s.setAccessInternal(identity, serviceID, level)
+	policyDoc, err := s.setAccessInternal(ctx, identity, serviceID, level)
 	if err != nil {
 		return nil, err
 	}
@@ -132,8 +137,10 @@ func (s *AzureIAMService) ProvisionUserWithAccess(userName string, serviceID str
 	identity.Policy = policyDoc
 
 	// Cache the identity and access level AFTER validation completes
+	s.mu.Lock()
 	s.provisionedUsers[userName] = identity
 	s.accessLevels[cacheKey] = level
+	s.mu.Unlock()
 
 	return identity, nil
 }
@@ -141,14 +148,14 @@ func (s *AzureIAMService) ProvisionUserWithAccess(userName string, serviceID str
 // provisionUserInternal is the internal implementation of ProvisionUser
 // Note: This does NOT interact with cache or wait for credential propagation
 // All caching and validation is handled by ProvisionUserWithAccess
-func (s *AzureIAMService) provisionUserInternal(userName string) (*Identity, error) {
+func (s *AzureIAMService) provisionUserInternal(ctx context.Context, userName string) (*Identity, error) {
 	// Service principal display names can be more flexible than managed identity names
 	displayName := sanitizeServicePrincipalName(userName)
 
 	fmt.Printf("🔷 Provisioning service principal: %s\n", displayName)
 
 	// Check if application already exists
-	existingAppID, existingObjectID, err := s.findApplicationByDisplayName(displayName)
+	existingAppID, existingObjectID, err := s.findApplicationByDisplayName(ctx, displayName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for existing application: %w", err)
 	}
@@ -164,36 +171,38 @@ func (s *AzureIAMService) provisionUserInternal(userName string) (*Identity, err
 		isExisting = true
 
 		// Get or create service principal for existing app
-		spObjectID, err = s.getOrCreateServicePrincipal(appID)
+		spObjectID, err = s.getOrCreateServicePrincipal(ctx, appID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get service principal: %w", err)
 		}
 	} else {
 		// Create new application
 		fmt.Printf("   📱 Creating new application...\n")
-		appID, objectID, err = s.createApplication(displayName)
+		appID, objectID, err = s.createApplication(ctx, displayName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create application: %w", err)
 		}
 		fmt.Printf("   📱 Application created: %s (ObjectID: %s)\n", appID, objectID)
+		journal.Created(s.instance, "iam", journal.KindApplication, objectID, map[string]string{"app_id": appID})
 
 		// Create service principal for the application
-		spObjectID, err = s.createServicePrincipal(appID)
+		spObjectID, err = s.createServicePrincipal(ctx, appID)
 		if err != nil {
 			// Try to clean up the application if service principal creation fails
-			_ = s.deleteApplication(objectID)
+			_ = s.deleteApplication(ctx, objectID)
 			return nil, fmt.Errorf("failed to create service principal: %w", err)
 		}
 		fmt.Printf("   🔑 Service principal created (ObjectID: %s)\n", spObjectID)
+		journal.Created(s.instance, "iam", journal.KindServicePrincipal, spObjectID, nil)
 	}
 
 	// Always create a new client secret (we can't retrieve existing ones)
-	clientSecret, secretID, err := s.addApplicationPassword(objectID, displayName)
+	clientSecret, secretID, err := s.addApplicationPassword(ctx, objectID, displayName)
 	if err != nil {
 		if !isExisting {
 			// Clean up if this was a new resource
-			_ = s.deleteServicePrincipal(spObjectID)
-			_ = s.deleteApplication(objectID)
+			_ = s.deleteServicePrincipal(ctx, spObjectID)
+			_ = s.deleteApplication(ctx, objectID)
 		}
 		return nil, fmt.Errorf("failed to create client secret: %w", err)
 	}
@@ -201,7 +210,7 @@ func (s *AzureIAMService) provisionUserInternal(userName string) (*Identity, err
 	fmt.Printf("   🔐 Client secret created\n")
 
 	// Get tenant ID
-	tenantID, err := s.getActualTenantID()
+	tenantID, err := s.getActualTenantID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tenant ID: %w", err)
 	}
@@ -239,7 +248,7 @@ func (s *AzureIAMService) provisionUserInternal(userName string) (*Identity, err
 // setAccessInternal grants an identity access to a specific Azure resource at the specified level
 // This is the internal implementation called by ProvisionUserWithAccess
 // Note: This does NOT interact with cache - all caching is handled by ProvisionUserWithAccess
-func (s *AzureIAMService) setAccessInternal(identity *Identity, serviceID string, level string) (string, error) {
+func (s *AzureIAMService) setAccessInternal(ctx context.Context, identity *Identity, serviceID string, level string) (string, error) {
 	// Get the role definition ID based on access level
 	roleDefinitionID, err := s.getRoleDefinitionForLevel(serviceID, level)
 	if err != nil {
@@ -278,7 +287,7 @@ func (s *AzureIAMService) setAccessInternal(identity *Identity, serviceID string
 		},
 	}
 
-	_, err = s.authClient.Create(s.ctx, scope, roleAssignmentName, roleAssignmentParams, nil)
+	assignment, err := s.authClient.Create(ctx, scope, roleAssignmentName, roleAssignmentParams, nil)
 	if err != nil {
 		// Check if assignment already exists
 		if strings.Contains(err.Error(), "already exists") || strings.Contains(err.Error(), "RoleAssignmentExists") {
@@ -288,19 +297,22 @@ func (s *AzureIAMService) setAccessInternal(identity *Identity, serviceID string
 		return "", fmt.Errorf("failed to create role assignment: %w", err)
 	}
 
+	if assignment.ID != nil {
+		journal.Created(s.instance, "iam", journal.KindRoleAssignment, *assignment.ID, nil)
+	}
 	fmt.Printf("   ✅ Access granted\n")
 
 	// Now validate both credential and RBAC propagation together
 	fmt.Printf("   🔄 Validating service principal credentials and RBAC propagation...\n")
 
 	// Step 1: Validate credentials work
-	err = s.waitForCredentialPropagation(identity.Credentials["client_id"], identity.Credentials["client_secret"], identity.Credentials["tenant_id"])
+	err = s.waitForCredentialPropagation(ctx, identity.Credentials["client_id"], identity.Credentials["client_secret"], identity.Credentials["tenant_id"])
 	if err != nil {
 		return "", fmt.Errorf("service principal credentials failed to propagate: %w", err)
 	}
 
 	// Step 2: Validate RBAC has propagated
-	err = s.waitForRBACPropagation(objectID, scope, roleDefinitionID)
+	err = s.waitForRBACPropagation(ctx, objectID, scope, roleDefinitionID)
 	if err != nil {
 		return "", fmt.Errorf("RBAC propagation validation failed: %w", err)
 	}
@@ -312,7 +324,7 @@ func (s *AzureIAMService) setAccessInternal(identity *Identity, serviceID string
 }
 
 // DestroyUser removes a service principal and all associated resources
-func (s *AzureIAMService) DestroyUser(identity *Identity) error {
+func (s *AzureIAMService) DestroyUser(ctx context.Context, identity *Identity) error {
 	displayName := identity.Credentials["display_name"]
 	if displayName == "" {
 		displayName = identity.UserName
@@ -332,7 +344,7 @@ func (s *AzureIAMService) DestroyUser(identity *Identity) error {
 		})
 
 		for pager.More() {
-			page, err := pager.NextPage(s.ctx)
+			page, err := pager.NextPage(ctx)
 			if err != nil {
 				fmt.Printf("   ⚠️  Failed to list role assignments: %v\n", err)
 				break
@@ -345,9 +357,11 @@ func (s *AzureIAMService) DestroyUser(identity *Identity) error {
 					// Extract scope from assignment ID
 					scope := extractScopeFromAssignmentID(*assignment.ID)
 
-					_, err := s.authClient.Delete(s.ctx, scope, *assignment.Name, nil)
+					_, err := s.authClient.Delete(ctx, scope, *assignment.Name, nil)
 					if err != nil {
 						fmt.Printf("   ⚠️  Failed to delete role assignment: %v\n", err)
+					} else {
+						journal.Deleted(s.instance, journal.KindRoleAssignment, *assignment.ID)
 					}
 				}
 			}
@@ -357,22 +371,24 @@ func (s *AzureIAMService) DestroyUser(identity *Identity) error {
 	// Step 2: Delete the service principal
 	spObjectID := identity.Credentials["object_id"]
 	if spObjectID != "" {
-		err := s.deleteServicePrincipal(spObjectID)
+		err := s.deleteServicePrincipal(ctx, spObjectID)
 		if err != nil {
 			fmt.Printf("   ⚠️  Failed to delete service principal: %v\n", err)
 		} else {
 			fmt.Printf("   ✅ Service principal deleted\n")
+			journal.Deleted(s.instance, journal.KindServicePrincipal, spObjectID)
 		}
 	}
 
 	// Step 3: Delete the application
 	appObjectID := identity.Credentials["app_object_id"]
 	if appObjectID != "" {
-		err := s.deleteApplication(appObjectID)
+		err := s.deleteApplication(ctx, appObjectID)
 		if err != nil {
 			fmt.Printf("   ⚠️  Failed to delete application: %v\n", err)
 		} else {
 			fmt.Printf("   ✅ Application deleted\n")
+			journal.Deleted(s.instance, journal.KindApplication, appObjectID)
 		}
 	}
 
@@ -380,6 +396,60 @@ func (s *AzureIAMService) DestroyUser(identity *Identity) error {
 	return nil
 }
 
+// Sweep deletes an application, service principal or role assignment journaled by an
+// earlier run
+func (s *AzureIAMService) Sweep(ctx context.Context, entry journal.Entry) error {
+	var err error
+	switch entry.Kind {
+	case journal.KindRoleAssignment:
+		_, err = s.authClient.DeleteByID(ctx, entry.ID, nil)
+	case journal.KindServicePrincipal:
+		err = s.deleteServicePrincipal(ctx, entry.ID)
+	case journal.KindApplication:
+		err = s.deleteApplication(ctx, entry.ID)
+	default:
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	// Deleting an application also removes its service principal
+	if err != nil && strings.Contains(err.Error(), "status 404") {
+		return nil
+	}
+	return err
+}
+
+// FindTagged lists the applications tagged with a run ID. Their service principals go with
+// them; role assignments cannot be tagged, so they are only found through the journal.
+func (s *AzureIAMService) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	filter := fmt.Sprintf("tags/any(t:startswith(t,'%s:'))", journal.TagKey)
+	endpoint := fmt.Sprintf("/applications?$filter=%s&$select=id,appId,tags", url.QueryEscape(filter))
+
+	var found []journal.Entry
+	for endpoint != "" {
+		result, err := s.callGraphAPI(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tagged applications: %w", err)
+		}
+		apps, _ := result["value"].([]interface{})
+		for _, a := range apps {
+			app, _ := a.(map[string]interface{})
+			objectID, _ := app["id"].(string)
+			appID, _ := app["appId"].(string)
+			tags, _ := app["tags"].([]interface{})
+			for _, t := range tags {
+				tag, _ := t.(string)
+				if runID, ok := strings.CutPrefix(tag, journal.TagKey+":"); ok && objectID != "" {
+					entry := journal.Found(s.instance, "iam", journal.KindApplication, objectID, runID)
+					entry.Details = map[string]string{"app_id": appID}
+					found = append(found, entry)
+				}
+			}
+		}
+		next, _ := result["@odata.nextLink"].(string)
+		endpoint = strings.TrimPrefix(next, "https://graph.microsoft.com/v1.0")
+	}
+	return found, nil
+}
+
 // Helper functions
 
 func (s *AzureIAMService) getRoleDefinitionForLevel(serviceID string, level string) (string, error) {
@@ -472,38 +542,43 @@ func toPtr(s string) *string {
 }
 
 // Fill this later when we are writing tests for IAM
-func (s *AzureIAMService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *AzureIAMService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	return []types.TestParams{}, nil
 }
 
-func (s *AzureIAMService) CheckUserProvisioned() error {
+// DiscoverTestableResources returns no resources until IAM tests exist
+func (s *AzureIAMService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return []types.TestParams{}, nil
+}
+
+func (s *AzureIAMService) CheckUserProvisioned(ctx context.Context) error {
 	// No-op: IAM services don't require additional credential validation
 	return nil
 }
 
-func (s *AzureIAMService) ElevateAccessForInspection() error {
+func (s *AzureIAMService) ElevateAccessForInspection(ctx context.Context) error {
 	// No-op: IAM services don't have network-level access controls to elevate
 	return nil
 }
 
 // ResetAccess is a no-op for IAM services
-func (s *AzureIAMService) ResetAccess() error {
+func (s *AzureIAMService) ResetAccess(ctx context.Context) error {
 	// No-op: IAM services don't have network-level access controls to reset
 	return nil
 }
 
 // UpdateResourcePolicy is not applicable for IAM service
-func (s *AzureIAMService) UpdateResourcePolicy() error {
+func (s *AzureIAMService) UpdateResourcePolicy(ctx context.Context) error {
 	return nil
 }
 
 // TriggerDataWrite is not applicable for IAM service
-func (s *AzureIAMService) TriggerDataWrite(resourceID string) error {
+func (s *AzureIAMService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not supported for IAM service")
 }
 
 // GetResourceRegion is not applicable for IAM service
-func (s *AzureIAMService) GetResourceRegion(resourceID string) (string, error) {
+func (s *AzureIAMService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not supported for IAM service")
 }
 
@@ -513,31 +588,35 @@ func (s *AzureIAMService) IsDataReplicatedToSeparateLocation(resourceID string)
 }
 
 // GetReplicationStatus is not applicable for IAM service
-func (s *AzureIAMService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *AzureIAMService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not supported for IAM service")
 }
 
 // TearDown removes all provisioned test users
-func (s *AzureIAMService) TearDown() error {
-	for userName, identity := range s.provisionedUsers {
-		if err := s.DestroyUser(identity); err != nil {
+func (s *AzureIAMService) TearDown(ctx context.Context) error {
+	s.mu.Lock()
+	users := s.provisionedUsers
+	s.provisionedUsers = make(map[string]*Identity)
+	s.accessLevels = make(map[string]string)
+	s.mu.Unlock()
+
+	for userName, identity := range users {
+		if err := s.DestroyUser(ctx, identity); err != nil {
 			fmt.Printf("⚠️  Failed to destroy user %s: %v\n", userName, err)
 		}
 	}
-	s.provisionedUsers = make(map[string]*Identity)
-	s.accessLevels = make(map[string]string)
 	return nil
 }
 
 // Microsoft Graph API helper methods
 
-func (s *AzureIAMService) callGraphAPI(method, endpoint string, body interface{}) (map[string]interface{}, error) {
+func (s *AzureIAMService) callGraphAPI(ctx context.Context, method, endpoint string, body interface{}) (map[string]interface{}, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (map[string]interface{}, error) {
-		return s.callGraphAPIOnce(method, endpoint, body)
+		return s.callGraphAPIOnce(ctx, method, endpoint, body)
 	}, retry.IsAzureGraphAuthorizationDeniedError)
 }
 
-func (s *AzureIAMService) callGraphAPIOnce(method, endpoint string, body interface{}) (map[string]interface{}, error) {
+func (s *AzureIAMService) callGraphAPIOnce(ctx context.Context, method, endpoint string, body interface{}) (map[string]interface{}, error) {
 	graphURL := "https://graph.microsoft.com/v1.0" + endpoint
 
 	var reqBody io.Reader
@@ -549,13 +628,13 @@ func (s *AzureIAMService) callGraphAPIOnce(method, endpoint string, body interfa
 		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	req, err := http.NewRequestWithContext(s.ctx, method, graphURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, graphURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Get access token for Microsoft Graph
-	token, err := s.credential.GetToken(s.ctx, policy.TokenRequestOptions{
+	token, err := s.credential.GetToken(ctx, policy.TokenRequestOptions{
 		Scopes: []string{"https://graph.microsoft.com/.default"},
 	})
 	if err != nil {
@@ -590,13 +669,14 @@ func (s *AzureIAMService) callGraphAPIOnce(method, endpoint string, body interfa
 	return result, nil
 }
 
-func (s *AzureIAMService) createApplication(displayName string) (appID, objectID string, err error) {
+func (s *AzureIAMService) createApplication(ctx context.Context, displayName string) (appID, objectID string, err error) {
 	requestBody := map[string]interface{}{
 		"displayName":    displayName,
 		"signInAudience": "AzureADMyOrg",
+		"tags":           graphTags(),
 	}
 
-	result, err := s.callGraphAPI("POST", "/applications", requestBody)
+	result, err := s.callGraphAPI(ctx, "POST", "/applications", requestBody)
 	if err != nil {
 		return "", "", err
 	}
@@ -611,12 +691,13 @@ func (s *AzureIAMService) createApplication(displayName string) (appID, objectID
 	return appID, objectID, nil
 }
 
-func (s *AzureIAMService) createServicePrincipal(appID string) (objectID string, err error) {
+func (s *AzureIAMService) createServicePrincipal(ctx context.Context, appID string) (objectID string, err error) {
 	requestBody := map[string]interface{}{
 		"appId": appID,
+		"tags":  graphTags(),
 	}
 
-	result, err := s.callGraphAPI("POST", "/servicePrincipals", requestBody)
+	result, err := s.callGraphAPI(ctx, "POST", "/servicePrincipals", requestBody)
 	if err != nil {
 		return "", err
 	}
@@ -629,14 +710,14 @@ func (s *AzureIAMService) createServicePrincipal(appID string) (objectID string,
 	return objectID, nil
 }
 
-func (s *AzureIAMService) addApplicationPassword(appObjectID, displayName string) (secret, secretID string, err error) {
+func (s *AzureIAMService) addApplicationPassword(ctx context.Context, appObjectID, displayName string) (secret, secretID string, err error) {
 	requestBody := map[string]interface{}{
 		"passwordCredential": map[string]interface{}{
 			"displayName": displayName + "-secret",
 		},
 	}
 
-	result, err := s.callGraphAPI("POST", "/applications/"+appObjectID+"/addPassword", requestBody)
+	result, err := s.callGraphAPI(ctx, "POST", "/applications/"+appObjectID+"/addPassword", requestBody)
 	if err != nil {
 		return "", "", err
 	}
@@ -651,19 +732,28 @@ func (s *AzureIAMService) addApplicationPassword(appObjectID, displayName string
 	return secret, secretID, nil
 }
 
-func (s *AzureIAMService) deleteServicePrincipal(objectID string) error {
-	_, err := s.callGraphAPI("DELETE", "/servicePrincipals/"+objectID, nil)
+// graphTags returns the run-ID tags for Graph objects, which take "key:value" strings
+func graphTags() []string {
+	tags := []string{}
+	for key, value := range journal.Tags() {
+		tags = append(tags, key+":"+value)
+	}
+	return tags
+}
+
+func (s *AzureIAMService) deleteServicePrincipal(ctx context.Context, objectID string) error {
+	_, err := s.callGraphAPI(ctx, "DELETE", "/servicePrincipals/"+objectID, nil)
 	return err
 }
 
-func (s *AzureIAMService) deleteApplication(objectID string) error {
-	_, err := s.callGraphAPI("DELETE", "/applications/"+objectID, nil)
+func (s *AzureIAMService) deleteApplication(ctx context.Context, objectID string) error {
+	_, err := s.callGraphAPI(ctx, "DELETE", "/applications/"+objectID, nil)
 	return err
 }
 
-func (s *AzureIAMService) getActualTenantID() (string, error) {
+func (s *AzureIAMService) getActualTenantID(ctx context.Context) (string, error) {
 	// Get the organization details to extract tenant ID
-	result, err := s.callGraphAPI("GET", "/organization", nil)
+	result, err := s.callGraphAPI(ctx, "GET", "/organization", nil)
 	if err != nil {
 		return "", err
 	}
@@ -680,12 +770,12 @@ func (s *AzureIAMService) getActualTenantID() (string, error) {
 	return "", fmt.Errorf("failed to extract tenant ID from organization response")
 }
 
-func (s *AzureIAMService) findApplicationByDisplayName(displayName string) (appID, objectID string, err error) {
+func (s *AzureIAMService) findApplicationByDisplayName(ctx context.Context, displayName string) (appID, objectID string, err error) {
 	// Search for applications by display name
 	filter := fmt.Sprintf("displayName eq '%s'", displayName)
 	endpoint := fmt.Sprintf("/applications?$filter=%s", url.QueryEscape(filter))
 
-	result, err := s.callGraphAPI("GET", endpoint, nil)
+	result, err := s.callGraphAPI(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return "", "", err
 	}
@@ -706,12 +796,12 @@ func (s *AzureIAMService) findApplicationByDisplayName(displayName string) (appI
 	return "", "", nil
 }
 
-func (s *AzureIAMService) getOrCreateServicePrincipal(appID string) (objectID string, err error) {
+func (s *AzureIAMService) getOrCreateServicePrincipal(ctx context.Context, appID string) (objectID string, err error) {
 	// Try to find existing service principal
 	filter := fmt.Sprintf("appId eq '%s'", appID)
 	endpoint := fmt.Sprintf("/servicePrincipals?$filter=%s", url.QueryEscape(filter))
 
-	result, err := s.callGraphAPI("GET", endpoint, nil)
+	result, err := s.callGraphAPI(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return "", err
 	}
@@ -729,12 +819,13 @@ func (s *AzureIAMService) getOrCreateServicePrincipal(appID string) (objectID st
 
 	// Service principal doesn't exist, create it
 	fmt.Printf("   🔑 Creating service principal...\n")
-	objectID, err = s.createServicePrincipal(appID)
+	objectID, err = s.createServicePrincipal(ctx, appID)
 	if err != nil {
 		return "", err
 	}
 
 	fmt.Printf("   🔑 Service principal created (ObjectID: %s)\n", objectID)
+	journal.Created(s.instance, "iam", journal.KindServicePrincipal, objectID, nil)
 	return objectID, nil
 }
 
@@ -745,13 +836,13 @@ var errRBACNotPropagatedYet = errors.New("rbac not propagated yet")
 // waitForCredentialPropagation validates that the service principal credentials work
 // by attempting to acquire a token for Graph API (identity management scope).
 // It retries for up to 60 seconds when propagation errors are detected.
-func (s *AzureIAMService) waitForCredentialPropagation(clientID, clientSecret, tenantID string) error {
+func (s *AzureIAMService) waitForCredentialPropagation(ctx context.Context, clientID, clientSecret, tenantID string) error {
 	_, err := retry.Do(12, 5*time.Second, func() (struct{}, error) {
-		cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+		cred, err := generic.AzureClientSecretCredential(tenantID, clientID, clientSecret)
 		if err != nil {
 			return struct{}{}, fmt.Errorf("failed to create credential for validation: %w", err)
 		}
-		_, err = cred.GetToken(s.ctx, policy.TokenRequestOptions{
+		_, err = cred.GetToken(ctx, policy.TokenRequestOptions{
 			Scopes: []string{"https://graph.microsoft.com/.default"},
 		})
 		return struct{}{}, err
@@ -765,9 +856,9 @@ func (s *AzureIAMService) waitForCredentialPropagation(clientID, clientSecret, t
 
 // waitForRBACPropagation validates that the role assignment has propagated and is effective
 // by verifying the assignment exists. It retries for up to 60 seconds.
-func (s *AzureIAMService) waitForRBACPropagation(principalID, scope, roleDefinitionID string) error {
+func (s *AzureIAMService) waitForRBACPropagation(ctx context.Context, principalID, scope, roleDefinitionID string) error {
 	err := retry.DoVoid(12, 5*time.Second, func() error {
-		found, listErr := s.checkRoleAssignmentExists(principalID, scope, roleDefinitionID)
+		found, listErr := s.checkRoleAssignmentExists(ctx, principalID, scope, roleDefinitionID)
 		if listErr != nil {
 			return listErr
 		}
@@ -788,14 +879,14 @@ func (s *AzureIAMService) waitForRBACPropagation(principalID, scope, roleDefinit
 }
 
 // checkRoleAssignmentExists lists role assignments and returns true if the expected one exists.
-func (s *AzureIAMService) checkRoleAssignmentExists(principalID, scope, roleDefinitionID string) (bool, error) {
+func (s *AzureIAMService) checkRoleAssignmentExists(ctx context.Context, principalID, scope, roleDefinitionID string) (bool, error) {
 	filter := fmt.Sprintf("principalId eq '%s'", principalID)
 	pager := s.authClient.NewListForScopePager(scope, &armauthorization.RoleAssignmentsClientListForScopeOptions{
 		Filter: &filter,
 	})
 
 	for pager.More() {
-		page, err := pager.NextPage(s.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return false, err
 		}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
 
 	admin "cloud.google.com/go/iam/admin/apiv1"
 	"cloud.google.com/go/iam/admin/apiv1/adminpb"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	iampb "google.golang.org/genproto/googleapis/iam/v1"
 )
@@ -17,22 +21,26 @@ import (
 // GCPIAMService implements IAMService for GCP
 type GCPIAMService struct {
 	client           *admin.IamClient
-	ctx              context.Context
 	instance         types.InstanceConfig
+	mu               sync.Mutex           // Guards the caches: parallel resources share this service
 	provisionedUsers map[string]*Identity // Cache of provisioned users by userName
 	accessLevels     map[string]string    // Cache of access levels by "userName:serviceID"
 }
 
 // NewGCPIAMService creates a new GCP IAM service using default credentials
 func NewGCPIAMService(ctx context.Context, instance types.InstanceConfig) (*GCPIAMService, error) {
-	client, err := admin.NewIamClient(ctx)
+	opts, err := generic.GCPGRPCClientOptions("gcp-iam")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := admin.NewIamClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCP IAM client: %w", err)
 	}
 
 	return &GCPIAMService{
 		client:           client,
-		ctx:              ctx,
 		instance:         instance,
 		provisionedUsers: make(map[string]*Identity),
 		accessLevels:     make(map[string]string),
@@ -41,14 +49,18 @@ func NewGCPIAMService(ctx context.Context, instance types.InstanceConfig) (*GCPI
 
 // NewGCPIAMServiceWithCredentials creates a new GCP IAM service with specific credentials
 func NewGCPIAMServiceWithCredentials(ctx context.Context, instance types.InstanceConfig, credentialsJSON []byte) (*GCPIAMService, error) {
-	client, err := admin.NewIamClient(ctx, option.WithCredentialsJSON(credentialsJSON))
+	opts, err := generic.GCPGRPCClientOptions("gcp-iam", option.WithCredentialsJSON(credentialsJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := admin.NewIamClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCP IAM client with credentials: %w", err)
 	}
 
 	return &GCPIAMService{
 		client:           client,
-		ctx:              ctx,
 		instance:         instance,
 		provisionedUsers: make(map[string]*Identity),
 		accessLevels:     make(map[string]string),
@@ -57,24 +69,28 @@ func NewGCPIAMServiceWithCredentials(ctx context.Context, instance types.Instanc
 
 // ProvisionUser creates a new IAM service account (GCP's equivalent of a user for programmatic access)
 // ProvisionUserWithAccess creates a user and sets their access level in a single operation
-func (s *GCPIAMService) ProvisionUserWithAccess(userName string, serviceID string, level string) (*Identity, error) {
+func (s *GCPIAMService) ProvisionUserWithAccess(ctx context.Context, userName string, serviceID string, level string) (*Identity, error) {
+	userName = resourceUserName(userName, serviceID)
+
 	// Check cache first for both user and access level
 	cacheKey := fmt.Sprintf("%s:%s", userName, serviceID)
-	if cachedIdentity, exists := s.provisionedUsers[userName]; exists {
-		if cachedLevel, levelExists := s.accessLevels[cacheKey]; levelExists && cachedLevel == level {
-			fmt.Printf("♻️  Using cached identity for user %s with %s access (skipping all delays)\n", userName, level)
-			return cachedIdentity, nil
-		}
+	s.mu.Lock()
+	cachedIdentity, exists := s.provisionedUsers[userName]
+	cachedLevel, levelExists := s.accessLevels[cacheKey]
+	s.mu.Unlock()
+	if exists && levelExists && cachedLevel == level {
+		fmt.Printf("♻️  Using cached identity for user %s with %s access (skipping all delays)\n", userName, level)
+		return cachedIdentity, nil
 	}
 
 	// Step 1: Provision the user (or retrieve existing) - GCP service account keys are immediately usable
-	identity, err := s.provisionUserInternal(userName)
+	identity, err := s.provisionUserInternal(ctx, userName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Step 2: Set access level - GCP IAM changes propagate quickly, no explicit wait needed
-	policyDoc, err := s.setAccessInternal(identity, serviceID, level)
+	policyDoc, err := s.setAccessInternal(ctx, identity, serviceID, level)
 	if err != nil {
 		return nil, err
 	}
@@ -83,15 +99,17 @@ func (s *GCPIAMService) ProvisionUserWithAccess(userName string, serviceID strin
 	identity.Policy = policyDoc
 
 	// Cache the identity and access level AFTER validation completes
+	s.mu.Lock()
 	s.provisionedUsers[userName] = identity
 	s.accessLevels[cacheKey] = level
+	s.mu.Unlock()
 
 	return identity, nil
 }
 
 // provisionUserInternal is the internal implementation of ProvisionUser
 // Note: This does NOT interact with cache - all caching is handled by ProvisionUserWithAccess
-func (s *GCPIAMService) provisionUserInternal(userName string) (*Identity, error) {
+func (s *GCPIAMService) provisionUserInternal(ctx context.Context, userName string) (*Identity, error) {
 	// Service account ID must be between 6-30 characters, lowercase, digits, hyphens
 	serviceAccountID := sanitizeServiceAccountID(userName)
 	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com", serviceAccountID, s.instance.Properties.GcpProjectId)
@@ -104,7 +122,7 @@ func (s *GCPIAMService) provisionUserInternal(userName string) (*Identity, error
 		Name: fmt.Sprintf("projects/%s/serviceAccounts/%s", s.instance.Properties.GcpProjectId, serviceAccountEmail),
 	}
 
-	existingAccount, err := s.client.GetServiceAccount(s.ctx, getReq)
+	existingAccount, err := s.client.GetServiceAccount(ctx, getReq)
 	if err == nil {
 		// Service account exists - reuse it
 		fmt.Printf("🤖 Service account %s already exists, reusing...\n", serviceAccountEmail)
@@ -118,14 +136,15 @@ func (s *GCPIAMService) provisionUserInternal(userName string) (*Identity, error
 			AccountId: serviceAccountID,
 			ServiceAccount: &adminpb.ServiceAccount{
 				DisplayName: fmt.Sprintf("CCC Test User: %s", userName),
-				Description: "Created by CCC-CFI-Compliance-Framework for testing",
+				Description: serviceAccountDescription(),
 			},
 		}
 
-		serviceAccount, err = s.client.CreateServiceAccount(s.ctx, createReq)
+		serviceAccount, err = s.client.CreateServiceAccount(ctx, createReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create service account %s: %w", serviceAccountID, err)
 		}
+		journal.Created(s.instance, "iam", journal.KindServiceAccount, serviceAccountEmail, nil)
 	}
 
 	// Create service account key for authentication
@@ -137,7 +156,7 @@ func (s *GCPIAMService) provisionUserInternal(userName string) (*Identity, error
 			Name: serviceAccount.Name,
 		}
 
-		keysResp, err := s.client.ListServiceAccountKeys(s.ctx, listKeysReq)
+		keysResp, err := s.client.ListServiceAccountKeys(ctx, listKeysReq)
 		if err == nil && len(keysResp.Keys) > 0 {
 			fmt.Printf("   🔑 Service account has %d existing key(s)\n", len(keysResp.Keys))
 			// Note: We can't retrieve existing key data, so we create a new one
@@ -149,11 +168,11 @@ func (s *GCPIAMService) provisionUserInternal(userName string) (*Identity, error
 		Name: serviceAccount.Name,
 	}
 
-	key, err := s.client.CreateServiceAccountKey(s.ctx, createKeyReq)
+	key, err := s.client.CreateServiceAccountKey(ctx, createKeyReq)
 	if err != nil {
 		// Cleanup: delete the service account if key creation fails (only if we just created it)
 		if !accountAlreadyExists {
-			s.client.DeleteServiceAccount(s.ctx, &adminpb.DeleteServiceAccountRequest{
+			s.client.DeleteServiceAccount(ctx, &adminpb.DeleteServiceAccountRequest{
 				Name: serviceAccount.Name,
 			})
 		}
@@ -202,7 +221,7 @@ func (s *GCPIAMService) provisionUserInternal(userName string) (*Identity, error
 // setAccessInternal grants an identity access to a specific GCP resource at the specified level
 // This is the internal implementation called by ProvisionUserWithAccess
 // Note: This does NOT interact with cache - all caching is handled by ProvisionUserWithAccess
-func (s *GCPIAMService) setAccessInternal(identity *Identity, serviceID string, level string) (string, error) {
+func (s *GCPIAMService) setAccessInternal(ctx context.Context, identity *Identity, serviceID string, level string) (string, error) {
 	// Determine the IAM role based on access level
 	role, err := s.getRoleForLevel(serviceID, level)
 	if err != nil {
@@ -237,7 +256,7 @@ func (s *GCPIAMService) setAccessInternal(identity *Identity, serviceID string,
 		Resource: resourceName,
 	}
 
-	policy, err := s.getResourcePolicy(s.ctx, resourceName, getPolicyReq)
+	policy, err := s.getResourcePolicy(ctx, resourceName, getPolicyReq)
 	if err != nil {
 		return "", fmt.Errorf("failed to get IAM policy for %s: %w", resourceName, err)
 	}
@@ -277,7 +296,7 @@ func (s *GCPIAMService) setAccessInternal(identity *Identity, serviceID string,
 		Policy:   policy,
 	}
 
-	_, err = s.setResourcePolicy(s.ctx, resourceName, setPolicyReq)
+	_, err = s.setResourcePolicy(ctx, resourceName, setPolicyReq)
 	if err != nil {
 		return "", fmt.Errorf("failed to set IAM policy for %s: %w", resourceName, err)
 	}
@@ -289,7 +308,7 @@ func (s *GCPIAMService) setAccessInternal(identity *Identity, serviceID string,
 }
 
 // DestroyUser removes a service account and all associated resources
-func (s *GCPIAMService) DestroyUser(identity *Identity) error {
+func (s *GCPIAMService) DestroyUser(ctx context.Context, identity *Identity) error {
 	serviceAccountEmail := identity.Credentials["email"]
 	if serviceAccountEmail == "" {
 		return fmt.Errorf("service account email not found in identity credentials")
@@ -304,7 +323,7 @@ func (s *GCPIAMService) DestroyUser(identity *Identity) error {
 		Name: serviceAccountName,
 	}
 
-	keysResp, err := s.client.ListServiceAccountKeys(s.ctx, listKeysReq)
+	keysResp, err := s.client.ListServiceAccountKeys(ctx, listKeysReq)
 	if err != nil {
 		// If we can't list keys, the account might not exist - continue anyway
 		fmt.Printf("   ⚠️  Could not list keys: %v\n", err)
@@ -316,7 +335,7 @@ func (s *GCPIAMService) DestroyUser(identity *Identity) error {
 					Name: key.Name,
 				}
 
-				err := s.client.DeleteServiceAccountKey(s.ctx, deleteKeyReq)
+				err := s.client.DeleteServiceAccountKey(ctx, deleteKeyReq)
 				if err != nil {
 					fmt.Printf("   ⚠️  Failed to delete key %s: %v\n", key.Name, err)
 				}
@@ -329,20 +348,68 @@ func (s *GCPIAMService) DestroyUser(identity *Identity) error {
 		Name: serviceAccountName,
 	}
 
-	err = s.client.DeleteServiceAccount(s.ctx, deleteReq)
+	err = s.client.DeleteServiceAccount(ctx, deleteReq)
 	if err != nil {
 		// Check if account doesn't exist
 		if strings.Contains(err.Error(), "not found") {
 			fmt.Printf("   ℹ️  Service account already deleted\n")
+			journal.Deleted(s.instance, journal.KindServiceAccount, serviceAccountEmail)
 			return nil
 		}
 		return fmt.Errorf("failed to delete service account %s: %w", serviceAccountEmail, err)
 	}
 
 	fmt.Printf("   ✅ Service account deleted\n")
+	journal.Deleted(s.instance, journal.KindServiceAccount, serviceAccountEmail)
 	return nil
 }
 
+// Sweep deletes a service account journaled by an earlier run
+func (s *GCPIAMService) Sweep(ctx context.Context, entry journal.Entry) error {
+	if entry.Kind != journal.KindServiceAccount {
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	return s.DestroyUser(ctx, &Identity{
+		UserName:    entry.ID,
+		Provider:    "gcp",
+		Credentials: map[string]string{"email": entry.ID},
+	})
+}
+
+// serviceAccountDescription returns the service account description, carrying the run ID
+// since service accounts cannot be labelled
+func serviceAccountDescription() string {
+	description := "Created by CCC-CFI-Compliance-Framework for testing"
+	if runID := journal.RunID(); runID != "" {
+		description += fmt.Sprintf(" (%s=%s)", journal.TagKey, runID)
+	}
+	return description
+}
+
+// FindTagged lists the project's service accounts whose description carries a run ID
+func (s *GCPIAMService) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	var found []journal.Entry
+	it := s.client.ListServiceAccounts(ctx, &adminpb.ListServiceAccountsRequest{
+		Name: "projects/" + s.instance.Properties.GcpProjectId,
+	})
+	for {
+		account, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list service accounts: %w", err)
+		}
+		if match := descriptionRunID.FindStringSubmatch(account.Description); match != nil {
+			found = append(found, journal.Found(s.instance, "iam", journal.KindServiceAccount, account.Email, match[1]))
+		}
+	}
+	return found, nil
+}
+
+// descriptionRunID matches the run ID written by serviceAccountDescription
+var descriptionRunID = regexp.MustCompile(`\(` + journal.TagKey + `=([^)]+)\)`)
+
 // Helper functions
 
 func (s *GCPIAMService) getRoleForLevel(serviceID string, level string) (string, error) {
@@ -442,9 +509,9 @@ func sanitizeServiceAccountID(userName string) string {
 		sanitized = sanitized + "-test"
 	}
 
-	// Ensure maximum length
+	// Ensure maximum length, keeping the resource suffix added by resourceUserName
 	if len(sanitized) > 30 {
-		sanitized = sanitized[:30]
+		sanitized = sanitized[:21] + sanitized[len(sanitized)-9:]
 	}
 
 	// Remove trailing hyphens
@@ -454,38 +521,43 @@ func sanitizeServiceAccountID(userName string) string {
 }
 
 // Fill this later when we are writing tests for IAM
-func (s *GCPIAMService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *GCPIAMService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	return []types.TestParams{}, nil
 }
 
-func (s *GCPIAMService) CheckUserProvisioned() error {
+// DiscoverTestableResources returns no resources until IAM tests exist
+func (s *GCPIAMService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return []types.TestParams{}, nil
+}
+
+func (s *GCPIAMService) CheckUserProvisioned(ctx context.Context) error {
 	return nil
 }
 
 // ElevateAccessForInspection is a no-op for IAM services
-func (s *GCPIAMService) ElevateAccessForInspection() error {
+func (s *GCPIAMService) ElevateAccessForInspection(ctx context.Context) error {
 	// No-op: IAM services don't have network-level access controls to elevate
 	return nil
 }
 
 // ResetAccess is a no-op for IAM services
-func (s *GCPIAMService) ResetAccess() error {
+func (s *GCPIAMService) ResetAccess(ctx context.Context) error {
 	// No-op: IAM services don't have network-level access controls to reset
 	return nil
 }
 
 // UpdateResourcePolicy is not applicable for IAM service
-func (s *GCPIAMService) UpdateResourcePolicy() error {
+func (s *GCPIAMService) UpdateResourcePolicy(ctx context.Context) error {
 	return nil
 }
 
 // TriggerDataWrite is not applicable for IAM service
-func (s *GCPIAMService) TriggerDataWrite(resourceID string) error {
+func (s *GCPIAMService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not supported for IAM service")
 }
 
 // GetResourceRegion is not applicable for IAM service
-func (s *GCPIAMService) GetResourceRegion(resourceID string) (string, error) {
+func (s *GCPIAMService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not supported for IAM service")
 }
 
@@ -495,18 +567,22 @@ func (s *GCPIAMService) IsDataReplicatedToSeparateLocation(resourceID string) (b
 }
 
 // GetReplicationStatus is not applicable for IAM service
-func (s *GCPIAMService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *GCPIAMService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not supported for IAM service")
 }
 
 // TearDown removes all provisioned test users
-func (s *GCPIAMService) TearDown() error {
-	for userName, identity := range s.provisionedUsers {
-		if err := s.DestroyUser(identity); err != nil {
+func (s *GCPIAMService) TearDown(ctx context.Context) error {
+	s.mu.Lock()
+	users := s.provisionedUsers
+	s.provisionedUsers = make(map[string]*Identity)
+	s.accessLevels = make(map[string]string)
+	s.mu.Unlock()
+
+	for userName, identity := range users {
+		if err := s.DestroyUser(ctx, identity); err != nil {
 			fmt.Printf("⚠️  Failed to destroy user %s: %v\n", userName, err)
 		}
 	}
-	s.provisionedUsers = make(map[string]*Identity)
-	s.accessLevels = make(map[string]string)
 	return nil
 }
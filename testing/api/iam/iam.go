@@ -1,5 +1,13 @@
 package iam
 
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+)
+
 // AccessLevel defines the level of access for a service
 type AccessLevel string
 
@@ -14,13 +22,88 @@ type IAMService interface {
 	// ProvisionUserWithAccess creates a new user/identity in the cloud provider and sets their access level
 	// Includes propagation/retry logic to ensure credentials and permissions are active
 	// level specifies the access level: "none", "read", "write", or "admin"
-	ProvisionUserWithAccess(userName string, serviceID string, level string) (*Identity, error)
+	// Cloud providers create one user per userName and serviceID (see resourceUserName)
+	ProvisionUserWithAccess(ctx context.Context, userName string, serviceID string, level string) (*Identity, error)
 
 	// GetAccess retrieves the current access level for a user and service
 	// Returns the access level ("none", "read", "write", "admin"), the policy document JSON, and any error
-	GetAccess(identity *Identity, serviceID string) (string, string, error)
+	GetAccess(ctx context.Context, identity *Identity, serviceID string) (string, string, error)
 
 	// DestroyUser removes the identity and all associated access
 	// does nothing if the user does not exist
-	DestroyUser(identity *Identity) error
+	DestroyUser(ctx context.Context, identity *Identity) error
+}
+
+// resourceUserName scopes a test user name to the resource it is granted access on, so that
+// resources tested in parallel never re-key or re-grant each other's users. The suffix is a
+// short hash because resource IDs are too long for user names.
+func resourceUserName(userName string, serviceID string) string {
+	sum := sha256.Sum256([]byte(serviceID))
+	return fmt.Sprintf("%s-%x", userName, sum[:4])
+}
+
+// sharedIAMKey holds a factory's IAM service in its shared state: identity-scoped clients of
+// other services are provisioned and granted access through the same instance
+const sharedIAMKey = "iam"
+
+func init() {
+	ambient := func(create func(env generic.ServiceEnv) (generic.Service, error)) generic.ServiceConstructor {
+		return func(env generic.ServiceEnv) (generic.Service, error) {
+			v, err := env.Shared(sharedIAMKey, func() (interface{}, error) { return create(env) })
+			if err != nil {
+				return nil, err
+			}
+			return v.(generic.Service), nil
+		}
+	}
+	aws := ambient(func(env generic.ServiceEnv) (generic.Service, error) {
+		service, err := NewAWSIAMService(env.Ctx, *env.Instance)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	})
+	azure := ambient(func(env generic.ServiceEnv) (generic.Service, error) {
+		// Storage emulators (Azurite) have no Entra ID / RBAC
+		if endpoint := env.Instance.EndpointConfig("object-storage"); endpoint.ConnectionString != "" || endpoint.EndpointURL != "" {
+			return nil, fmt.Errorf("Azure IAM (RBAC) is %w in emulator mode", generic.ErrNotApplicable)
+		}
+		service, err := NewAzureIAMService(env.Ctx, *env.Instance)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	})
+	gcp := ambient(func(env generic.ServiceEnv) (generic.Service, error) {
+		// An unauthenticated emulator endpoint (fake-gcs-server) has no IAM to provision users in
+		if env.Instance.Properties.GcpProjectId == "" || env.Instance.EndpointConfig("object-storage").Unauthenticated {
+			return nil, fmt.Errorf("GCP IAM is %w without a project or against an unauthenticated endpoint", generic.ErrNotApplicable)
+		}
+		service, err := NewGCPIAMService(env.Ctx, *env.Instance)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	})
+	local := func(env generic.ServiceEnv) (generic.Service, error) {
+		return SharedLocalIAMService(env), nil
+	}
+
+	// The IAM service acts for the runner whichever identity asks for it
+	generic.RegisterService(generic.ServiceDefinition{
+		Name: "iam",
+		Providers: map[string]generic.ProviderImplementation{
+			"aws":   {New: aws, NewWithIdentity: aws},
+			"azure": {New: azure, NewWithIdentity: azure},
+			"gcp":   {New: gcp, NewWithIdentity: gcp},
+			"local": {New: local, NewWithIdentity: local},
+		},
+	})
+}
+
+// SharedLocalIAMService returns the in-memory IAM service shared by the local services of a
+// factory, so grants made through it apply to their identity-scoped clients
+func SharedLocalIAMService(env generic.ServiceEnv) *LocalIAMService {
+	v, _ := env.Shared(sharedIAMKey, func() (interface{}, error) { return NewLocalIAMService(*env.Instance), nil })
+	return v.(*LocalIAMService)
 }
@@ -1,9 +1,7 @@
 package iam
 
-// Identity represents the identity of a user or service principal
-type Identity struct {
-	UserName    string            // Username or principal name
-	Provider    string            // Cloud provider (aws, azure, gcp)
-	Credentials map[string]string // Provider-specific credentials
-	Policy      string            // IAM policy document (JSON) for the current access level
-}
+import "github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+
+// Identity represents the identity of a user or service principal. It is defined in
+// generic so that service constructors can receive it without importing this package.
+type Identity = generic.Identity
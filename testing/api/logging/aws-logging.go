@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
@@ -15,7 +16,6 @@ import (
 // AWSLoggingService implements Service for AWS CloudTrail
 type AWSLoggingService struct {
 	cloudTrailClient *cloudtrail.Client
-	ctx              context.Context
 	instance         types.InstanceConfig
 	cloudTrailName   string
 	cloudTrailCached bool
@@ -23,14 +23,15 @@ type AWSLoggingService struct {
 
 // NewAWSLoggingService creates a new AWS logging service using default credential chain
 func NewAWSLoggingService(ctx context.Context, instance *types.InstanceConfig) (*AWSLoggingService, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(instance.CloudParams().Region))
+	cfg, err := config.LoadDefaultConfig(ctx, generic.AWSLoadOptions(*instance, "logging",
+		config.WithRegion(instance.CloudParams().Region),
+	)...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &AWSLoggingService{
 		cloudTrailClient: cloudtrail.NewFromConfig(cfg),
-		ctx:              ctx,
 		instance:         *instance,
 	}, nil
 }
@@ -41,7 +42,7 @@ func NewAWSLoggingService(ctx context.Context, instance *types.InstanceConfig) (
 // 2. First multi-region trail found via CloudTrail API
 // 3. First trail found via CloudTrail API
 // Returns empty string if no trail is found
-func (s *AWSLoggingService) DiscoverCloudTrailName() string {
+func (s *AWSLoggingService) DiscoverCloudTrailName(ctx context.Context) string {
 	if s.cloudTrailCached {
 		return s.cloudTrailName
 	}
@@ -52,14 +53,14 @@ func (s *AWSLoggingService) DiscoverCloudTrailName() string {
 		return s.cloudTrailName
 	}
 
-	cfg, err := config.LoadDefaultConfig(s.ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, generic.AWSLoadOptions(s.instance, "logging")...)
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to load AWS config for CloudTrail discovery: %v\n", err)
 		return ""
 	}
 
 	client := cloudtrail.NewFromConfig(cfg)
-	result, err := client.DescribeTrails(s.ctx, &cloudtrail.DescribeTrailsInput{})
+	result, err := client.DescribeTrails(ctx, &cloudtrail.DescribeTrailsInput{})
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Failed to describe CloudTrail trails: %v\n", err)
 		return ""
@@ -87,8 +88,8 @@ func (s *AWSLoggingService) DiscoverCloudTrailName() string {
 }
 
 // GetOrProvisionTestableResources returns testable resources for the logging service
-func (s *AWSLoggingService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
-	trailName := s.DiscoverCloudTrailName()
+func (s *AWSLoggingService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	trailName := s.DiscoverCloudTrailName(ctx)
 
 	return []types.TestParams{
 		{
@@ -100,39 +101,75 @@ func (s *AWSLoggingService) GetOrProvisionTestableResources() ([]types.TestParam
 			UID:                 trailName,
 			ReportFile:          "cloudtrail-" + trailName,
 			ReportTitle:         "CloudTrail: " + trailName,
+			Labels:              s.trailLabels(ctx, trailName),
 			Instance:            s.instance,
 			Props:               map[string]interface{}{"AWSCloudTrailName": trailName},
 		},
 	}, nil
 }
 
+// trailLabels returns the trail's tags as labels. Tags that cannot be read are logged and
+// left out rather than failing discovery.
+func (s *AWSLoggingService) trailLabels(ctx context.Context, trailName string) []string {
+	if trailName == "" {
+		return nil
+	}
+	// ListTags takes the trail ARN, which an AWS_CLOUDTRAIL_NAME override does not give
+	trail, err := s.cloudTrailClient.GetTrail(ctx, &cloudtrail.GetTrailInput{Name: aws.String(trailName)})
+	if err != nil || trail.Trail == nil {
+		fmt.Printf("⚠️  Warning: Failed to get CloudTrail trail %s: %v\n", trailName, err)
+		return nil
+	}
+	output, err := s.cloudTrailClient.ListTags(ctx, &cloudtrail.ListTagsInput{
+		ResourceIdList: []string{aws.ToString(trail.Trail.TrailARN)},
+	})
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to get tags for CloudTrail trail %s: %v\n", trailName, err)
+		return nil
+	}
+
+	tags := make(map[string]string)
+	for _, resourceTags := range output.ResourceTagList {
+		for _, tag := range resourceTags.TagsList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return types.LabelsFromMap(tags)
+}
+
+// DiscoverTestableResources returns the same resources as GetOrProvisionTestableResources;
+// the logging service never provisions anything
+func (s *AWSLoggingService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return s.GetOrProvisionTestableResources(ctx)
+}
+
 // CheckUserProvisioned validates that the service's identity is properly provisioned
-func (s *AWSLoggingService) CheckUserProvisioned() error {
+func (s *AWSLoggingService) CheckUserProvisioned(ctx context.Context) error {
 	return nil
 }
 
 // ElevateAccessForInspection temporarily elevates access permissions
-func (s *AWSLoggingService) ElevateAccessForInspection() error {
+func (s *AWSLoggingService) ElevateAccessForInspection(ctx context.Context) error {
 	return nil
 }
 
 // ResetAccess restores the original access permissions
-func (s *AWSLoggingService) ResetAccess() error {
+func (s *AWSLoggingService) ResetAccess(ctx context.Context) error {
 	return nil
 }
 
 // UpdateResourcePolicy is not applicable for logging service
-func (s *AWSLoggingService) UpdateResourcePolicy() error {
+func (s *AWSLoggingService) UpdateResourcePolicy(ctx context.Context) error {
 	return nil
 }
 
 // TriggerDataWrite is not applicable for logging service
-func (s *AWSLoggingService) TriggerDataWrite(resourceID string) error {
+func (s *AWSLoggingService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not supported for logging service")
 }
 
 // GetResourceRegion is not applicable for logging service
-func (s *AWSLoggingService) GetResourceRegion(resourceID string) (string, error) {
+func (s *AWSLoggingService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not supported for logging service")
 }
 
@@ -142,31 +179,31 @@ func (s *AWSLoggingService) IsDataReplicatedToSeparateLocation(resourceID string
 }
 
 // GetReplicationStatus is not applicable for logging service
-func (s *AWSLoggingService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *AWSLoggingService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not supported for logging service")
 }
 
 // TearDown is a no-op for logging service (does not create resources)
-func (s *AWSLoggingService) TearDown() error {
+func (s *AWSLoggingService) TearDown(ctx context.Context) error {
 	return nil
 }
 
 // QueryAdminLogs queries CloudTrail for admin/management events
-func (s *AWSLoggingService) QueryAdminLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
-	return s.queryCloudTrailLogs(resourceID, lookbackMinutes, "management")
+func (s *AWSLoggingService) QueryAdminLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+	return s.queryCloudTrailLogs(ctx, resourceID, lookbackMinutes, "management")
 }
 
 // QueryDataWriteLogs queries CloudTrail for data write events
-func (s *AWSLoggingService) QueryDataWriteLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
-	return s.queryCloudTrailLogs(resourceID, lookbackMinutes, "data-write")
+func (s *AWSLoggingService) QueryDataWriteLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+	return s.queryCloudTrailLogs(ctx, resourceID, lookbackMinutes, "data-write")
 }
 
 // QueryDataReadLogs queries CloudTrail for data read events
-func (s *AWSLoggingService) QueryDataReadLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
-	return s.queryCloudTrailLogs(resourceID, lookbackMinutes, "data-read")
+func (s *AWSLoggingService) QueryDataReadLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+	return s.queryCloudTrailLogs(ctx, resourceID, lookbackMinutes, "data-read")
 }
 
-func (s *AWSLoggingService) queryCloudTrailLogs(resourceID string, lookbackMinutes int, eventType string) ([]LogEntry, error) {
+func (s *AWSLoggingService) queryCloudTrailLogs(ctx context.Context, resourceID string, lookbackMinutes int, eventType string) ([]LogEntry, error) {
 	startTime := time.Now().Add(-time.Duration(lookbackMinutes) * time.Minute)
 	endTime := time.Now()
 
@@ -175,7 +212,7 @@ func (s *AWSLoggingService) queryCloudTrailLogs(resourceID string, lookbackMinut
 		EndTime:   &endTime,
 	}
 
-	result, err := s.cloudTrailClient.LookupEvents(s.ctx, input)
+	result, err := s.cloudTrailClient.LookupEvents(ctx, input)
 	if err != nil {
 		return nil, err
 	}
@@ -202,3 +239,11 @@ func getString(s *string) string {
 	}
 	return *s
 }
+
+// SDKClient returns the CloudTrail client for policy sdk queries
+func (s *AWSLoggingService) SDKClient(ctx context.Context, name, resourceID string) (interface{}, error) {
+	if name != "cloudtrail" {
+		return nil, generic.UnknownSDKClient(s, name, "cloudtrail")
+	}
+	return s.cloudTrailClient, nil
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/monitor/azquery"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights"
@@ -24,36 +23,36 @@ type AzureLoggingService struct {
 	workspacesClient         *armoperationalinsights.WorkspacesClient
 	diagnosticSettingsClient *armmonitor.DiagnosticSettingsClient
 	credential               azcore.TokenCredential
-	ctx                      context.Context
 	instance                 types.InstanceConfig
 	workspaceIDCache         string
+	workspaceTags            map[string]*string
 	workspaceIDInit          sync.Once
 	workspaceIDInitErr       error
 }
 
 // NewAzureLoggingService creates a new Azure logging service using default credential chain
 func NewAzureLoggingService(ctx context.Context, instance *types.InstanceConfig) (*AzureLoggingService, error) {
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	cred, err := generic.AzureDefaultCredential()
 	if err != nil {
 		return nil, err
 	}
 
-	activityLogsClient, err := armmonitor.NewActivityLogsClient(instance.CloudParams().AzureSubscriptionID, cred, nil)
+	activityLogsClient, err := armmonitor.NewActivityLogsClient(instance.CloudParams().AzureSubscriptionID, cred, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, err
 	}
 
-	logsClient, err := azquery.NewLogsClient(cred, nil)
+	logsClient, err := azquery.NewLogsClient(cred, &azquery.LogsClientOptions{ClientOptions: generic.AzureClientOptions("azure-logs")})
 	if err != nil {
 		return nil, err
 	}
 
-	workspacesClient, err := armoperationalinsights.NewWorkspacesClient(instance.CloudParams().AzureSubscriptionID, cred, nil)
+	workspacesClient, err := armoperationalinsights.NewWorkspacesClient(instance.CloudParams().AzureSubscriptionID, cred, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, err
 	}
 
-	diagnosticSettingsClient, err := armmonitor.NewDiagnosticSettingsClient(cred, nil)
+	diagnosticSettingsClient, err := armmonitor.NewDiagnosticSettingsClient(cred, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, err
 	}
@@ -64,13 +63,12 @@ func NewAzureLoggingService(ctx context.Context, instance *types.InstanceConfig)
 		workspacesClient:         workspacesClient,
 		diagnosticSettingsClient: diagnosticSettingsClient,
 		credential:               cred,
-		ctx:                      ctx,
 		instance:                 *instance,
 	}, nil
 }
 
 // GetOrProvisionTestableResources returns testable resources for the logging service
-func (s *AzureLoggingService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *AzureLoggingService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	resourceName := "azure-monitor"
 	return []types.TestParams{
 		{
@@ -82,38 +80,55 @@ func (s *AzureLoggingService) GetOrProvisionTestableResources() ([]types.TestPar
 			UID:                 resourceName,
 			ReportFile:          "azure-monitor",
 			ReportTitle:         "Azure Monitor",
+			Labels:              s.workspaceLabels(ctx),
 			Instance:            s.instance,
 		},
 	}, nil
 }
 
+// workspaceLabels returns the tags of the Log Analytics workspace the logs are queried from.
+// A workspace that cannot be found is logged and gives no labels rather than failing discovery.
+func (s *AzureLoggingService) workspaceLabels(ctx context.Context) []string {
+	if _, err := s.getOrDiscoverWorkspaceID(ctx); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to get Log Analytics workspace tags: %v\n", err)
+		return nil
+	}
+	return generic.AzureTagLabels(s.workspaceTags)
+}
+
+// DiscoverTestableResources returns the same resources as GetOrProvisionTestableResources;
+// the logging service never provisions anything
+func (s *AzureLoggingService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return s.GetOrProvisionTestableResources(ctx)
+}
+
 // CheckUserProvisioned validates that the service's identity is properly provisioned
-func (s *AzureLoggingService) CheckUserProvisioned() error {
+func (s *AzureLoggingService) CheckUserProvisioned(ctx context.Context) error {
 	return nil
 }
 
 // ElevateAccessForInspection temporarily elevates access permissions
-func (s *AzureLoggingService) ElevateAccessForInspection() error {
+func (s *AzureLoggingService) ElevateAccessForInspection(ctx context.Context) error {
 	return nil
 }
 
 // ResetAccess restores the original access permissions
-func (s *AzureLoggingService) ResetAccess() error {
+func (s *AzureLoggingService) ResetAccess(ctx context.Context) error {
 	return nil
 }
 
 // UpdateResourcePolicy is not applicable for logging service
-func (s *AzureLoggingService) UpdateResourcePolicy() error {
+func (s *AzureLoggingService) UpdateResourcePolicy(ctx context.Context) error {
 	return nil
 }
 
 // TriggerDataWrite is not applicable for logging service
-func (s *AzureLoggingService) TriggerDataWrite(resourceID string) error {
+func (s *AzureLoggingService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not supported for logging service")
 }
 
 // GetResourceRegion is not applicable for logging service
-func (s *AzureLoggingService) GetResourceRegion(resourceID string) (string, error) {
+func (s *AzureLoggingService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not supported for logging service")
 }
 
@@ -123,23 +138,23 @@ func (s *AzureLoggingService) IsDataReplicatedToSeparateLocation(resourceID stri
 }
 
 // GetReplicationStatus is not applicable for logging service
-func (s *AzureLoggingService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *AzureLoggingService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not supported for logging service")
 }
 
 // TearDown is a no-op for logging service (does not create resources)
-func (s *AzureLoggingService) TearDown() error {
+func (s *AzureLoggingService) TearDown(ctx context.Context) error {
 	return nil
 }
 
 // QueryAdminLogs queries Azure Activity Log for admin events
-func (s *AzureLoggingService) QueryAdminLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *AzureLoggingService) QueryAdminLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() ([]LogEntry, error) {
-		return s.queryAdminLogs(resourceID, lookbackMinutes)
+		return s.queryAdminLogs(ctx, resourceID, lookbackMinutes)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureLoggingService) queryAdminLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *AzureLoggingService) queryAdminLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	startTime := time.Now().Add(-time.Duration(lookbackMinutes) * time.Minute)
 	endTime := time.Now()
 
@@ -154,7 +169,7 @@ func (s *AzureLoggingService) queryAdminLogs(resourceID string, lookbackMinutes
 
 	var entries []LogEntry
 	for pager.More() {
-		page, err := pager.NextPage(s.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get activity log page: %w", err)
 		}
@@ -182,17 +197,17 @@ func (s *AzureLoggingService) queryAdminLogs(resourceID string, lookbackMinutes
 
 // QueryDataWriteLogs queries Azure Log Analytics for storage write events
 // Note: Requires Diagnostic Settings configured to send StorageWrite logs to a Log Analytics workspace
-func (s *AzureLoggingService) QueryDataWriteLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *AzureLoggingService) QueryDataWriteLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() ([]LogEntry, error) {
-		return s.queryStorageLogs(resourceID, lookbackMinutes, "StorageWrite")
+		return s.queryStorageLogs(ctx, resourceID, lookbackMinutes, "StorageWrite")
 	}, retry.IsAzureRBACPropagationError)
 }
 
 // QueryDataReadLogs queries Azure Log Analytics for storage read events
 // Note: Requires Diagnostic Settings configured to send StorageRead logs to a Log Analytics workspace
-func (s *AzureLoggingService) QueryDataReadLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *AzureLoggingService) QueryDataReadLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() ([]LogEntry, error) {
-		return s.queryStorageLogs(resourceID, lookbackMinutes, "StorageRead")
+		return s.queryStorageLogs(ctx, resourceID, lookbackMinutes, "StorageRead")
 	}, retry.IsAzureRBACPropagationError)
 }
 
@@ -200,7 +215,7 @@ func (s *AzureLoggingService) QueryDataReadLogs(resourceID string, lookbackMinut
 // Discovery order: 1) workspace from
 // storage account diagnostic settings (where logs are actually sent); 2) first
 // workspace in the instance's resource group.
-func (s *AzureLoggingService) getOrDiscoverWorkspaceID() (string, error) {
+func (s *AzureLoggingService) getOrDiscoverWorkspaceID(ctx context.Context) (string, error) {
 	s.workspaceIDInit.Do(func() {
 		cp := s.instance.CloudParams()
 		rg := cp.AzureResourceGroup
@@ -210,19 +225,20 @@ func (s *AzureLoggingService) getOrDiscoverWorkspaceID() (string, error) {
 		}
 		// Try to get workspace from diagnostic settings on the storage account's blob service.
 		// This matches where the policy says logs are sent; workspace may be in a different RG.
-		storageAccount := serviceParamString(s.instance.ServiceProperties("object-storage"), "azure-storage-account")
+		storageAccount := s.instance.ObjectStorageConfig().AzureStorageAccount
 		if storageAccount != "" && cp.AzureSubscriptionID != "" {
 			blobServiceURI := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/%s/blobServices/default",
 				cp.AzureSubscriptionID, rg, storageAccount)
-			if customerID := s.workspaceFromDiagnosticSettings(blobServiceURI); customerID != "" {
-				s.workspaceIDCache = customerID
+			if w := s.workspaceFromDiagnosticSettings(ctx, blobServiceURI); w != nil {
+				s.workspaceIDCache = *w.Properties.CustomerID
+				s.workspaceTags = w.Tags
 				return
 			}
 		}
 		// Fallback: first workspace in the instance's resource group.
 		pager := s.workspacesClient.NewListByResourceGroupPager(rg, nil)
 		for pager.More() {
-			page, err := pager.NextPage(s.ctx)
+			page, err := pager.NextPage(ctx)
 			if err != nil {
 				s.workspaceIDInitErr = fmt.Errorf("failed to list Log Analytics workspaces: %w", err)
 				return
@@ -233,12 +249,13 @@ func (s *AzureLoggingService) getOrDiscoverWorkspaceID() (string, error) {
 			w := page.Value[0]
 			if w.Properties != nil && w.Properties.CustomerID != nil {
 				s.workspaceIDCache = *w.Properties.CustomerID
+				s.workspaceTags = w.Tags
 				return
 			}
 			break
 		}
 		if s.workspaceIDCache == "" {
-			s.workspaceIDInitErr = fmt.Errorf("ensure logs go to Log Analytics", rg)
+			s.workspaceIDInitErr = fmt.Errorf("no Log Analytics workspace found in resource group %s: ensure logs go to Log Analytics", rg)
 		}
 	})
 	if s.workspaceIDInitErr != nil {
@@ -248,31 +265,32 @@ func (s *AzureLoggingService) getOrDiscoverWorkspaceID() (string, error) {
 }
 
 // workspaceFromDiagnosticSettings lists diagnostic settings for the resource and returns
-// the CustomerID of the first workspace destination found, or "" if none.
-func (s *AzureLoggingService) workspaceFromDiagnosticSettings(resourceURI string) string {
+// the first workspace destination found that has a CustomerID, or nil if none.
+func (s *AzureLoggingService) workspaceFromDiagnosticSettings(ctx context.Context, resourceURI string) *armoperationalinsights.Workspace {
 	pager := s.diagnosticSettingsClient.NewListPager(resourceURI, nil)
 	for pager.More() {
-		page, err := pager.NextPage(s.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return ""
+			return nil
 		}
 		for _, ds := range page.Value {
 			if ds.Properties == nil || ds.Properties.WorkspaceID == nil || *ds.Properties.WorkspaceID == "" {
 				continue
 			}
 			workspaceARMID := *ds.Properties.WorkspaceID
-			customerID, err := s.customerIDFromWorkspaceARMID(workspaceARMID)
-			if err == nil && customerID != "" {
-				return customerID
+			w, err := s.workspaceFromARMID(ctx, workspaceARMID)
+			if err == nil {
+				return w
 			}
 		}
 	}
-	return ""
+	return nil
 }
 
-// customerIDFromWorkspaceARMID parses an ARM resource ID and fetches the workspace CustomerID.
+// workspaceFromARMID parses an ARM resource ID and fetches the workspace, which must have a
+// CustomerID.
 // Format: /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.OperationalInsights/workspaces/{name}
-func (s *AzureLoggingService) customerIDFromWorkspaceARMID(armID string) (string, error) {
+func (s *AzureLoggingService) workspaceFromARMID(ctx context.Context, armID string) (*armoperationalinsights.Workspace, error) {
 	parts := strings.Split(strings.Trim(armID, "/"), "/")
 	var resourceGroup, workspaceName string
 	for i := 0; i < len(parts)-1; i++ {
@@ -285,20 +303,20 @@ func (s *AzureLoggingService) customerIDFromWorkspaceARMID(armID string) (string
 		}
 	}
 	if resourceGroup == "" || workspaceName == "" {
-		return "", fmt.Errorf("invalid workspace ARM ID: %s", armID)
+		return nil, fmt.Errorf("invalid workspace ARM ID: %s", armID)
 	}
-	w, err := s.workspacesClient.Get(s.ctx, resourceGroup, workspaceName, nil)
+	w, err := s.workspacesClient.Get(ctx, resourceGroup, workspaceName, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if w.Properties == nil || w.Properties.CustomerID == nil {
-		return "", fmt.Errorf("workspace has no CustomerID")
+	if w.Properties == nil || w.Properties.CustomerID == nil || *w.Properties.CustomerID == "" {
+		return nil, fmt.Errorf("workspace has no CustomerID")
 	}
-	return *w.Properties.CustomerID, nil
+	return &w.Workspace, nil
 }
 
-func (s *AzureLoggingService) queryStorageLogs(resourceID string, lookbackMinutes int, category string) ([]LogEntry, error) {
-	workspaceID, err := s.getOrDiscoverWorkspaceID()
+func (s *AzureLoggingService) queryStorageLogs(ctx context.Context, resourceID string, lookbackMinutes int, category string) ([]LogEntry, error) {
+	workspaceID, err := s.getOrDiscoverWorkspaceID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -306,7 +324,7 @@ func (s *AzureLoggingService) queryStorageLogs(resourceID string, lookbackMinute
 		return []LogEntry{}, nil
 	}
 
-	storageAccount := serviceParamString(s.instance.ServiceProperties("object-storage"), "azure-storage-account")
+	storageAccount := s.instance.ObjectStorageConfig().AzureStorageAccount
 
 	kql := fmt.Sprintf(`StorageBlobLogs
 | where TimeGenerated >= ago(%dm)
@@ -317,7 +335,7 @@ func (s *AzureLoggingService) queryStorageLogs(resourceID string, lookbackMinute
 		lookbackMinutes, category, storageAccount)
 
 	query := azquery.Body{Query: &kql}
-	resp, err := s.logsClient.QueryWorkspace(s.ctx, workspaceID, query, nil)
+	resp, err := s.logsClient.QueryWorkspace(ctx, workspaceID, query, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query Log Analytics workspace: %w", err)
 	}
@@ -7,31 +7,35 @@ import (
 	"cloud.google.com/go/logging/logadmin"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+	"google.golang.org/api/cloudresourcemanager/v1"
 )
 
 // GCPLoggingService implements Service for GCP Cloud Audit Logs
 type GCPLoggingService struct {
 	logAdminClient *logadmin.Client
-	ctx            context.Context
 	instance       types.InstanceConfig
 }
 
 // NewGCPLoggingService creates a new GCP logging service
 func NewGCPLoggingService(ctx context.Context, instance *types.InstanceConfig) (*GCPLoggingService, error) {
-	client, err := logadmin.NewClient(ctx, instance.CloudParams().GcpProjectId)
+	opts, err := generic.GCPGRPCClientOptions("gcp-logging")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := logadmin.NewClient(ctx, instance.CloudParams().GcpProjectId, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &GCPLoggingService{
 		logAdminClient: client,
-		ctx:            ctx,
 		instance:       *instance,
 	}, nil
 }
 
 // GetOrProvisionTestableResources returns testable resources for the logging service
-func (s *GCPLoggingService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *GCPLoggingService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	resourceName := "cloud-audit-logs"
 	return []types.TestParams{
 		{
@@ -43,38 +47,67 @@ func (s *GCPLoggingService) GetOrProvisionTestableResources() ([]types.TestParam
 			UID:                 resourceName,
 			ReportFile:          "cloud-audit-logs",
 			ReportTitle:         "Cloud Audit Logs",
+			Labels:              s.projectLabels(ctx),
 			Instance:            s.instance,
 		},
 	}, nil
 }
 
+// projectLabels returns the labels of the project the audit logs belong to. Labels that
+// cannot be read are logged and left out rather than failing discovery.
+func (s *GCPLoggingService) projectLabels(ctx context.Context) []string {
+	projectID := s.instance.CloudParams().GcpProjectId
+	opts, err := generic.GCPClientOptions(ctx, "gcp-resourcemanager")
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to get labels for project %s: %v\n", projectID, err)
+		return nil
+	}
+	service, err := cloudresourcemanager.NewService(ctx, opts...)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to get labels for project %s: %v\n", projectID, err)
+		return nil
+	}
+	project, err := service.Projects.Get(projectID).Context(ctx).Do()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to get labels for project %s: %v\n", projectID, err)
+		return nil
+	}
+	return types.LabelsFromMap(project.Labels)
+}
+
+// DiscoverTestableResources returns the same resources as GetOrProvisionTestableResources;
+// the logging service never provisions anything
+func (s *GCPLoggingService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return s.GetOrProvisionTestableResources(ctx)
+}
+
 // CheckUserProvisioned validates that the service's identity is properly provisioned
-func (s *GCPLoggingService) CheckUserProvisioned() error {
+func (s *GCPLoggingService) CheckUserProvisioned(ctx context.Context) error {
 	return nil
 }
 
 // ElevateAccessForInspection temporarily elevates access permissions
-func (s *GCPLoggingService) ElevateAccessForInspection() error {
+func (s *GCPLoggingService) ElevateAccessForInspection(ctx context.Context) error {
 	return nil
 }
 
 // ResetAccess restores the original access permissions
-func (s *GCPLoggingService) ResetAccess() error {
+func (s *GCPLoggingService) ResetAccess(ctx context.Context) error {
 	return nil
 }
 
 // UpdateResourcePolicy is not applicable for logging service
-func (s *GCPLoggingService) UpdateResourcePolicy() error {
+func (s *GCPLoggingService) UpdateResourcePolicy(ctx context.Context) error {
 	return nil
 }
 
 // TriggerDataWrite is not applicable for logging service
-func (s *GCPLoggingService) TriggerDataWrite(resourceID string) error {
+func (s *GCPLoggingService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not supported for logging service")
 }
 
 // GetResourceRegion is not applicable for logging service
-func (s *GCPLoggingService) GetResourceRegion(resourceID string) (string, error) {
+func (s *GCPLoggingService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not supported for logging service")
 }
 
@@ -84,31 +117,31 @@ func (s *GCPLoggingService) IsDataReplicatedToSeparateLocation(resourceID string
 }
 
 // GetReplicationStatus is not applicable for logging service
-func (s *GCPLoggingService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *GCPLoggingService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not supported for logging service")
 }
 
 // TearDown is a no-op for logging service (does not create resources)
-func (s *GCPLoggingService) TearDown() error {
+func (s *GCPLoggingService) TearDown(ctx context.Context) error {
 	return nil
 }
 
 // QueryAdminLogs queries Cloud Audit Logs for admin activity events
-func (s *GCPLoggingService) QueryAdminLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *GCPLoggingService) QueryAdminLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	// TODO: Implement actual GCP Cloud Audit Logs querying
 	// Admin Activity audit logs are enabled by default in GCP
 	return []LogEntry{}, nil
 }
 
 // QueryDataWriteLogs queries Cloud Audit Logs for data write events
-func (s *GCPLoggingService) QueryDataWriteLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *GCPLoggingService) QueryDataWriteLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	// TODO: Implement actual GCP Cloud Audit Logs querying
 	// DATA_WRITE audit logs must be explicitly enabled in IAM policy
 	return []LogEntry{}, nil
 }
 
 // QueryDataReadLogs queries Cloud Audit Logs for data read events
-func (s *GCPLoggingService) QueryDataReadLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+func (s *GCPLoggingService) QueryDataReadLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
 	// TODO: Implement actual GCP Cloud Audit Logs querying
 	// DATA_READ audit logs must be explicitly enabled in IAM policy
 	return []LogEntry{}, nil
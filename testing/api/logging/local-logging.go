@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// Event types recorded in a LocalAuditLog
+const (
+	LocalEventAdmin     = "admin"
+	LocalEventDataWrite = "data-write"
+	LocalEventDataRead  = "data-read"
+)
+
+// localAuditEntry is a LogEntry plus the event type it is queried by
+type localAuditEntry struct {
+	eventType string
+	entry     LogEntry
+}
+
+// LocalAuditLog is the in-memory audit trail shared by the services of a local factory.
+// The local fakes record into it and LocalLoggingService queries it.
+type LocalAuditLog struct {
+	mu      sync.Mutex
+	entries []localAuditEntry
+}
+
+// NewLocalAuditLog creates an empty audit log
+func NewLocalAuditLog() *LocalAuditLog {
+	return &LocalAuditLog{}
+}
+
+// Record appends an event for the given resource
+func (l *LocalAuditLog) Record(eventType, identity, action, resource, result string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, localAuditEntry{
+		eventType: eventType,
+		entry: LogEntry{
+			Identity:  identity,
+			Action:    action,
+			Resource:  resource,
+			Timestamp: time.Now(),
+			Result:    result,
+		},
+	})
+}
+
+// query returns the entries of eventType for resourceID within the lookback window
+func (l *LocalAuditLog) query(eventType, resourceID string, lookbackMinutes int) []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	since := time.Now().Add(-time.Duration(lookbackMinutes) * time.Minute)
+	entries := []LogEntry{}
+	for _, e := range l.entries {
+		if e.eventType == eventType && e.entry.Resource == resourceID && !e.entry.Timestamp.Before(since) {
+			entries = append(entries, e.entry)
+		}
+	}
+	return entries
+}
+
+// LocalLoggingService implements Service over a LocalAuditLog for the offline "local" provider
+type LocalLoggingService struct {
+	instance types.InstanceConfig
+	auditLog *LocalAuditLog
+}
+
+// NewLocalLoggingService creates a logging service that queries the given audit log
+func NewLocalLoggingService(instance types.InstanceConfig, auditLog *LocalAuditLog) *LocalLoggingService {
+	return &LocalLoggingService{
+		instance: instance,
+		auditLog: auditLog,
+	}
+}
+
+// GetOrProvisionTestableResources returns testable resources for the logging service
+func (s *LocalLoggingService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	resourceName := "local-audit-log"
+	return []types.TestParams{
+		{
+			ServiceType:         "logging",
+			ProviderServiceType: "local:audit-log",
+			CatalogTypes:        []string{"CCC.Core"},
+			TagFilter:           []string{"@logging", "@PerService", "~@Policy"},
+			ResourceName:        resourceName,
+			UID:                 resourceName,
+			ReportFile:          resourceName,
+			ReportTitle:         "Local Audit Log",
+			Labels:              s.instance.LocalServiceConfig("logging").Labels,
+			Instance:            s.instance,
+		},
+	}, nil
+}
+
+// DiscoverTestableResources returns the same resources as GetOrProvisionTestableResources;
+// the logging service never provisions anything
+func (s *LocalLoggingService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return s.GetOrProvisionTestableResources(ctx)
+}
+
+// CheckUserProvisioned validates that the service's identity is properly provisioned
+func (s *LocalLoggingService) CheckUserProvisioned(ctx context.Context) error {
+	return nil
+}
+
+// ElevateAccessForInspection temporarily elevates access permissions
+func (s *LocalLoggingService) ElevateAccessForInspection(ctx context.Context) error {
+	return nil
+}
+
+// ResetAccess restores the original access permissions
+func (s *LocalLoggingService) ResetAccess(ctx context.Context) error {
+	return nil
+}
+
+// UpdateResourcePolicy is not applicable for logging service
+func (s *LocalLoggingService) UpdateResourcePolicy(ctx context.Context) error {
+	return nil
+}
+
+// TriggerDataWrite is not applicable for logging service
+func (s *LocalLoggingService) TriggerDataWrite(ctx context.Context, resourceID string) error {
+	return fmt.Errorf("not supported for logging service")
+}
+
+// GetResourceRegion is not applicable for logging service
+func (s *LocalLoggingService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
+	return "", fmt.Errorf("not supported for logging service")
+}
+
+// GetReplicationStatus is not applicable for logging service
+func (s *LocalLoggingService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
+	return nil, fmt.Errorf("not supported for logging service")
+}
+
+// TearDown is a no-op for logging service
+func (s *LocalLoggingService) TearDown(ctx context.Context) error {
+	return nil
+}
+
+// QueryAdminLogs returns recorded administrative events for the resource
+func (s *LocalLoggingService) QueryAdminLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+	return s.auditLog.query(LocalEventAdmin, resourceID, lookbackMinutes), nil
+}
+
+// QueryDataWriteLogs returns recorded data write events for the resource
+func (s *LocalLoggingService) QueryDataWriteLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+	return s.auditLog.query(LocalEventDataWrite, resourceID, lookbackMinutes), nil
+}
+
+// QueryDataReadLogs returns recorded data read events for the resource
+func (s *LocalLoggingService) QueryDataReadLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error) {
+	return s.auditLog.query(LocalEventDataRead, resourceID, lookbackMinutes), nil
+}
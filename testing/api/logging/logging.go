@@ -1,9 +1,11 @@
 package logging
 
 import (
+	"context"
 	"time"
 
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
 // LogEntry represents a log entry from cloud logging services (CloudTrail, Cloud Audit Logs, Azure Monitor)
@@ -15,19 +17,6 @@ type LogEntry struct {
 	Result    string    `json:"result"`    // Result/status of the action
 }
 
-// serviceParamString retrieves a string value from service params by camelCase key
-func serviceParamString(serviceParams map[string]interface{}, key string) string {
-	if serviceParams == nil {
-		return ""
-	}
-	if v, ok := serviceParams[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
-}
-
 // Service provides operations for cloud logging testing
 // This interface abstracts CloudTrail (AWS), Cloud Audit Logs (GCP), and Azure Monitor
 type Service interface {
@@ -35,13 +24,55 @@ type Service interface {
 
 	// QueryAdminLogs queries for administrative/management events
 	// Returns log entries for admin actions like resource creation, configuration changes
-	QueryAdminLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error)
+	QueryAdminLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error)
 
 	// QueryDataWriteLogs queries for data write events
 	// Returns log entries for data modification operations (create, update, delete)
-	QueryDataWriteLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error)
+	QueryDataWriteLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error)
 
 	// QueryDataReadLogs queries for data read events
 	// Returns log entries for data read operations
-	QueryDataReadLogs(resourceID string, lookbackMinutes int) ([]LogEntry, error)
+	QueryDataReadLogs(ctx context.Context, resourceID string, lookbackMinutes int) ([]LogEntry, error)
+}
+
+func init() {
+	generic.RegisterService(generic.ServiceDefinition{
+		Name:         "logging",
+		CatalogTypes: []string{"CCC.Core"},
+		TagFilter:    []string{"@logging", "@PerService"},
+		Config:       types.LoggingConfig{},
+		Providers: map[string]generic.ProviderImplementation{
+			"aws": {New: func(env generic.ServiceEnv) (generic.Service, error) {
+				service, err := NewAWSLoggingService(env.Ctx, env.Instance)
+				if err != nil {
+					return nil, err
+				}
+				return service, nil
+			}},
+			"azure": {New: func(env generic.ServiceEnv) (generic.Service, error) {
+				service, err := NewAzureLoggingService(env.Ctx, env.Instance)
+				if err != nil {
+					return nil, err
+				}
+				return service, nil
+			}},
+			"gcp": {New: func(env generic.ServiceEnv) (generic.Service, error) {
+				service, err := NewGCPLoggingService(env.Ctx, env.Instance)
+				if err != nil {
+					return nil, err
+				}
+				return service, nil
+			}},
+			"local": {New: func(env generic.ServiceEnv) (generic.Service, error) {
+				return NewLocalLoggingService(*env.Instance, SharedLocalAuditLog(env)), nil
+			}},
+		},
+	})
+}
+
+// SharedLocalAuditLog returns the audit log shared by the local services of a factory, so
+// events recorded by the fakes can be queried through the local logging service
+func SharedLocalAuditLog(env generic.ServiceEnv) *LocalAuditLog {
+	v, _ := env.Shared("local-audit-log", func() (interface{}, error) { return NewLocalAuditLog(), nil })
+	return v.(*LocalAuditLog)
 }
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -15,32 +17,36 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
 // AWSS3Service implements Service for AWS S3
 type AWSS3Service struct {
-	client     *s3.Client
-	config     aws.Config
-	ctx        context.Context
-	instance   types.InstanceConfig
-	createdObjs []struct{ bucket, object string }
-	createdMu   sync.Mutex
+	client          *s3.Client
+	config          aws.Config
+	instance        types.InstanceConfig
+	createdObjs     []struct{ bucket, object string }
+	createdMu       sync.Mutex
+	regionalClients map[string]*s3.Client // Clients by region, for reading the tags of buckets anywhere
+	regionalMu      sync.Mutex
 }
 
 // NewAWSS3Service creates a new AWS S3 service using default credentials
 func NewAWSS3Service(ctx context.Context, instance types.InstanceConfig) (*AWSS3Service, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(instance.Properties.Region))
+	cfg, err := config.LoadDefaultConfig(ctx, generic.AWSLoadOptions(instance, "object-storage",
+		config.WithRegion(instance.Properties.Region),
+	)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	return &AWSS3Service{
-		client:   s3.NewFromConfig(cfg),
+		client:   newS3Client(cfg, instance),
 		config:   cfg,
-		ctx:      ctx,
 		instance: instance,
 	}, nil
 }
@@ -58,29 +64,37 @@ func NewAWSS3ServiceWithCredentials(ctx context.Context, instance types.Instance
 	fmt.Printf("   Secret Key Length: %d\n", len(secretAccessKey))
 	fmt.Printf("   Has Session Token: %v\n", sessionToken != "")
 
-	cfg, err := config.LoadDefaultConfig(ctx,
+	cfg, err := config.LoadDefaultConfig(ctx, generic.AWSLoadOptions(instance, "object-storage",
 		config.WithRegion(instance.Properties.Region),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
 			accessKeyID,
 			secretAccessKey,
 			sessionToken,
 		)),
-	)
+	)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config with credentials: %w", err)
 	}
 
 	return &AWSS3Service{
-		client:   s3.NewFromConfig(cfg),
+		client:   newS3Client(cfg, instance),
 		config:   cfg,
-		ctx:      ctx,
 		instance: instance,
 	}, nil
 }
 
+// newS3Client creates an S3 client, using path-style addressing when the object-storage
+// service sets use-path-style (required by most S3-compatible emulators)
+func newS3Client(cfg aws.Config, instance types.InstanceConfig) *s3.Client {
+	usePathStyle := instance.EndpointConfig("object-storage").UsePathStyle
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = usePathStyle
+	})
+}
+
 // ListBuckets lists all S3 buckets
-func (s *AWSS3Service) ListBuckets() ([]Bucket, error) {
-	output, err := s.client.ListBuckets(s.ctx, &s3.ListBucketsInput{})
+func (s *AWSS3Service) ListBuckets(ctx context.Context) ([]Bucket, error) {
+	output, err := s.client.ListBuckets(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list buckets: %w", err)
 	}
@@ -90,7 +104,7 @@ func (s *AWSS3Service) ListBuckets() ([]Bucket, error) {
 		bucketName := aws.ToString(b.Name)
 
 		// Get the region for this bucket
-		region, err := s.GetBucketRegion(bucketName)
+		region, err := s.GetBucketRegion(ctx, bucketName)
 		if err != nil {
 			// If we can't get the region, log a warning but continue
 			fmt.Printf("⚠️  Warning: Failed to get region for bucket %s: %v\n", bucketName, err)
@@ -101,27 +115,90 @@ func (s *AWSS3Service) ListBuckets() ([]Bucket, error) {
 			ID:     bucketName,
 			Name:   bucketName,
 			Region: region,
+			Labels: s.bucketLabels(ctx, bucketName, region),
 		})
 	}
 
 	return buckets, nil
 }
 
+// bucketLabels returns the bucket's tags as labels. A bucket without tags has none; tags
+// that cannot be read are logged and left out rather than failing the listing.
+func (s *AWSS3Service) bucketLabels(ctx context.Context, bucketName, region string) []string {
+	return types.LabelsFromMap(s.bucketTags(ctx, bucketName, region))
+}
+
+// regionalClient returns the S3 client for region (the instance's region if empty), creating
+// it on first use so listing many buckets does not build a client per bucket
+func (s *AWSS3Service) regionalClient(region string) *s3.Client {
+	if region == "" {
+		region = s.instance.Properties.Region
+	}
+	s.regionalMu.Lock()
+	defer s.regionalMu.Unlock()
+	if client, ok := s.regionalClients[region]; ok {
+		return client
+	}
+	if s.regionalClients == nil {
+		s.regionalClients = make(map[string]*s3.Client)
+	}
+	regionalConfig := s.config.Copy()
+	regionalConfig.Region = region
+	client := newS3Client(regionalConfig, s.instance)
+	s.regionalClients[region] = client
+	return client
+}
+
+// bucketTags returns the bucket's tags, or nil (with a warning) if they cannot be read
+func (s *AWSS3Service) bucketTags(ctx context.Context, bucketName, region string) map[string]string {
+	output, err := s.regionalClient(region).GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "NoSuchTagSet" {
+			fmt.Printf("⚠️  Warning: Failed to get tags for bucket %s: %v\n", bucketName, err)
+		}
+		return nil
+	}
+
+	tags := make(map[string]string, len(output.TagSet))
+	for _, tag := range output.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
+
 // CreateBucket creates a new S3 bucket in the configured region
-func (s *AWSS3Service) CreateBucket(bucketID string) (*Bucket, error) {
+func (s *AWSS3Service) CreateBucket(ctx context.Context, bucketID string) (*Bucket, error) {
 	// Create a regional client
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = s.instance.Properties.Region
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
 	input := &s3.CreateBucketInput{
 		Bucket: aws.String(bucketID),
 	}
 
-	_, err := regionalClient.CreateBucket(s.ctx, input)
+	_, err := regionalClient.CreateBucket(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bucket %s: %w", bucketID, err)
 	}
+	journal.Created(s.instance, "object-storage", journal.KindBucket, bucketID, nil)
+
+	if tags := journal.Tags(); len(tags) > 0 {
+		tagSet := make([]s3types.Tag, 0, len(tags))
+		for key, value := range tags {
+			tagSet = append(tagSet, s3types.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+		_, err = regionalClient.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+			Bucket:  aws.String(bucketID),
+			Tagging: &s3types.Tagging{TagSet: tagSet},
+		})
+		if err != nil {
+			fmt.Printf("⚠️  Warning: Failed to tag bucket %s: %v\n", bucketID, err)
+		}
+	}
 
 	return &Bucket{
 		ID:     bucketID,
@@ -131,30 +208,31 @@ func (s *AWSS3Service) CreateBucket(bucketID string) (*Bucket, error) {
 }
 
 // DeleteBucket deletes an S3 bucket
-func (s *AWSS3Service) DeleteBucket(bucketID string) error {
+func (s *AWSS3Service) DeleteBucket(ctx context.Context, bucketID string) error {
 	// Create a regional client
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = s.instance.Properties.Region
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
-	_, err := regionalClient.DeleteBucket(s.ctx, &s3.DeleteBucketInput{
+	_, err := regionalClient.DeleteBucket(ctx, &s3.DeleteBucketInput{
 		Bucket: aws.String(bucketID),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete bucket %s: %w", bucketID, err)
 	}
+	journal.Deleted(s.instance, journal.KindBucket, bucketID)
 
 	return nil
 }
 
 // ListObjects lists all objects in a bucket
-func (s *AWSS3Service) ListObjects(bucketID string) ([]Object, error) {
+func (s *AWSS3Service) ListObjects(ctx context.Context, bucketID string) ([]Object, error) {
 	// Create a regional client
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = s.instance.Properties.Region
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
-	output, err := regionalClient.ListObjectsV2(s.ctx, &s3.ListObjectsV2Input{
+	output, err := regionalClient.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
 		Bucket: aws.String(bucketID),
 	})
 	if err != nil {
@@ -176,9 +254,9 @@ func (s *AWSS3Service) ListObjects(bucketID string) ([]Object, error) {
 }
 
 // CreateObject creates a new object in a bucket
-func (s *AWSS3Service) CreateObject(bucketID string, objectID string, data string) (*Object, error) {
+func (s *AWSS3Service) CreateObject(ctx context.Context, bucketID string, objectID string, data string) (*Object, error) {
 	// Get the bucket's actual region
-	bucketRegion, err := s.GetBucketRegion(bucketID)
+	bucketRegion, err := s.GetBucketRegion(ctx, bucketID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bucket region: %w", err)
 	}
@@ -186,19 +264,29 @@ func (s *AWSS3Service) CreateObject(bucketID string, objectID string, data strin
 	// Create a regional client for the bucket's region
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = bucketRegion
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
 	// Convert string to []byte
 	content := []byte(data)
 
-	putResult, err := regionalClient.PutObject(s.ctx, &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket: aws.String(bucketID),
 		Key:    aws.String(objectID),
 		Body:   bytes.NewReader(content),
-	})
+	}
+	if tags := journal.Tags(); len(tags) > 0 {
+		query := url.Values{}
+		for key, value := range tags {
+			query.Set(key, value)
+		}
+		input.Tagging = aws.String(query.Encode())
+	}
+
+	putResult, err := regionalClient.PutObject(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create object %s in bucket %s: %w", objectID, bucketID, err)
 	}
+	journal.Created(s.instance, "object-storage", journal.KindObject, bucketID+"/"+objectID, nil)
 
 	// Extract encryption information from response
 	encryption := string(putResult.ServerSideEncryption)
@@ -230,16 +318,16 @@ func (s *AWSS3Service) CreateObject(bucketID string, objectID string, data strin
 }
 
 // ReadObjectAtVersion reads a specific version of an object from a bucket
-func (s *AWSS3Service) ReadObjectAtVersion(bucketID string, objectID string, versionID string) (*Object, error) {
-	bucketRegion, err := s.GetBucketRegion(bucketID)
+func (s *AWSS3Service) ReadObjectAtVersion(ctx context.Context, bucketID string, objectID string, versionID string) (*Object, error) {
+	bucketRegion, err := s.GetBucketRegion(ctx, bucketID)
 	if err != nil {
 		return nil, err
 	}
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = bucketRegion
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
-	output, err := regionalClient.GetObject(s.ctx, &s3.GetObjectInput{
+	output, err := regionalClient.GetObject(ctx, &s3.GetObjectInput{
 		Bucket:    aws.String(bucketID),
 		Key:       aws.String(objectID),
 		VersionId: aws.String(versionID),
@@ -264,13 +352,13 @@ func (s *AWSS3Service) ReadObjectAtVersion(bucketID string, objectID string, ver
 }
 
 // ReadObject reads an object from a bucket
-func (s *AWSS3Service) ReadObject(bucketID string, objectID string) (*Object, error) {
+func (s *AWSS3Service) ReadObject(ctx context.Context, bucketID string, objectID string) (*Object, error) {
 	// Create a regional client
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = s.instance.Properties.Region
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
-	output, err := regionalClient.GetObject(s.ctx, &s3.GetObjectInput{
+	output, err := regionalClient.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucketID),
 		Key:    aws.String(objectID),
 	})
@@ -295,9 +383,9 @@ func (s *AWSS3Service) ReadObject(bucketID string, objectID string) (*Object, er
 }
 
 // DeleteObject deletes an object from a bucket
-func (s *AWSS3Service) DeleteObject(bucketID string, objectID string) error {
+func (s *AWSS3Service) DeleteObject(ctx context.Context, bucketID string, objectID string) error {
 	// Get the bucket's actual region
-	bucketRegion, err := s.GetBucketRegion(bucketID)
+	bucketRegion, err := s.GetBucketRegion(ctx, bucketID)
 	if err != nil {
 		return fmt.Errorf("failed to get bucket region: %w", err)
 	}
@@ -305,22 +393,86 @@ func (s *AWSS3Service) DeleteObject(bucketID string, objectID string) error {
 	// Create a regional client for the bucket's region
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = bucketRegion
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
-	_, err = regionalClient.DeleteObject(s.ctx, &s3.DeleteObjectInput{
+	_, err = regionalClient.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(bucketID),
 		Key:    aws.String(objectID),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete object %s from bucket %s: %w", objectID, bucketID, err)
 	}
+	journal.Deleted(s.instance, journal.KindObject, bucketID+"/"+objectID)
 
 	return nil
 }
 
+// Sweep deletes a bucket or object journaled by an earlier run. Buckets are emptied first,
+// since they were created by the run.
+func (s *AWSS3Service) Sweep(ctx context.Context, entry journal.Entry) error {
+	var err error
+	switch entry.Kind {
+	case journal.KindObject:
+		bucketID, objectID, _ := strings.Cut(entry.ID, "/")
+		err = s.DeleteObject(ctx, bucketID, objectID)
+	case journal.KindBucket:
+		var objects []Object
+		objects, err = s.ListObjects(ctx, entry.ID)
+		for _, obj := range objects {
+			if err = s.DeleteObject(ctx, entry.ID, obj.ID); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = s.DeleteBucket(ctx, entry.ID)
+		}
+	default:
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	if isS3NotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// FindTagged lists the buckets carrying the run-ID tag. Objects are only found through the
+// journal, since S3 returns object tags one object at a time.
+func (s *AWSS3Service) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	output, err := s.client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+
+	var found []journal.Entry
+	for _, b := range output.Buckets {
+		bucketName := aws.ToString(b.Name)
+		region, err := s.GetBucketRegion(ctx, bucketName)
+		if err != nil {
+			region = ""
+		}
+		if runID := s.bucketTags(ctx, bucketName, region)[journal.TagKey]; runID != "" {
+			found = append(found, journal.Found(s.instance, "object-storage", journal.KindBucket, bucketName, runID))
+		}
+	}
+	return found, nil
+}
+
+// isS3NotFoundError reports whether err means the bucket or object does not exist
+func isS3NotFoundError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "NoSuchBucket", "NoSuchKey", "NotFound":
+		return true
+	}
+	return false
+}
+
 // GetBucketRegion gets the region where a bucket is located
-func (s *AWSS3Service) GetBucketRegion(bucketID string) (string, error) {
-	output, err := s.client.GetBucketLocation(s.ctx, &s3.GetBucketLocationInput{
+func (s *AWSS3Service) GetBucketRegion(ctx context.Context, bucketID string) (string, error) {
+	output, err := s.client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 		Bucket: aws.String(bucketID),
 	})
 	if err != nil {
@@ -338,7 +490,7 @@ func (s *AWSS3Service) GetBucketRegion(bucketID string) (string, error) {
 
 // EnsureDefaultResourceExists ensures at least one S3 bucket exists for testing
 // Takes the result of ListBuckets() and creates a default bucket if none exist
-func (s *AWSS3Service) EnsureDefaultResourceExists(buckets []Bucket, err error) ([]Bucket, error) {
+func (s *AWSS3Service) EnsureDefaultResourceExists(ctx context.Context, buckets []Bucket, err error) ([]Bucket, error) {
 	// If there was an error listing buckets, return it
 	if err != nil {
 		return nil, err
@@ -353,7 +505,7 @@ func (s *AWSS3Service) EnsureDefaultResourceExists(buckets []Bucket, err error)
 	defaultBucketName := fmt.Sprintf("ccc-test-bucket-%s", strings.ToLower(s.instance.Properties.Region))
 	fmt.Printf("📦 No buckets found. Creating default test bucket: %s\n", defaultBucketName)
 
-	bucket, err := s.CreateBucket(defaultBucketName)
+	bucket, err := s.CreateBucket(ctx, defaultBucketName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create default bucket: %w", err)
 	}
@@ -363,14 +515,14 @@ func (s *AWSS3Service) EnsureDefaultResourceExists(buckets []Bucket, err error)
 }
 
 // GetBucketRetentionDurationDays retrieves the Object Lock retention duration in days for a bucket
-func (s *AWSS3Service) GetBucketRetentionDurationDays(bucketID string) (int, error) {
+func (s *AWSS3Service) GetBucketRetentionDurationDays(ctx context.Context, bucketID string) (int, error) {
 	// Create a regional client
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = s.instance.Properties.Region
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
 	// Get Object Lock configuration
-	lockConfig, err := regionalClient.GetObjectLockConfiguration(s.ctx, &s3.GetObjectLockConfigurationInput{
+	lockConfig, err := regionalClient.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
 		Bucket: aws.String(bucketID),
 	})
 	if err != nil {
@@ -397,20 +549,20 @@ func (s *AWSS3Service) GetBucketRetentionDurationDays(bucketID string) (int, err
 }
 
 // GetObjectRetentionDurationDays retrieves the Object Lock retention duration in days for a specific object
-func (s *AWSS3Service) GetObjectRetentionDurationDays(bucketID string, objectID string) (int, error) {
+func (s *AWSS3Service) GetObjectRetentionDurationDays(ctx context.Context, bucketID string, objectID string) (int, error) {
 	// Create a regional client
 	regionalConfig := s.config.Copy()
 	regionalConfig.Region = s.instance.Properties.Region
-	regionalClient := s3.NewFromConfig(regionalConfig)
+	regionalClient := newS3Client(regionalConfig, s.instance)
 
 	// Get object retention
-	retention, err := regionalClient.GetObjectRetention(s.ctx, &s3.GetObjectRetentionInput{
+	retention, err := regionalClient.GetObjectRetention(ctx, &s3.GetObjectRetentionInput{
 		Bucket: aws.String(bucketID),
 		Key:    aws.String(objectID),
 	})
 	if err != nil {
 		// No retention set on this object, check bucket default
-		return s.GetBucketRetentionDurationDays(bucketID)
+		return s.GetBucketRetentionDurationDays(ctx, bucketID)
 	}
 
 	// Calculate days until retention expires
@@ -429,15 +581,32 @@ func (s *AWSS3Service) GetObjectRetentionDurationDays(bucketID string, objectID
 // GetOrProvisionTestableResources returns all S3 buckets as testable resources
 // Returns two TestParams per bucket:
 // 1. PerService - for policy/configuration checks
-// 2. PerPort - for TLS/endpoint connectivity tests
-func (s *AWSS3Service) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+// 2. PerPort - for TLS/endpoint connectivity tests (https endpoints only)
+func (s *AWSS3Service) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	// List all buckets and ensure at least one exists
-	buckets, err := s.EnsureDefaultResourceExists(s.ListBuckets())
+	buckets, err := s.ListBuckets(ctx)
+	buckets, err = s.EnsureDefaultResourceExists(ctx, buckets, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+
+	return s.bucketTestParams(buckets), nil
+}
+
+// DiscoverTestableResources lists existing S3 buckets as testable resources without
+// creating a default bucket when none exist
+func (s *AWSS3Service) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	buckets, err := s.ListBuckets(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list buckets: %w", err)
 	}
 
-	// Convert buckets to TestParams (2 per bucket: service + port)
+	return s.bucketTestParams(buckets), nil
+}
+
+// bucketTestParams converts buckets to TestParams (2 per bucket: service + port, or only
+// service when the endpoint is not https)
+func (s *AWSS3Service) bucketTestParams(buckets []Bucket) []types.TestParams {
 	resources := make([]types.TestParams, 0, len(buckets)*2)
 	for _, bucket := range buckets {
 		// PerService: Resource-level tests (policy checks, configuration validation)
@@ -449,50 +618,79 @@ func (s *AWSS3Service) GetOrProvisionTestableResources() ([]types.TestParams, er
 			ProviderServiceType: "s3",
 			ServiceType:         "object-storage",
 			CatalogTypes:        []string{"CCC.ObjStor"},
+			Labels:              bucket.Labels,
 			TagFilter:           []string{"@object-storage", "@PerService"},
 			Instance:            s.instance,
 		})
 
-		// PerPort: Endpoint-level tests (TLS/SSL, port connectivity)
-		endpoint := fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket.Name, s.instance.Properties.Region)
+		// PerPort: Endpoint-level tests (TLS/SSL, port connectivity). An emulator endpoint-url
+		// serving plain HTTP has no TLS to test, so it gets none, as with Azurite.
+		endpoint, port, protocol := s.bucketEndpoint(bucket.Name)
+		if protocol != "https" {
+			continue
+		}
 		resources = append(resources, types.TestParams{
 			ResourceName:        bucket.Name,
 			UID:                 bucket.ID,
 			ReportFile:          fmt.Sprintf("%s-port", bucket.Name),
-			ReportTitle:         fmt.Sprintf("%s:443", endpoint),
+			ReportTitle:         fmt.Sprintf("%s:%s", endpoint, port),
 			HostName:            endpoint,
-			PortNumber:          "443",
-			Protocol:            "https",
+			PortNumber:          port,
+			Protocol:            protocol,
 			ProviderServiceType: "s3",
 			ServiceType:         "object-storage",
 			CatalogTypes:        []string{"CCC.ObjStor"},
+			Labels:              bucket.Labels,
 			TagFilter:           []string{"@object-storage", "@PerPort", "@tls", "~@ftp", "~@telnet", "~@ssh", "~@smtp", "~@dns", "~@ldap"},
 			Instance:            s.instance,
 		})
 	}
 
-	return resources, nil
+	return resources
+}
+
+// bucketEndpoint returns the host, port and protocol serving the bucket: the regional
+// virtual-hosted S3 endpoint, or the configured endpoint-url when one is set
+func (s *AWSS3Service) bucketEndpoint(bucketName string) (string, string, string) {
+	endpointURL := s.instance.EndpointConfig("object-storage").EndpointURL
+	if endpointURL == "" {
+		return fmt.Sprintf("%s.s3.%s.amazonaws.com", bucketName, s.instance.Properties.Region), "443", "https"
+	}
+
+	u, err := url.Parse(endpointURL)
+	if err != nil || u.Hostname() == "" {
+		fmt.Printf("⚠️  Warning: Invalid object-storage endpoint-url %q: %v\n", endpointURL, err)
+		return endpointURL, "443", "https"
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return u.Hostname(), port, u.Scheme
 }
 
 // CheckUserProvisioned validates that the given identity can access S3
 // For AWS, credentials are immediately usable, so this just attempts a simple S3 API call
-func (s *AWSS3Service) CheckUserProvisioned() error {
+func (s *AWSS3Service) CheckUserProvisioned(ctx context.Context) error {
 	// Try to list buckets as a validation that credentials work
-	_, err := s.client.ListBuckets(s.ctx, &s3.ListBucketsInput{})
+	_, err := s.client.ListBuckets(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return fmt.Errorf("credentials not ready for S3 access: %w", err)
 	}
 	return nil
 }
 
-func (s *AWSS3Service) ElevateAccessForInspection() error {
+func (s *AWSS3Service) ElevateAccessForInspection(ctx context.Context) error {
 	// No-op: AWS S3 access is managed through IAM policies, not network access
 	return nil
 }
 
 // SetObjectPermission attempts to set object-level permissions using S3 ACLs
 // If S3 bucket has ACLs disabled (uniform bucket-level access), this will fail
-func (s *AWSS3Service) SetObjectPermission(bucketID, objectID string, permissionLevel string) error {
+func (s *AWSS3Service) SetObjectPermission(ctx context.Context, bucketID, objectID string, permissionLevel string) error {
 	// Map permission level to S3 canned ACL
 	var acl string
 	switch permissionLevel {
@@ -508,7 +706,7 @@ func (s *AWSS3Service) SetObjectPermission(bucketID, objectID string, permission
 
 	// Attempt to set object-level ACL
 	// If bucket has ACLs disabled (enforcing uniform access), this will fail
-	_, err := s.client.PutObjectAcl(s.ctx, &s3.PutObjectAclInput{
+	_, err := s.client.PutObjectAcl(ctx, &s3.PutObjectAclInput{
 		Bucket: aws.String(bucketID),
 		Key:    aws.String(objectID),
 		ACL:    s3types.ObjectCannedACL(acl),
@@ -528,24 +726,24 @@ func (s *AWSS3Service) SetObjectPermission(bucketID, objectID string, permission
 
 // ListDeletedBuckets returns an error - AWS S3 does not support bucket-level soft delete
 // S3 bucket deletion is immediate and permanent (CN03.AR01 not supported)
-func (s *AWSS3Service) ListDeletedBuckets() ([]Bucket, error) {
+func (s *AWSS3Service) ListDeletedBuckets(ctx context.Context) ([]Bucket, error) {
 	return nil, fmt.Errorf("AWS S3 does not support bucket-level soft delete - bucket deletion is immediate and permanent")
 }
 
 // RestoreBucket returns an error - AWS S3 does not support bucket-level soft delete
 // S3 bucket deletion is immediate and permanent (CN03.AR01 not supported)
-func (s *AWSS3Service) RestoreBucket(bucketID string) error {
+func (s *AWSS3Service) RestoreBucket(ctx context.Context, bucketID string) error {
 	return fmt.Errorf("AWS S3 does not support bucket restoration - bucket deletion is immediate and permanent")
 }
 
 // SetBucketRetentionDurationDays returns an error - AWS S3 does not support bucket-level retention policies
 // S3 has Object Lock for object-level retention, but not bucket-level (CN03.AR02 not supported at bucket level)
-func (s *AWSS3Service) SetBucketRetentionDurationDays(bucketID string, days int) error {
+func (s *AWSS3Service) SetBucketRetentionDurationDays(ctx context.Context, bucketID string, days int) error {
 	return fmt.Errorf("AWS S3 does not support bucket-level retention policies - use Object Lock for object-level retention instead")
 }
 
 // ResetAccess is a no-op for AWS S3 (access is managed via IAM)
-func (s *AWSS3Service) ResetAccess() error {
+func (s *AWSS3Service) ResetAccess(ctx context.Context) error {
 	// No-op: AWS S3 access is managed through IAM policies, not network access
 	return nil
 }
@@ -553,9 +751,9 @@ func (s *AWSS3Service) ResetAccess() error {
 // UpdateResourcePolicy updates the bucket policy to trigger logging without functional changes.
 // It fetches the existing policy and modifies the SID field with a timestamp to ensure the
 // policy is "changed" from CloudTrail's perspective while keeping the actual permissions intact.
-func (s *AWSS3Service) UpdateResourcePolicy() error {
+func (s *AWSS3Service) UpdateResourcePolicy(ctx context.Context) error {
 	// Get the first bucket to update
-	buckets, err := s.ListBuckets()
+	buckets, err := s.ListBuckets(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list buckets: %w", err)
 	}
@@ -566,7 +764,7 @@ func (s *AWSS3Service) UpdateResourcePolicy() error {
 	bucketID := buckets[0].ID
 
 	// Get the existing bucket policy
-	getPolicyOutput, err := s.client.GetBucketPolicy(s.ctx, &s3.GetBucketPolicyInput{
+	getPolicyOutput, err := s.client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
 		Bucket: aws.String(bucketID),
 	})
 	if err != nil {
@@ -607,7 +805,7 @@ func (s *AWSS3Service) UpdateResourcePolicy() error {
 	}
 
 	// Put the modified policy back
-	_, err = s.client.PutBucketPolicy(s.ctx, &s3.PutBucketPolicyInput{
+	_, err = s.client.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
 		Bucket: aws.String(bucketID),
 		Policy: aws.String(string(modifiedPolicy)),
 	})
@@ -619,12 +817,12 @@ func (s *AWSS3Service) UpdateResourcePolicy() error {
 }
 
 // TriggerDataWrite performs a data modification to trigger logging (CN04.AR02)
-func (s *AWSS3Service) TriggerDataWrite(resourceID string) error {
+func (s *AWSS3Service) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not yet implemented")
 }
 
 // GetResourceRegion returns the bucket region (CN06.AR01)
-func (s *AWSS3Service) GetResourceRegion(resourceID string) (string, error) {
+func (s *AWSS3Service) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not yet implemented")
 }
 
@@ -635,12 +833,12 @@ func (s *AWSS3Service) IsDataReplicatedToSeparateLocation(resourceID string) (bo
 
 // GetReplicationStatus returns replication status including locations (CN08.AR01, CN08.AR02).
 // Populates ReplicationStatus with Locations (source + CRR destination regions), Status, SyncStatus.
-func (s *AWSS3Service) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *AWSS3Service) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not yet implemented")
 }
 
 // TearDown deletes objects created during testing
-func (s *AWSS3Service) TearDown() error {
+func (s *AWSS3Service) TearDown(ctx context.Context) error {
 	s.createdMu.Lock()
 	objs := make([]struct{ bucket, object string }, len(s.createdObjs))
 	copy(objs, s.createdObjs)
@@ -648,9 +846,25 @@ func (s *AWSS3Service) TearDown() error {
 	s.createdMu.Unlock()
 
 	for _, r := range objs {
-		if err := s.DeleteObject(r.bucket, r.object); err != nil {
+		if err := s.DeleteObject(ctx, r.bucket, r.object); err != nil {
 			fmt.Printf("   ⚠️  TearDown: could not delete %s/%s: %v\n", r.bucket, r.object, err)
 		}
 	}
 	return nil
 }
+
+// SDKClient returns the S3 client for policy sdk queries. The SDK does not follow S3's
+// region redirects, so queries about a bucket get the client for the bucket's region.
+func (s *AWSS3Service) SDKClient(ctx context.Context, name, resourceID string) (interface{}, error) {
+	if name != "s3" {
+		return nil, generic.UnknownSDKClient(s, name, "s3")
+	}
+	if resourceID == "" {
+		return s.client, nil
+	}
+	region, err := s.GetBucketRegion(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.regionalClient(region), nil
+}
@@ -3,18 +3,23 @@ package objstorage
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/retry"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/object-storage/elevation"
@@ -25,43 +30,60 @@ import (
 type AzureBlobService struct {
 	storageClient *armstorage.AccountsClient // For normal storage operations
 	credential    azcore.TokenCredential
-	ctx           context.Context
 	instance      *types.InstanceConfig
 	elevator      *elevation.AzureStorageElevator // Handles access elevation (RBAC + network)
+	endpoint      types.EndpointConfig            // Emulator (Azurite) endpoint overrides, if any
 	createdObjs   []struct{ bucket, object string }
 	createdMu     sync.Mutex
 }
 
-// storageAccountName returns the Azure storage account name from service params
+// emulator reports whether the service talks to a storage emulator such as Azurite rather
+// than Azure. Emulators only serve the blob data plane, so ARM operations are not applicable.
+func (s *AzureBlobService) emulator() bool {
+	return s.endpoint.ConnectionString != "" || s.endpoint.EndpointURL != ""
+}
+
+// notApplicableInEmulator returns the error reported by ARM-only operations in emulator mode
+func notApplicableInEmulator(operation string) error {
+	return fmt.Errorf("%s requires Azure Resource Manager and is %w in emulator mode", operation, generic.ErrNotApplicable)
+}
+
+// storageAccountName returns the Azure storage account name from the object-storage block
 func (s *AzureBlobService) storageAccountName() string {
-	return serviceParamString(s.instance.ServiceProperties("object-storage"), "azure-storage-account")
+	return s.instance.ObjectStorageConfig().AzureStorageAccount
 }
 
-// defaultContainerName returns the Azure default container name from service params
+// defaultContainerName returns the Azure default container name from the object-storage block
 func (s *AzureBlobService) defaultContainerName() string {
-	name := serviceParamString(s.instance.ServiceProperties("object-storage"), "default-container")
-	if name == "" {
-		return "ccc-test-container-2" // Fallback
-	}
-	return name
+	return s.instance.ObjectStorageConfig().DefaultContainer
 }
 
 // NewAzureBlobService creates a new Azure Blob Storage service using default credentials
 func NewAzureBlobService(ctx context.Context, instance *types.InstanceConfig) (*AzureBlobService, error) {
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	// Emulator mode: data plane only, no ARM client or elevator
+	endpoint := instance.EndpointConfig("object-storage")
+	if endpoint.ConnectionString != "" || endpoint.EndpointURL != "" {
+		fmt.Printf("🧪 Using Azure Blob Storage emulator endpoint (ARM operations not applicable)\n")
+		return &AzureBlobService{
+			instance: instance,
+			endpoint: endpoint,
+		}, nil
+	}
+
+	cred, err := generic.AzureDefaultCredential()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
 	// Create storage client for normal operations
-	storageClient, err := armstorage.NewAccountsClient(instance.CloudParams().AzureSubscriptionID, cred, nil)
+	storageClient, err := armstorage.NewAccountsClient(instance.CloudParams().AzureSubscriptionID, cred, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
 
 	// Create elevator for managing access controls (RBAC + network)
 	elevator, err := elevation.NewAzureStorageElevator(
-		ctx,
+		*instance,
 		cred,
 		instance.CloudParams().AzureSubscriptionID,
 		instance.CloudParams().AzureResourceGroup,
@@ -73,7 +95,6 @@ func NewAzureBlobService(ctx context.Context, instance *types.InstanceConfig) (*
 	return &AzureBlobService{
 		storageClient: storageClient,
 		credential:    cred,
-		ctx:           ctx,
 		instance:      instance,
 		elevator:      elevator,
 	}, nil
@@ -81,6 +102,11 @@ func NewAzureBlobService(ctx context.Context, instance *types.InstanceConfig) (*
 
 // NewAzureBlobServiceWithCredentials creates a new Azure Blob Storage service with service principal credentials
 func NewAzureBlobServiceWithCredentials(ctx context.Context, cloudParams types.CloudParams, instance types.InstanceConfig, identity *iam.Identity) (*AzureBlobService, error) {
+	endpoint := instance.EndpointConfig("object-storage")
+	if endpoint.ConnectionString != "" || endpoint.EndpointURL != "" {
+		return nil, notApplicableInEmulator("service principal (RBAC) access")
+	}
+
 	// Extract service principal credentials
 	clientID := identity.Credentials["client_id"]
 	if clientID == "" {
@@ -102,20 +128,20 @@ func NewAzureBlobServiceWithCredentials(ctx context.Context, cloudParams types.C
 	fmt.Printf("   Tenant ID: %s\n", tenantID)
 
 	// Create service principal credential
-	cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	cred, err := generic.AzureClientSecretCredential(tenantID, clientID, clientSecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create service principal credential: %w", err)
 	}
 
 	// Create storage client for normal operations
-	storageClient, err := armstorage.NewAccountsClient(cloudParams.AzureSubscriptionID, cred, nil)
+	storageClient, err := armstorage.NewAccountsClient(cloudParams.AzureSubscriptionID, cred, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
 
 	// Create elevator for managing access controls (RBAC + network)
 	elevator, err := elevation.NewAzureStorageElevator(
-		ctx,
+		instance,
 		cred,
 		cloudParams.AzureSubscriptionID,
 		cloudParams.AzureResourceGroup,
@@ -127,7 +153,6 @@ func NewAzureBlobServiceWithCredentials(ctx context.Context, cloudParams types.C
 	return &AzureBlobService{
 		storageClient: storageClient,
 		credential:    cred,
-		ctx:           ctx,
 		instance:      &instance,
 		elevator:      elevator,
 	}, nil
@@ -135,32 +160,37 @@ func NewAzureBlobServiceWithCredentials(ctx context.Context, cloudParams types.C
 
 // ListBuckets lists all containers in the identified storage account
 // In Azure, a "bucket" is represented as "resourceGroup/storageAccount/containerName"
-func (s *AzureBlobService) ListBuckets() ([]Bucket, error) {
+func (s *AzureBlobService) ListBuckets(ctx context.Context) ([]Bucket, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() ([]Bucket, error) {
-		return s.listBuckets()
+		return s.listBuckets(ctx)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) listBuckets() ([]Bucket, error) {
+func (s *AzureBlobService) listBuckets(ctx context.Context) ([]Bucket, error) {
 	storageAccountName := s.storageAccountName()
 	fmt.Printf("📦 Using storage account: %s\n", storageAccountName)
 
 	buckets := []Bucket{}
 	resourceGroup := s.instance.Properties.AzureResourceGroup
 
-	// Get the storage account location
-	account, err := s.storageClient.GetProperties(s.ctx, resourceGroup, storageAccountName, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get storage account properties: %w", err)
-	}
-
+	// Get the storage account location and tags (the emulator has no ARM, so use the
+	// configured region; its containers have no tags)
 	location := s.instance.Properties.Region
-	if account.Location != nil {
-		location = *account.Location
+	var labels []string
+	if !s.emulator() {
+		account, err := s.storageClient.GetProperties(ctx, resourceGroup, storageAccountName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage account properties: %w", err)
+		}
+		if account.Location != nil {
+			location = *account.Location
+		}
+		// Containers carry the tags of their storage account
+		labels = generic.AzureTagLabels(account.Tags)
 	}
 
 	// List containers in the storage account
-	containers, err := s.listContainersForAccount(storageAccountName)
+	containers, err := s.listContainersForAccount(ctx, storageAccountName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers for %s: %w", storageAccountName, err)
 	}
@@ -171,6 +201,7 @@ func (s *AzureBlobService) listBuckets() ([]Bucket, error) {
 			ID:     containerName,
 			Name:   containerName,
 			Region: location,
+			Labels: labels,
 		})
 	}
 
@@ -179,19 +210,19 @@ func (s *AzureBlobService) listBuckets() ([]Bucket, error) {
 
 // CreateBucket creates a new container in the storage account
 // bucketID is the container name
-func (s *AzureBlobService) CreateBucket(bucketID string) (*Bucket, error) {
+func (s *AzureBlobService) CreateBucket(ctx context.Context, bucketID string) (*Bucket, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (*Bucket, error) {
-		return s.createBucket(bucketID)
+		return s.createBucket(ctx, bucketID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) createBucket(bucketID string) (*Bucket, error) {
+func (s *AzureBlobService) createBucket(ctx context.Context, bucketID string) (*Bucket, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 	fmt.Printf("📦 Creating container %s in storage account %s...\n", containerName, storageAccountName)
 
 	// Create container in the existing storage account
-	err := s.createContainer(storageAccountName, containerName)
+	err := s.createContainer(ctx, storageAccountName, containerName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
@@ -207,23 +238,26 @@ func (s *AzureBlobService) createBucket(bucketID string) (*Bucket, error) {
 
 // DeleteBucket deletes a container from the storage account
 // bucketID is the container name
-func (s *AzureBlobService) DeleteBucket(bucketID string) error {
+func (s *AzureBlobService) DeleteBucket(ctx context.Context, bucketID string) error {
 	return retry.DoVoid(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() error {
-		return s.deleteBucket(bucketID)
+		return s.deleteBucket(ctx, bucketID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) deleteBucket(bucketID string) error {
+func (s *AzureBlobService) deleteBucket(ctx context.Context, bucketID string) error {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 	fmt.Printf("🗑️  Deleting container %s from storage account %s...\n", containerName, storageAccountName)
-	return s.deleteContainer(storageAccountName, containerName)
+	return s.deleteContainer(ctx, storageAccountName, containerName)
 }
 
 // GetBucketRegion returns the region where the storage account is located
-func (s *AzureBlobService) GetBucketRegion(bucketID string) (string, error) {
+func (s *AzureBlobService) GetBucketRegion(ctx context.Context, bucketID string) (string, error) {
+	if s.emulator() {
+		return "", notApplicableInEmulator("storage account location")
+	}
 	storageAccountName := s.storageAccountName()
-	account, err := s.storageClient.GetProperties(s.ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, nil)
+	account, err := s.storageClient.GetProperties(ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -237,13 +271,13 @@ func (s *AzureBlobService) GetBucketRegion(bucketID string) (string, error) {
 
 // ListObjects lists all blobs in a container
 // bucketID is the container name
-func (s *AzureBlobService) ListObjects(bucketID string) ([]Object, error) {
+func (s *AzureBlobService) ListObjects(ctx context.Context, bucketID string) ([]Object, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() ([]Object, error) {
-		return s.listObjects(bucketID)
+		return s.listObjects(ctx, bucketID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) listObjects(bucketID string) ([]Object, error) {
+func (s *AzureBlobService) listObjects(ctx context.Context, bucketID string) ([]Object, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -258,7 +292,7 @@ func (s *AzureBlobService) listObjects(bucketID string) ([]Object, error) {
 
 	objects := []Object{}
 	for pager.More() {
-		page, err := pager.NextPage(s.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list blobs: %w", err)
 		}
@@ -288,13 +322,13 @@ func (s *AzureBlobService) listObjects(bucketID string) ([]Object, error) {
 
 // CreateObject creates a new blob in a container
 // bucketID is the container name
-func (s *AzureBlobService) CreateObject(bucketID string, objectID string, data string) (*Object, error) {
+func (s *AzureBlobService) CreateObject(ctx context.Context, bucketID string, objectID string, data string) (*Object, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (*Object, error) {
-		return s.createObject(bucketID, objectID, data)
+		return s.createObject(ctx, bucketID, objectID, data)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) createObject(bucketID string, objectID string, data string) (*Object, error) {
+func (s *AzureBlobService) createObject(ctx context.Context, bucketID string, objectID string, data string) (*Object, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -311,10 +345,13 @@ func (s *AzureBlobService) createObject(bucketID string, objectID string, data s
 	blockBlobClient := containerClient.NewBlockBlobClient(objectID)
 
 	// Upload blob
-	uploadResp, err := blockBlobClient.UploadStream(s.ctx, bytes.NewReader(content), nil)
+	uploadResp, err := blockBlobClient.UploadStream(ctx, bytes.NewReader(content), &blockblob.UploadStreamOptions{
+		Metadata: journalMetadata(),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload blob %s: %w", objectID, err)
 	}
+	journal.Created(*s.instance, "object-storage", journal.KindObject, bucketID+"/"+objectID, nil)
 
 	// Azure encrypts all blobs by default
 	// Check if the response indicates encryption (IsServerEncrypted)
@@ -351,13 +388,13 @@ func (s *AzureBlobService) createObject(bucketID string, objectID string, data s
 }
 
 // ReadObjectAtVersion reads a specific version of a blob from a container
-func (s *AzureBlobService) ReadObjectAtVersion(bucketID string, objectID string, versionID string) (*Object, error) {
+func (s *AzureBlobService) ReadObjectAtVersion(ctx context.Context, bucketID string, objectID string, versionID string) (*Object, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (*Object, error) {
-		return s.readObjectAtVersion(bucketID, objectID, versionID)
+		return s.readObjectAtVersion(ctx, bucketID, objectID, versionID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) readObjectAtVersion(bucketID string, objectID string, versionID string) (*Object, error) {
+func (s *AzureBlobService) readObjectAtVersion(ctx context.Context, bucketID string, objectID string, versionID string) (*Object, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -373,7 +410,7 @@ func (s *AzureBlobService) readObjectAtVersion(bucketID string, objectID string,
 		return nil, fmt.Errorf("failed to create versioned blob client: %w", err)
 	}
 
-	downloadResponse, err := versionedBlobClient.DownloadStream(s.ctx, nil)
+	downloadResponse, err := versionedBlobClient.DownloadStream(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download blob %s version %s: %w", objectID, versionID, err)
 	}
@@ -400,13 +437,13 @@ func (s *AzureBlobService) readObjectAtVersion(bucketID string, objectID string,
 
 // ReadObject reads a blob from a container
 // bucketID is the container name
-func (s *AzureBlobService) ReadObject(bucketID string, objectID string) (*Object, error) {
+func (s *AzureBlobService) ReadObject(ctx context.Context, bucketID string, objectID string) (*Object, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (*Object, error) {
-		return s.readObject(bucketID, objectID)
+		return s.readObject(ctx, bucketID, objectID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) readObject(bucketID string, objectID string) (*Object, error) {
+func (s *AzureBlobService) readObject(ctx context.Context, bucketID string, objectID string) (*Object, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -420,7 +457,7 @@ func (s *AzureBlobService) readObject(bucketID string, objectID string) (*Object
 	blockBlobClient := containerClient.NewBlockBlobClient(objectID)
 
 	// Download blob
-	downloadResponse, err := blockBlobClient.DownloadStream(s.ctx, nil)
+	downloadResponse, err := blockBlobClient.DownloadStream(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download blob %s: %w", objectID, err)
 	}
@@ -448,13 +485,13 @@ func (s *AzureBlobService) readObject(bucketID string, objectID string) (*Object
 
 // DeleteObject deletes a blob from a container
 // bucketID is the container name
-func (s *AzureBlobService) DeleteObject(bucketID string, objectID string) error {
+func (s *AzureBlobService) DeleteObject(ctx context.Context, bucketID string, objectID string) error {
 	return retry.DoVoid(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() error {
-		return s.deleteObject(bucketID, objectID)
+		return s.deleteObject(ctx, bucketID, objectID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) deleteObject(bucketID string, objectID string) error {
+func (s *AzureBlobService) deleteObject(ctx context.Context, bucketID string, objectID string) error {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -468,23 +505,137 @@ func (s *AzureBlobService) deleteObject(bucketID string, objectID string) error
 	blockBlobClient := containerClient.NewBlockBlobClient(objectID)
 
 	// Delete blob
-	_, err = blockBlobClient.Delete(s.ctx, nil)
+	_, err = blockBlobClient.Delete(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete blob %s: %w", objectID, err)
 	}
+	journal.Deleted(*s.instance, journal.KindObject, bucketID+"/"+objectID)
 
 	return nil
 }
 
+// Sweep deletes a container, blob or elevation role assignment journaled by an earlier run
+func (s *AzureBlobService) Sweep(ctx context.Context, entry journal.Entry) error {
+	var err error
+	switch entry.Kind {
+	case journal.KindObject:
+		bucketID, objectID, _ := strings.Cut(entry.ID, "/")
+		err = s.DeleteObject(ctx, bucketID, objectID)
+	case journal.KindBucket:
+		// Deleting a container deletes its blobs
+		err = s.DeleteBucket(ctx, entry.ID)
+	case journal.KindRoleAssignment:
+		return s.elevator.DeleteRoleAssignment(ctx, entry.ID)
+	default:
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	if bloberror.HasCode(err, bloberror.ContainerNotFound, bloberror.BlobNotFound, bloberror.ContainerBeingDeleted) {
+		return nil
+	}
+	return err
+}
+
+// journalMetadata returns the run-ID tags as container or blob metadata. Metadata names
+// must be valid C# identifiers, so hyphens become underscores.
+func journalMetadata() map[string]*string {
+	metadata := map[string]*string{}
+	for key, value := range journal.Tags() {
+		value := value
+		metadata[strings.ReplaceAll(key, "-", "_")] = &value
+	}
+	return metadata
+}
+
+// FindTagged lists the containers, and the blobs in other containers, whose metadata carries
+// the run-ID tag
+func (s *AzureBlobService) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	blobClient, err := s.getBlobServiceClient(s.storageAccountName())
+	if err != nil {
+		return nil, err
+	}
+
+	var found []journal.Entry
+	pager := blobClient.NewListContainersPager(&azblob.ListContainersOptions{
+		Include: azblob.ListContainersInclude{Metadata: true},
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list containers: %w", err)
+		}
+		for _, cont := range page.ContainerItems {
+			if cont.Name == nil {
+				continue
+			}
+			if runID := metadataRunID(cont.Metadata); runID != "" {
+				found = append(found, journal.Found(*s.instance, "object-storage", journal.KindBucket, *cont.Name, runID))
+				continue
+			}
+			blobPager := blobClient.NewListBlobsFlatPager(*cont.Name, &azblob.ListBlobsFlatOptions{
+				Include: azblob.ListBlobsInclude{Metadata: true},
+			})
+			for blobPager.More() {
+				blobPage, err := blobPager.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list blobs in container %s: %w", *cont.Name, err)
+				}
+				for _, blob := range blobPage.Segment.BlobItems {
+					if runID := metadataRunID(blob.Metadata); blob.Name != nil && runID != "" {
+						found = append(found, journal.Found(*s.instance, "object-storage", journal.KindObject, *cont.Name+"/"+*blob.Name, runID))
+					}
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
+// metadataRunID returns the run ID written by journalMetadata, if any. The service may
+// change the case of metadata names, so they are compared case-insensitively.
+func metadataRunID(metadata map[string]*string) string {
+	for key, value := range metadata {
+		if value != nil && strings.EqualFold(key, strings.ReplaceAll(journal.TagKey, "-", "_")) {
+			return *value
+		}
+	}
+	return ""
+}
+
 // Helper functions
 
-// getBlobServiceClient creates a blob service client for a storage account
+// getBlobServiceClient creates a blob service client for a storage account.
+// In emulator mode the connection string or endpoint-url is used instead of the public endpoint.
 func (s *AzureBlobService) getBlobServiceClient(storageAccountName string) (*azblob.Client, error) {
-	// Construct the blob service URL
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
+	var base http.RoundTripper
+	if s.endpoint.InsecureSkipVerify {
+		base = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	options := &azblob.ClientOptions{}
+	if base != nil || recorder.Enabled() {
+		options.Transport = &http.Client{Transport: recorder.Transport("azure-blob", base)}
+	}
+
+	var client *azblob.Client
+	var err error
+	switch {
+	case s.endpoint.ConnectionString != "":
+		client, err = azblob.NewClientFromConnectionString(s.endpoint.ConnectionString, options)
+
+	case s.endpoint.EndpointURL != "" && s.endpoint.AccountKey != "":
+		cred, credErr := azblob.NewSharedKeyCredential(storageAccountName, s.endpoint.AccountKey)
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create shared key credential: %w", credErr)
+		}
+		client, err = azblob.NewClientWithSharedKeyCredential(s.endpoint.EndpointURL, cred, options)
 
-	// Create blob client with Azure AD authentication
-	client, err := azblob.NewClient(serviceURL, s.credential, nil)
+	case s.endpoint.EndpointURL != "":
+		client, err = azblob.NewClientWithNoCredential(s.endpoint.EndpointURL, options)
+
+	default:
+		// Construct the blob service URL and authenticate with Azure AD
+		serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
+		client, err = azblob.NewClient(serviceURL, s.credential, options)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create blob client: %w", err)
 	}
@@ -493,7 +644,7 @@ func (s *AzureBlobService) getBlobServiceClient(storageAccountName string) (*azb
 }
 
 // listContainersForAccount lists all containers in a storage account
-func (s *AzureBlobService) listContainersForAccount(storageAccountName string) ([]string, error) {
+func (s *AzureBlobService) listContainersForAccount(ctx context.Context, storageAccountName string) ([]string, error) {
 	blobClient, err := s.getBlobServiceClient(storageAccountName)
 	if err != nil {
 		return nil, err
@@ -503,7 +654,7 @@ func (s *AzureBlobService) listContainersForAccount(storageAccountName string) (
 	pager := blobClient.NewListContainersPager(nil)
 
 	for pager.More() {
-		page, err := pager.NextPage(s.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list containers: %w", err)
 		}
@@ -519,14 +670,14 @@ func (s *AzureBlobService) listContainersForAccount(storageAccountName string) (
 }
 
 // createContainer creates a new container in a storage account
-func (s *AzureBlobService) createContainer(storageAccountName, containerName string) error {
+func (s *AzureBlobService) createContainer(ctx context.Context, storageAccountName, containerName string) error {
 	blobClient, err := s.getBlobServiceClient(storageAccountName)
 	if err != nil {
 		return err
 	}
 
 	containerClient := blobClient.ServiceClient().NewContainerClient(containerName)
-	_, err = containerClient.Create(s.ctx, &container.CreateOptions{})
+	_, err = containerClient.Create(ctx, &container.CreateOptions{Metadata: journalMetadata()})
 	if err != nil {
 		// Check if container already exists
 		if strings.Contains(err.Error(), "ContainerAlreadyExists") {
@@ -535,29 +686,31 @@ func (s *AzureBlobService) createContainer(storageAccountName, containerName str
 		}
 		return fmt.Errorf("failed to create container %s: %w", containerName, err)
 	}
+	journal.Created(*s.instance, "object-storage", journal.KindBucket, containerName, nil)
 
 	return nil
 }
 
 // deleteContainer deletes a container from a storage account
-func (s *AzureBlobService) deleteContainer(storageAccountName, containerName string) error {
+func (s *AzureBlobService) deleteContainer(ctx context.Context, storageAccountName, containerName string) error {
 	blobClient, err := s.getBlobServiceClient(storageAccountName)
 	if err != nil {
 		return err
 	}
 
 	containerClient := blobClient.ServiceClient().NewContainerClient(containerName)
-	_, err = containerClient.Delete(s.ctx, nil)
+	_, err = containerClient.Delete(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete container %s: %w", containerName, err)
 	}
+	journal.Deleted(*s.instance, journal.KindBucket, containerName)
 
 	return nil
 }
 
 // EnsureDefaultResourceExists ensures at least one container exists in each storage account for testing
 // Takes the result of ListBuckets() and creates default containers if needed
-func (s *AzureBlobService) EnsureDefaultResourceExists(buckets []Bucket, err error) ([]Bucket, error) {
+func (s *AzureBlobService) EnsureDefaultResourceExists(ctx context.Context, buckets []Bucket, err error) ([]Bucket, error) {
 	// If there was an error listing buckets, return it
 	if err != nil {
 		return nil, err
@@ -574,7 +727,7 @@ func (s *AzureBlobService) EnsureDefaultResourceExists(buckets []Bucket, err err
 	defaultContainerName := s.defaultContainerName()
 	fmt.Printf("   Creating container: %s\n", defaultContainerName)
 
-	bucket, err := s.CreateBucket(defaultContainerName)
+	bucket, err := s.CreateBucket(ctx, defaultContainerName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create default container: %w", err)
 	}
@@ -586,13 +739,13 @@ func (s *AzureBlobService) EnsureDefaultResourceExists(buckets []Bucket, err err
 }
 
 // GetBucketRetentionDurationDays retrieves the retention policy duration in days for a container
-func (s *AzureBlobService) GetBucketRetentionDurationDays(bucketID string) (int, error) {
+func (s *AzureBlobService) GetBucketRetentionDurationDays(ctx context.Context, bucketID string) (int, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (int, error) {
-		return s.getBucketRetentionDurationDays(bucketID)
+		return s.getBucketRetentionDurationDays(ctx, bucketID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) getBucketRetentionDurationDays(bucketID string) (int, error) {
+func (s *AzureBlobService) getBucketRetentionDurationDays(ctx context.Context, bucketID string) (int, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -605,7 +758,7 @@ func (s *AzureBlobService) getBucketRetentionDurationDays(bucketID string) (int,
 	containerClient := blobClient.ServiceClient().NewContainerClient(containerName)
 
 	// Get container properties
-	_, err = containerClient.GetProperties(s.ctx, nil)
+	_, err = containerClient.GetProperties(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get container properties: %w", err)
 	}
@@ -618,13 +771,13 @@ func (s *AzureBlobService) getBucketRetentionDurationDays(bucketID string) (int,
 }
 
 // GetObjectRetentionDurationDays retrieves the retention policy duration in days for a blob
-func (s *AzureBlobService) GetObjectRetentionDurationDays(bucketID string, objectID string) (int, error) {
+func (s *AzureBlobService) GetObjectRetentionDurationDays(ctx context.Context, bucketID string, objectID string) (int, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (int, error) {
-		return s.getObjectRetentionDurationDays(bucketID, objectID)
+		return s.getObjectRetentionDurationDays(ctx, bucketID, objectID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) getObjectRetentionDurationDays(bucketID string, objectID string) (int, error) {
+func (s *AzureBlobService) getObjectRetentionDurationDays(ctx context.Context, bucketID string, objectID string) (int, error) {
 	storageAccountName := s.storageAccountName()
 	containerName := bucketID
 
@@ -638,7 +791,7 @@ func (s *AzureBlobService) getObjectRetentionDurationDays(bucketID string, objec
 	blockBlobClient := containerClient.NewBlockBlobClient(objectID)
 
 	// Get blob properties
-	props, err := blockBlobClient.GetProperties(s.ctx, nil)
+	props, err := blockBlobClient.GetProperties(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get blob properties: %w", err)
 	}
@@ -653,39 +806,67 @@ func (s *AzureBlobService) getObjectRetentionDurationDays(bucketID string, objec
 	// Azure blob immutability policies inherit from container level
 	// For object-level specifics, we'd need to check the blob's immutability policy
 	// Return container-level retention as default
-	return s.GetBucketRetentionDurationDays(bucketID)
+	return s.GetBucketRetentionDurationDays(ctx, bucketID)
 }
 
 // GetOrProvisionTestableResources returns all Azure storage containers as testable resources
 // Returns two TestParams per container:
 // 1. PerService - for policy/configuration checks
 // 2. PerPort - for TLS/endpoint connectivity tests
-func (s *AzureBlobService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *AzureBlobService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	// Validate that storage account name is set
 	if s.storageAccountName() == "" {
 		return nil, fmt.Errorf("AzureStorageAccount not set in CloudParams")
 	}
 
-	// Build the storage account resource ID for RBAC
-	storageAccountResourceID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/%s",
-		s.instance.Properties.AzureSubscriptionID,
-		s.instance.Properties.AzureResourceGroup,
-		s.storageAccountName())
-
-	fmt.Printf("   Storage Account Resource ID for RBAC: %s\n", storageAccountResourceID)
+	if !s.emulator() {
+		fmt.Printf("   Storage Account Resource ID for RBAC: %s\n", s.storageAccountResourceID())
+	}
 
 	// Elevate access before discovery to ensure we can list containers and interact with the data plane
-	if err := s.ElevateAccessForInspection(); err != nil {
+	if err := s.ElevateAccessForInspection(ctx); err != nil {
 		fmt.Printf("   ⚠️  Warning: Failed to elevate access for discovery: %v\n", err)
 		// Continue anyway, we might already have access
 	}
 
 	// List all buckets and ensure at least one container exists per storage account
-	buckets, err := s.EnsureDefaultResourceExists(s.ListBuckets())
+	buckets, err := s.ListBuckets(ctx)
+	buckets, err = s.EnsureDefaultResourceExists(ctx, buckets, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	return s.containerTestParams(buckets), nil
+}
+
+// DiscoverTestableResources lists existing containers as testable resources without
+// elevating access or creating a default container. If the storage account is locked
+// down, listing fails rather than opening it up.
+func (s *AzureBlobService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	if s.storageAccountName() == "" {
+		return nil, fmt.Errorf("AzureStorageAccount not set in CloudParams")
+	}
+
+	buckets, err := s.ListBuckets(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
 
+	return s.containerTestParams(buckets), nil
+}
+
+// storageAccountResourceID builds the ARM resource ID of the configured storage account
+func (s *AzureBlobService) storageAccountResourceID() string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/%s",
+		s.instance.Properties.AzureSubscriptionID,
+		s.instance.Properties.AzureResourceGroup,
+		s.storageAccountName())
+}
+
+// containerTestParams converts containers to TestParams (2 per container: service + port)
+func (s *AzureBlobService) containerTestParams(buckets []Bucket) []types.TestParams {
+	storageAccountResourceID := s.storageAccountResourceID()
+
 	// Convert containers to TestParams (2 per container: service + port)
 	// UID is the storage account resource ID (for RBAC scope)
 	// ResourceName is the container name (for test identification)
@@ -700,10 +881,16 @@ func (s *AzureBlobService) GetOrProvisionTestableResources() ([]types.TestParams
 			ServiceType:         "object-storage",
 			ProviderServiceType: "Microsoft.Storage/storageAccounts",
 			CatalogTypes:        []string{"CCC.ObjStor"},
-			TagFilter:           []string{"@object-storage", "@PerService"},
+			Labels:              bucket.Labels,
+			TagFilter:           s.serviceTagFilter(),
 			Instance:            *s.instance,
 		})
 
+		// The emulator serves plain HTTP on a local port, so there is no endpoint to TLS-test
+		if s.emulator() {
+			continue
+		}
+
 		// PerPort: Endpoint-level tests (TLS/SSL, port connectivity)
 		endpoint := fmt.Sprintf("%s.blob.core.windows.net", s.storageAccountName())
 		resources = append(resources, types.TestParams{
@@ -717,18 +904,28 @@ func (s *AzureBlobService) GetOrProvisionTestableResources() ([]types.TestParams
 			ServiceType:         "object-storage",
 			ProviderServiceType: "Microsoft.Storage/storageAccounts",
 			CatalogTypes:        []string{"CCC.ObjStor"},
+			Labels:              bucket.Labels,
 			TagFilter:           []string{"@object-storage", "@PerPort", "@tls", "~@ftp", "~@telnet", "~@ssh", "~@smtp", "~@dns", "~@ldap"},
 			Instance:            *s.instance,
 		})
 	}
 
-	return resources, nil
+	return resources
+}
+
+// serviceTagFilter returns the PerService tag filter. Policy checks query the storage
+// account through the az CLI (ARM), so they are excluded in emulator mode.
+func (s *AzureBlobService) serviceTagFilter() []string {
+	if s.emulator() {
+		return []string{"@object-storage", "@PerService", "~@Policy"}
+	}
+	return []string{"@object-storage", "@PerService"}
 }
 
 // CheckUserProvisioned validates that the given identity can access Azure Blob Storage
 // This performs a simple list operation to ensure credentials have propagated
-func (s *AzureBlobService) CheckUserProvisioned() error {
-	_, err := s.listContainersForAccount(s.storageAccountName())
+func (s *AzureBlobService) CheckUserProvisioned(ctx context.Context) error {
+	_, err := s.listContainersForAccount(ctx, s.storageAccountName())
 	if err != nil {
 		return fmt.Errorf("credentials not ready for Azure Blob Storage access: %w", err)
 	}
@@ -737,27 +934,30 @@ func (s *AzureBlobService) CheckUserProvisioned() error {
 
 // SetObjectPermission always returns an error for Azure Blob Storage
 // Azure does not support object-level permissions - only uniform bucket-level access via RBAC
-func (s *AzureBlobService) SetObjectPermission(bucketID, objectID string, permissionLevel string) error {
+func (s *AzureBlobService) SetObjectPermission(ctx context.Context, bucketID, objectID string, permissionLevel string) error {
 	return fmt.Errorf("azure Blob Storage does not support object-level permissions - uniform bucket-level access is enforced via RBAC")
 }
 
 // ListDeletedBuckets lists all soft-deleted containers in the storage account
 // Azure supports container-level soft delete for CN03.AR01
-func (s *AzureBlobService) ListDeletedBuckets() ([]Bucket, error) {
+func (s *AzureBlobService) ListDeletedBuckets(ctx context.Context) ([]Bucket, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() ([]Bucket, error) {
-		return s.listDeletedBuckets()
+		return s.listDeletedBuckets(ctx)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) listDeletedBuckets() ([]Bucket, error) {
+func (s *AzureBlobService) listDeletedBuckets(ctx context.Context) ([]Bucket, error) {
+	if s.emulator() {
+		return nil, notApplicableInEmulator("container soft delete")
+	}
+
 	storageAccountName := s.storageAccountName()
 	if storageAccountName == "" {
 		return nil, fmt.Errorf("no storage account name provided")
 	}
 
 	// Create blob service client
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
-	client, err := azblob.NewClient(serviceURL, s.credential, nil)
+	client, err := s.getBlobServiceClient(storageAccountName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create blob service client: %w", err)
 	}
@@ -771,7 +971,7 @@ func (s *AzureBlobService) listDeletedBuckets() ([]Bucket, error) {
 
 	var buckets []Bucket
 	for pager.More() {
-		resp, err := pager.NextPage(s.ctx)
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list deleted containers: %w", err)
 		}
@@ -793,21 +993,24 @@ func (s *AzureBlobService) listDeletedBuckets() ([]Bucket, error) {
 
 // RestoreBucket restores a soft-deleted container
 // Azure supports container-level soft delete for CN03.AR01
-func (s *AzureBlobService) RestoreBucket(bucketID string) error {
+func (s *AzureBlobService) RestoreBucket(ctx context.Context, bucketID string) error {
 	return retry.DoVoid(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() error {
-		return s.restoreBucket(bucketID)
+		return s.restoreBucket(ctx, bucketID)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) restoreBucket(bucketID string) error {
+func (s *AzureBlobService) restoreBucket(ctx context.Context, bucketID string) error {
+	if s.emulator() {
+		return notApplicableInEmulator("container soft-delete restore")
+	}
+
 	storageAccountName := s.storageAccountName()
 	if storageAccountName == "" {
 		return fmt.Errorf("no storage account name provided")
 	}
 
 	// Create blob service client
-	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", storageAccountName)
-	client, err := azblob.NewClient(serviceURL, s.credential, nil)
+	client, err := s.getBlobServiceClient(storageAccountName)
 	if err != nil {
 		return fmt.Errorf("failed to create blob service client: %w", err)
 	}
@@ -821,7 +1024,7 @@ func (s *AzureBlobService) restoreBucket(bucketID string) error {
 
 	var deletedVersion string
 	for pager.More() {
-		resp, err := pager.NextPage(s.ctx)
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to list deleted containers: %w", err)
 		}
@@ -844,7 +1047,7 @@ func (s *AzureBlobService) restoreBucket(bucketID string) error {
 	containerClient := client.ServiceClient().NewContainerClient(bucketID)
 
 	// Restore the deleted container with its version
-	_, err = containerClient.Restore(s.ctx, deletedVersion, &container.RestoreOptions{})
+	_, err = containerClient.Restore(ctx, deletedVersion, &container.RestoreOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to restore container %s: %w", bucketID, err)
 	}
@@ -855,14 +1058,18 @@ func (s *AzureBlobService) restoreBucket(bucketID string) error {
 
 // SetBucketRetentionDurationDays attempts to modify the immutability policy
 // For CN03.AR02, this should fail if the policy is locked
-func (s *AzureBlobService) SetBucketRetentionDurationDays(bucketID string, days int) error {
+func (s *AzureBlobService) SetBucketRetentionDurationDays(ctx context.Context, bucketID string, days int) error {
+	if s.emulator() {
+		return notApplicableInEmulator("container immutability policy")
+	}
+
 	storageAccountName := s.storageAccountName()
 	if storageAccountName == "" {
 		return fmt.Errorf("no storage account name provided")
 	}
 
 	// Create BlobContainersClient for managing container properties
-	containersClient, err := armstorage.NewBlobContainersClient(s.instance.Properties.AzureSubscriptionID, s.credential, nil)
+	containersClient, err := armstorage.NewBlobContainersClient(s.instance.Properties.AzureSubscriptionID, s.credential, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return fmt.Errorf("failed to create blob containers client: %w", err)
 	}
@@ -872,7 +1079,7 @@ func (s *AzureBlobService) SetBucketRetentionDurationDays(bucketID string, days
 	immutabilityPeriod := int32(days)
 
 	_, err = containersClient.CreateOrUpdateImmutabilityPolicy(
-		s.ctx,
+		ctx,
 		s.instance.Properties.AzureResourceGroup,
 		storageAccountName,
 		bucketID,
@@ -894,23 +1101,32 @@ func (s *AzureBlobService) SetBucketRetentionDurationDays(bucketID string, days
 	return nil
 }
 
-func (s *AzureBlobService) ElevateAccessForInspection() error {
-	return s.elevator.ElevateStorageAccountAccess(s.storageAccountName())
+// ElevateAccessForInspection grants RBAC and network access to the storage account.
+// It is a no-op in emulator mode, where there is no RBAC or firewall to open.
+func (s *AzureBlobService) ElevateAccessForInspection(ctx context.Context) error {
+	if s.emulator() {
+		return nil
+	}
+	return s.elevator.ElevateStorageAccountAccess(ctx, s.storageAccountName())
 }
 
-func (s *AzureBlobService) ResetAccess() error {
-	return s.elevator.ResetStorageAccountAccess(s.storageAccountName())
+// ResetAccess reverts ElevateAccessForInspection (no-op in emulator mode)
+func (s *AzureBlobService) ResetAccess(ctx context.Context) error {
+	if s.emulator() {
+		return nil
+	}
+	return s.elevator.ResetStorageAccountAccess(ctx, s.storageAccountName())
 }
 
 // UpdateBucketPolicy updates container access policy (used for admin action logging tests)
 // containerName is just the container name; storage account is taken from cloudParams
-func (s *AzureBlobService) UpdateBucketPolicy(containerName string, policyTag string) (*Bucket, error) {
+func (s *AzureBlobService) UpdateBucketPolicy(ctx context.Context, containerName string, policyTag string) (*Bucket, error) {
 	return retry.Do(retry.DefaultPropagationAttempts, retry.DefaultPropagationDelay, func() (*Bucket, error) {
-		return s.updateBucketPolicy(containerName, policyTag)
+		return s.updateBucketPolicy(ctx, containerName, policyTag)
 	}, retry.IsAzureRBACPropagationError)
 }
 
-func (s *AzureBlobService) updateBucketPolicy(containerName string, policyTag string) (*Bucket, error) {
+func (s *AzureBlobService) updateBucketPolicy(ctx context.Context, containerName string, policyTag string) (*Bucket, error) {
 	storageAccountName := s.storageAccountName()
 
 	blobClient, err := s.getBlobServiceClient(storageAccountName)
@@ -921,7 +1137,7 @@ func (s *AzureBlobService) updateBucketPolicy(containerName string, policyTag st
 	containerClient := blobClient.ServiceClient().NewContainerClient(containerName)
 
 	// Set metadata as a simple admin action that will be logged
-	_, err = containerClient.SetMetadata(s.ctx, &container.SetMetadataOptions{
+	_, err = containerClient.SetMetadata(ctx, &container.SetMetadataOptions{
 		Metadata: map[string]*string{
 			"test_policy_tag": &policyTag,
 		},
@@ -939,11 +1155,15 @@ func (s *AzureBlobService) updateBucketPolicy(containerName string, policyTag st
 // UpdateResourcePolicy updates the storage account tags to trigger Activity Log entries.
 // Azure Activity Log only captures control plane (ARM) operations, so we update tags
 // rather than container metadata (which is a data plane operation).
-func (s *AzureBlobService) UpdateResourcePolicy() error {
+func (s *AzureBlobService) UpdateResourcePolicy(ctx context.Context) error {
+	if s.emulator() {
+		return notApplicableInEmulator("storage account tag update")
+	}
+
 	storageAccountName := s.storageAccountName()
 
 	// Get current storage account to preserve existing tags
-	account, err := s.storageClient.GetProperties(s.ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, nil)
+	account, err := s.storageClient.GetProperties(ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -961,7 +1181,7 @@ func (s *AzureBlobService) UpdateResourcePolicy() error {
 	tags["ccc_compliance_test"] = &timestamp
 
 	// Update storage account with new tags (control plane operation - will appear in Activity Log)
-	_, err = s.storageClient.Update(s.ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, armstorage.AccountUpdateParameters{
+	_, err = s.storageClient.Update(ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, armstorage.AccountUpdateParameters{
 		Tags: tags,
 	}, nil)
 	if err != nil {
@@ -972,12 +1192,12 @@ func (s *AzureBlobService) UpdateResourcePolicy() error {
 }
 
 // TriggerDataWrite performs a data modification to trigger logging (CN04.AR02)
-func (s *AzureBlobService) TriggerDataWrite(resourceID string) error {
+func (s *AzureBlobService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not yet implemented")
 }
 
 // GetResourceRegion returns the resource region (CN06.AR01)
-func (s *AzureBlobService) GetResourceRegion(resourceID string) (string, error) {
+func (s *AzureBlobService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not yet implemented")
 }
 
@@ -988,9 +1208,13 @@ func (s *AzureBlobService) IsDataReplicatedToSeparateLocation(resourceID string)
 
 // GetReplicationStatus returns replication status including locations (CN08.AR01, CN08.AR02).
 // Populates ReplicationStatus with Locations (primary + secondary for GRS/RA-GRS), Status, SyncStatus.
-func (s *AzureBlobService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *AzureBlobService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
+	if s.emulator() {
+		return nil, notApplicableInEmulator("storage account replication status")
+	}
+
 	storageAccountName := s.storageAccountName()
-	account, err := s.storageClient.GetProperties(s.ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, nil)
+	account, err := s.storageClient.GetProperties(ctx, s.instance.Properties.AzureResourceGroup, storageAccountName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -1053,7 +1277,7 @@ func (s *AzureBlobService) GetReplicationStatus(resourceID string) (*generic.Rep
 }
 
 // TearDown deletes objects created during testing (best-effort; immutable objects are skipped)
-func (s *AzureBlobService) TearDown() error {
+func (s *AzureBlobService) TearDown(ctx context.Context) error {
 	s.createdMu.Lock()
 	objs := make([]struct{ bucket, object string }, len(s.createdObjs))
 	copy(objs, s.createdObjs)
@@ -1061,10 +1285,21 @@ func (s *AzureBlobService) TearDown() error {
 	s.createdMu.Unlock()
 
 	for _, r := range objs {
-		if err := s.DeleteObject(r.bucket, r.object); err != nil {
+		if err := s.DeleteObject(ctx, r.bucket, r.object); err != nil {
 			// Immutability or other constraints may block delete - log and continue
 			fmt.Printf("   ⚠️  TearDown: could not delete %s/%s: %v\n", r.bucket, r.object, err)
 		}
 	}
 	return nil
 }
+
+// SDKClient returns the storage accounts client for policy sdk queries
+func (s *AzureBlobService) SDKClient(ctx context.Context, name, resourceID string) (interface{}, error) {
+	if name != "armstorage" {
+		return nil, generic.UnknownSDKClient(s, name, "armstorage")
+	}
+	if s.emulator() {
+		return nil, notApplicableInEmulator("armstorage")
+	}
+	return s.storageClient, nil
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 	"github.com/google/uuid"
 )
 
@@ -34,7 +38,7 @@ type AzureStorageElevationState struct {
 // AzureStorageElevator handles elevation of Azure Storage Account access controls
 // It manages both storage-specific network controls and RBAC
 type AzureStorageElevator struct {
-	ctx            context.Context
+	instance       types.InstanceConfig // Owner of the role assignments in the teardown journal
 	credential     azcore.TokenCredential
 	subscriptionID string
 	resourceGroup  string
@@ -45,25 +49,25 @@ type AzureStorageElevator struct {
 
 // NewAzureStorageElevator creates a new Azure Storage elevator
 func NewAzureStorageElevator(
-	ctx context.Context,
+	instance types.InstanceConfig,
 	credential azcore.TokenCredential,
 	subscriptionID string,
 	resourceGroup string,
 ) (*AzureStorageElevator, error) {
 	// Create storage-specific client
-	storageClient, err := armstorage.NewAccountsClient(subscriptionID, credential, nil)
+	storageClient, err := armstorage.NewAccountsClient(subscriptionID, credential, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage accounts client: %w", err)
 	}
 
 	// Create authorization client for RBAC
-	authClient, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, credential, nil)
+	authClient, err := armauthorization.NewRoleAssignmentsClient(subscriptionID, credential, generic.AzureARMOptions("azure-arm"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorization client: %w", err)
 	}
 
 	return &AzureStorageElevator{
-		ctx:            ctx,
+		instance:       instance,
 		credential:     credential,
 		subscriptionID: subscriptionID,
 		resourceGroup:  resourceGroup,
@@ -94,8 +98,8 @@ func objectIDFromJWT(accessToken string) (string, error) {
 	return "", fmt.Errorf("JWT has no oid claim")
 }
 
-func (e *AzureStorageElevator) objectIDFromResourceManagerToken() (string, error) {
-	token, err := e.credential.GetToken(e.ctx, policy.TokenRequestOptions{
+func (e *AzureStorageElevator) objectIDFromResourceManagerToken(ctx context.Context) (string, error) {
+	token, err := e.credential.GetToken(ctx, policy.TokenRequestOptions{
 		Scopes: []string{"https://management.azure.com/.default"},
 	})
 	if err != nil {
@@ -105,13 +109,13 @@ func (e *AzureStorageElevator) objectIDFromResourceManagerToken() (string, error
 }
 
 // GetCurrentIdentityObjectID resolves the current principal's object id (ARM token oid first, then Graph).
-func (e *AzureStorageElevator) GetCurrentIdentityObjectID() (string, error) {
-	if oid, err := e.objectIDFromResourceManagerToken(); err == nil {
+func (e *AzureStorageElevator) GetCurrentIdentityObjectID(ctx context.Context) (string, error) {
+	if oid, err := e.objectIDFromResourceManagerToken(ctx); err == nil {
 		return oid, nil
 	}
 
 	// Get a token for Graph API
-	token, err := e.credential.GetToken(e.ctx, policy.TokenRequestOptions{
+	token, err := e.credential.GetToken(ctx, policy.TokenRequestOptions{
 		Scopes: []string{"https://graph.microsoft.com/.default"},
 	})
 	if err != nil {
@@ -119,13 +123,13 @@ func (e *AzureStorageElevator) GetCurrentIdentityObjectID() (string, error) {
 	}
 
 	// Try /me first (works for users)
-	req, err := http.NewRequestWithContext(e.ctx, "GET", "https://graph.microsoft.com/v1.0/me", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://graph.microsoft.com/v1.0/me", nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := recorder.Client("azure-graph").Do(req)
 
 	// If /me fails, it might be a service principal (standard for CI/GitHub Actions)
 	if err == nil && resp.StatusCode != 200 {
@@ -140,9 +144,9 @@ func (e *AzureStorageElevator) GetCurrentIdentityObjectID() (string, error) {
 		if clientID != "" {
 			fmt.Printf("   ℹ️  /me failed, searching for service principal ID for client %s...\n", clientID)
 			query := fmt.Sprintf("https://graph.microsoft.com/v1.0/servicePrincipals?$filter=appId eq '%s'", clientID)
-			req, _ = http.NewRequestWithContext(e.ctx, "GET", query, nil)
+			req, _ = http.NewRequestWithContext(ctx, "GET", query, nil)
 			req.Header.Set("Authorization", "Bearer "+token.Token)
-			resp, err = http.DefaultClient.Do(req)
+			resp, err = recorder.Client("azure-graph").Do(req)
 		}
 	}
 
@@ -179,8 +183,8 @@ func (e *AzureStorageElevator) GetCurrentIdentityObjectID() (string, error) {
 }
 
 // ElevatePublicNetworkAccess enables public network access on a storage account
-func (e *AzureStorageElevator) ElevatePublicNetworkAccess(storageAccountName string) error {
-	resp, err := e.storageClient.GetProperties(e.ctx, e.resourceGroup, storageAccountName, nil)
+func (e *AzureStorageElevator) ElevatePublicNetworkAccess(ctx context.Context, storageAccountName string) error {
+	resp, err := e.storageClient.GetProperties(ctx, e.resourceGroup, storageAccountName, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -212,7 +216,7 @@ func (e *AzureStorageElevator) ElevatePublicNetworkAccess(storageAccountName str
 		},
 	}
 
-	_, err = e.storageClient.Update(e.ctx, e.resourceGroup, storageAccountName, updateParams, nil)
+	_, err = e.storageClient.Update(ctx, e.resourceGroup, storageAccountName, updateParams, nil)
 	if err != nil {
 		return fmt.Errorf("failed to enable public network access: %w", err)
 	}
@@ -223,7 +227,7 @@ func (e *AzureStorageElevator) ElevatePublicNetworkAccess(storageAccountName str
 }
 
 // ResetPublicNetworkAccess restores the original public network access setting
-func (e *AzureStorageElevator) ResetPublicNetworkAccess(storageAccountName string) error {
+func (e *AzureStorageElevator) ResetPublicNetworkAccess(ctx context.Context, storageAccountName string) error {
 	if e.state.OriginalPublicNetworkAccess == nil {
 		fmt.Printf("   ℹ️  No original public network access stored\n")
 		return nil
@@ -236,7 +240,7 @@ func (e *AzureStorageElevator) ResetPublicNetworkAccess(storageAccountName strin
 		return nil
 	}
 
-	resp, err := e.storageClient.GetProperties(e.ctx, e.resourceGroup, storageAccountName, nil)
+	resp, err := e.storageClient.GetProperties(ctx, e.resourceGroup, storageAccountName, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -260,7 +264,7 @@ func (e *AzureStorageElevator) ResetPublicNetworkAccess(storageAccountName strin
 		},
 	}
 
-	_, err = e.storageClient.Update(e.ctx, e.resourceGroup, storageAccountName, updateParams, nil)
+	_, err = e.storageClient.Update(ctx, e.resourceGroup, storageAccountName, updateParams, nil)
 	if err != nil {
 		return fmt.Errorf("failed to restore public network access: %w", err)
 	}
@@ -271,8 +275,8 @@ func (e *AzureStorageElevator) ResetPublicNetworkAccess(storageAccountName strin
 }
 
 // ElevateNetworkFirewall changes the network firewall default action to Allow
-func (e *AzureStorageElevator) ElevateNetworkFirewall(storageAccountName string) error {
-	resp, err := e.storageClient.GetProperties(e.ctx, e.resourceGroup, storageAccountName, nil)
+func (e *AzureStorageElevator) ElevateNetworkFirewall(ctx context.Context, storageAccountName string) error {
+	resp, err := e.storageClient.GetProperties(ctx, e.resourceGroup, storageAccountName, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -306,7 +310,7 @@ func (e *AzureStorageElevator) ElevateNetworkFirewall(storageAccountName string)
 		},
 	}
 
-	_, err = e.storageClient.Update(e.ctx, e.resourceGroup, storageAccountName, updateParams, nil)
+	_, err = e.storageClient.Update(ctx, e.resourceGroup, storageAccountName, updateParams, nil)
 	if err != nil {
 		return fmt.Errorf("failed to update network firewall: %w", err)
 	}
@@ -317,7 +321,7 @@ func (e *AzureStorageElevator) ElevateNetworkFirewall(storageAccountName string)
 }
 
 // ResetNetworkFirewall restores the original network firewall setting
-func (e *AzureStorageElevator) ResetNetworkFirewall(storageAccountName string) error {
+func (e *AzureStorageElevator) ResetNetworkFirewall(ctx context.Context, storageAccountName string) error {
 	if e.state.OriginalNetworkDefaultAction == nil {
 		fmt.Printf("   ℹ️  No original network firewall setting stored\n")
 		return nil
@@ -330,7 +334,7 @@ func (e *AzureStorageElevator) ResetNetworkFirewall(storageAccountName string) e
 		return nil
 	}
 
-	resp, err := e.storageClient.GetProperties(e.ctx, e.resourceGroup, storageAccountName, nil)
+	resp, err := e.storageClient.GetProperties(ctx, e.resourceGroup, storageAccountName, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get storage account properties: %w", err)
 	}
@@ -356,7 +360,7 @@ func (e *AzureStorageElevator) ResetNetworkFirewall(storageAccountName string) e
 		},
 	}
 
-	_, err = e.storageClient.Update(e.ctx, e.resourceGroup, storageAccountName, updateParams, nil)
+	_, err = e.storageClient.Update(ctx, e.resourceGroup, storageAccountName, updateParams, nil)
 	if err != nil {
 		return fmt.Errorf("failed to restore network firewall: %w", err)
 	}
@@ -367,7 +371,7 @@ func (e *AzureStorageElevator) ResetNetworkFirewall(storageAccountName string) e
 }
 
 // GrantRBAC grants an RBAC role to a principal on a resource
-func (e *AzureStorageElevator) GrantRBAC(resourceID string, principalID string, roleDefinitionID string) error {
+func (e *AzureStorageElevator) GrantRBAC(ctx context.Context, resourceID string, principalID string, roleDefinitionID string) error {
 	// Check if role assignment already exists
 	filter := "atScope()"
 	pager := e.authClient.NewListForScopePager(resourceID, &armauthorization.RoleAssignmentsClientListForScopeOptions{
@@ -376,7 +380,7 @@ func (e *AzureStorageElevator) GrantRBAC(resourceID string, principalID string,
 
 	// Check if already assigned
 	for pager.More() {
-		page, err := pager.NextPage(e.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			fmt.Printf("   ⚠️  Warning: Failed to check existing role assignments: %v\n", err)
 			break
@@ -405,7 +409,7 @@ func (e *AzureStorageElevator) GrantRBAC(resourceID string, principalID string,
 		},
 	}
 
-	assignment, err := e.authClient.Create(e.ctx, resourceID, roleAssignmentName, roleAssignmentParams, nil)
+	assignment, err := e.authClient.Create(ctx, resourceID, roleAssignmentName, roleAssignmentParams, nil)
 	if err != nil {
 		if strings.Contains(err.Error(), "already exists") || strings.Contains(err.Error(), "RoleAssignmentExists") {
 			fmt.Printf("   ✅ Role assignment already exists\n")
@@ -417,6 +421,7 @@ func (e *AzureStorageElevator) GrantRBAC(resourceID string, principalID string,
 	// Store the assignment ID for cleanup
 	if assignment.ID != nil {
 		e.state.GrantedRoleAssignments = append(e.state.GrantedRoleAssignments, *assignment.ID)
+		journal.Created(e.instance, "object-storage", journal.KindRoleAssignment, *assignment.ID, nil)
 		fmt.Printf("   ✅ RBAC role assigned\n")
 	}
 
@@ -424,7 +429,7 @@ func (e *AzureStorageElevator) GrantRBAC(resourceID string, principalID string,
 }
 
 // ResetRBAC removes all RBAC role assignments that were granted
-func (e *AzureStorageElevator) ResetRBAC() error {
+func (e *AzureStorageElevator) ResetRBAC(ctx context.Context) error {
 	if len(e.state.GrantedRoleAssignments) == 0 {
 		fmt.Printf("   ℹ️  No RBAC role assignments to remove\n")
 		return nil
@@ -433,11 +438,12 @@ func (e *AzureStorageElevator) ResetRBAC() error {
 	fmt.Printf("   🔓 Removing %d granted RBAC role assignment(s)...\n", len(e.state.GrantedRoleAssignments))
 
 	for _, assignmentID := range e.state.GrantedRoleAssignments {
-		_, err := e.authClient.DeleteByID(e.ctx, assignmentID, nil)
+		_, err := e.authClient.DeleteByID(ctx, assignmentID, nil)
 		if err != nil {
 			fmt.Printf("   ⚠️  Warning: Failed to remove role assignment %s: %v\n", assignmentID, err)
 		} else {
 			fmt.Printf("   ✅ Removed role assignment\n")
+			journal.Deleted(e.instance, journal.KindRoleAssignment, assignmentID)
 		}
 	}
 
@@ -445,19 +451,28 @@ func (e *AzureStorageElevator) ResetRBAC() error {
 	return nil
 }
 
+// DeleteRoleAssignment removes a role assignment by ID, e.g. one leaked by an earlier run
+func (e *AzureStorageElevator) DeleteRoleAssignment(ctx context.Context, assignmentID string) error {
+	_, err := e.authClient.DeleteByID(ctx, assignmentID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete role assignment %s: %w", assignmentID, err)
+	}
+	return nil
+}
+
 // ElevateStorageAccountAccess performs all elevation steps for a storage account
 // This includes: public network access, firewall rules, and RBAC for the current identity
-func (e *AzureStorageElevator) ElevateStorageAccountAccess(storageAccountName string) error {
+func (e *AzureStorageElevator) ElevateStorageAccountAccess(ctx context.Context, storageAccountName string) error {
 	fmt.Printf("🔍 Elevating access for storage account %s...\n", storageAccountName)
 
 	// Step 1: Enable public network access
-	if err := e.ElevatePublicNetworkAccess(storageAccountName); err != nil {
+	if err := e.ElevatePublicNetworkAccess(ctx, storageAccountName); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to elevate public network access: %v\n", err)
 		// Continue - might already be enabled
 	}
 
 	// Step 2: Change network firewall default action to Allow
-	if err := e.ElevateNetworkFirewall(storageAccountName); err != nil {
+	if err := e.ElevateNetworkFirewall(ctx, storageAccountName); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to elevate network firewall: %v\n", err)
 		// Continue - might already be Allow
 	}
@@ -471,14 +486,14 @@ func (e *AzureStorageElevator) ElevateStorageAccountAccess(storageAccountName st
 	roleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/ba92f5b4-2d11-453d-a403-e96b0029c9fe",
 		e.subscriptionID)
 
-	principalID, err := e.GetCurrentIdentityObjectID()
+	principalID, err := e.GetCurrentIdentityObjectID(ctx)
 	if err != nil {
 		fmt.Printf("⚠️  Warning: Could not determine current identity object ID: %v\n", err)
 		fmt.Printf("   Tests may fail if RBAC permissions are not already granted\n")
 		return nil
 	}
 
-	if err := e.GrantRBAC(storageAccountResourceID, principalID, roleDefinitionID); err != nil {
+	if err := e.GrantRBAC(ctx, storageAccountResourceID, principalID, roleDefinitionID); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to grant RBAC role: %v\n", err)
 		fmt.Printf("   Tests may fail if RBAC permissions are not already granted\n")
 	}
@@ -490,21 +505,21 @@ func (e *AzureStorageElevator) ElevateStorageAccountAccess(storageAccountName st
 }
 
 // ResetStorageAccountAccess resets all elevation changes for a storage account
-func (e *AzureStorageElevator) ResetStorageAccountAccess(storageAccountName string) error {
+func (e *AzureStorageElevator) ResetStorageAccountAccess(ctx context.Context, storageAccountName string) error {
 	fmt.Printf("🔒 Resetting access for %s...\n", storageAccountName)
 
 	// Step 1: Remove RBAC role assignments
-	if err := e.ResetRBAC(); err != nil {
+	if err := e.ResetRBAC(ctx); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to remove RBAC role: %v\n", err)
 	}
 
 	// Step 2: Restore network firewall
-	if err := e.ResetNetworkFirewall(storageAccountName); err != nil {
+	if err := e.ResetNetworkFirewall(ctx, storageAccountName); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to reset network firewall: %v\n", err)
 	}
 
 	// Step 3: Restore public network access
-	if err := e.ResetPublicNetworkAccess(storageAccountName); err != nil {
+	if err := e.ResetPublicNetworkAccess(ctx, storageAccountName); err != nil {
 		fmt.Printf("⚠️  Warning: Failed to reset public network access: %v\n", err)
 	}
 
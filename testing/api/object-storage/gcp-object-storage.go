@@ -2,8 +2,12 @@ package objstorage
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,6 +15,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 	"google.golang.org/api/iterator"
@@ -20,22 +25,30 @@ import (
 // GCPStorageService implements Service for Google Cloud Storage
 type GCPStorageService struct {
 	client      *storage.Client
-	ctx         context.Context
 	instance    types.InstanceConfig
 	createdObjs []struct{ bucket, object string }
 	createdMu   sync.Mutex
 }
 
-// NewGCPStorageService creates a new GCP Cloud Storage service using default credentials
+// NewGCPStorageService creates a new GCP Cloud Storage service using default credentials,
+// or an unauthenticated client when the object-storage block targets an emulator
 func NewGCPStorageService(ctx context.Context, instance types.InstanceConfig) (*GCPStorageService, error) {
-	client, err := storage.NewClient(ctx)
+	endpoint := instance.EndpointConfig("object-storage")
+	if endpoint.EndpointURL != "" {
+		fmt.Printf("🧪 Using GCS endpoint %s\n", endpoint.EndpointURL)
+	}
+
+	opts, err := generic.GCPClientOptions(ctx, "gcp-storage", gcsClientOptions(endpoint)...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := storage.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCP storage client: %w", err)
 	}
 
 	return &GCPStorageService{
 		client:   client,
-		ctx:      ctx,
 		instance: instance,
 	}, nil
 }
@@ -48,22 +61,52 @@ func NewGCPStorageServiceWithCredentials(ctx context.Context, instance types.Ins
 		return nil, fmt.Errorf("service_account_key not found in identity credentials")
 	}
 
+	endpoint := instance.EndpointConfig("object-storage")
+	if endpoint.Unauthenticated {
+		return nil, fmt.Errorf("service account access is %w with an unauthenticated GCS endpoint", generic.ErrNotApplicable)
+	}
+
 	fmt.Printf("🔐 Creating GCP Storage client with service account credentials\n")
 
-	client, err := storage.NewClient(ctx, option.WithCredentialsJSON([]byte(serviceAccountKey)))
+	opts, err := generic.GCPClientOptions(ctx, "gcp-storage",
+		append(gcsClientOptions(endpoint), option.WithCredentialsJSON([]byte(serviceAccountKey)))...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := storage.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCP storage client with credentials: %w", err)
 	}
 
 	return &GCPStorageService{
 		client:   client,
-		ctx:      ctx,
 		instance: instance,
 	}, nil
 }
 
+// gcsClientOptions returns the client options for the configured endpoint overrides.
+// An emulator such as fake-gcs-server needs endpoint-url (e.g. http://localhost:4443/storage/v1/)
+// and unauthenticated: true; insecure-skip-verify accepts its self-signed certificate.
+func gcsClientOptions(endpoint types.EndpointConfig) []option.ClientOption {
+	opts := []option.ClientOption{}
+	if endpoint.EndpointURL != "" {
+		opts = append(opts, option.WithEndpoint(endpoint.EndpointURL))
+	}
+	if endpoint.Unauthenticated {
+		if endpoint.InsecureSkipVerify {
+			// A custom HTTP client carries no credentials, so it also covers WithoutAuthentication
+			opts = append(opts, option.WithHTTPClient(&http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			}}))
+		} else {
+			opts = append(opts, option.WithoutAuthentication())
+		}
+	}
+	return opts
+}
+
 // ListBuckets lists all GCS buckets in the project
-func (s *GCPStorageService) ListBuckets() ([]Bucket, error) {
+func (s *GCPStorageService) ListBuckets(ctx context.Context) ([]Bucket, error) {
 	projectID := s.instance.Properties.GcpProjectId
 	if projectID == "" {
 		return nil, fmt.Errorf("GcpProjectId not set in CloudParams")
@@ -72,7 +115,7 @@ func (s *GCPStorageService) ListBuckets() ([]Bucket, error) {
 	fmt.Printf("📦 Listing buckets in project: %s\n", projectID)
 
 	var buckets []Bucket
-	it := s.client.Buckets(s.ctx, projectID)
+	it := s.client.Buckets(ctx, projectID)
 	for {
 		attrs, err := it.Next()
 		if err == iterator.Done {
@@ -86,6 +129,7 @@ func (s *GCPStorageService) ListBuckets() ([]Bucket, error) {
 			ID:     attrs.Name,
 			Name:   attrs.Name,
 			Region: attrs.Location,
+			Labels: types.LabelsFromMap(attrs.Labels),
 		})
 	}
 
@@ -93,7 +137,7 @@ func (s *GCPStorageService) ListBuckets() ([]Bucket, error) {
 }
 
 // CreateBucket creates a new GCS bucket
-func (s *GCPStorageService) CreateBucket(bucketID string) (*Bucket, error) {
+func (s *GCPStorageService) CreateBucket(ctx context.Context, bucketID string) (*Bucket, error) {
 	projectID := s.instance.Properties.GcpProjectId
 	region := s.instance.Properties.Region
 	if region == "" {
@@ -103,12 +147,14 @@ func (s *GCPStorageService) CreateBucket(bucketID string) (*Bucket, error) {
 	fmt.Printf("📦 Creating bucket %s in project %s (region: %s)...\n", bucketID, projectID, region)
 
 	bucket := s.client.Bucket(bucketID)
-	err := bucket.Create(s.ctx, projectID, &storage.BucketAttrs{
+	err := bucket.Create(ctx, projectID, &storage.BucketAttrs{
 		Location: region,
+		Labels:   journal.Tags(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bucket %s: %w", bucketID, err)
 	}
+	journal.Created(s.instance, "object-storage", journal.KindBucket, bucketID, nil)
 
 	fmt.Printf("   ✅ Bucket created\n")
 
@@ -120,22 +166,23 @@ func (s *GCPStorageService) CreateBucket(bucketID string) (*Bucket, error) {
 }
 
 // DeleteBucket deletes a GCS bucket
-func (s *GCPStorageService) DeleteBucket(bucketID string) error {
+func (s *GCPStorageService) DeleteBucket(ctx context.Context, bucketID string) error {
 	fmt.Printf("🗑️  Deleting bucket %s...\n", bucketID)
 
 	bucket := s.client.Bucket(bucketID)
-	err := bucket.Delete(s.ctx)
+	err := bucket.Delete(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to delete bucket %s: %w", bucketID, err)
 	}
+	journal.Deleted(s.instance, journal.KindBucket, bucketID)
 
 	return nil
 }
 
 // GetBucketRegion returns the region where a bucket is located
-func (s *GCPStorageService) GetBucketRegion(bucketID string) (string, error) {
+func (s *GCPStorageService) GetBucketRegion(ctx context.Context, bucketID string) (string, error) {
 	bucket := s.client.Bucket(bucketID)
-	attrs, err := bucket.Attrs(s.ctx)
+	attrs, err := bucket.Attrs(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get bucket attributes for %s: %w", bucketID, err)
 	}
@@ -144,11 +191,11 @@ func (s *GCPStorageService) GetBucketRegion(bucketID string) (string, error) {
 }
 
 // ListObjects lists all objects in a bucket
-func (s *GCPStorageService) ListObjects(bucketID string) ([]Object, error) {
+func (s *GCPStorageService) ListObjects(ctx context.Context, bucketID string) ([]Object, error) {
 	bucket := s.client.Bucket(bucketID)
 
 	var objects []Object
-	it := bucket.Objects(s.ctx, nil)
+	it := bucket.Objects(ctx, nil)
 	for {
 		attrs, err := it.Next()
 		if err == iterator.Done {
@@ -171,12 +218,13 @@ func (s *GCPStorageService) ListObjects(bucketID string) ([]Object, error) {
 }
 
 // CreateObject creates a new object in a bucket
-func (s *GCPStorageService) CreateObject(bucketID string, objectID string, data string) (*Object, error) {
+func (s *GCPStorageService) CreateObject(ctx context.Context, bucketID string, objectID string, data string) (*Object, error) {
 	bucket := s.client.Bucket(bucketID)
 	obj := bucket.Object(objectID)
 
 	// Create writer and upload
-	writer := obj.NewWriter(s.ctx)
+	writer := obj.NewWriter(ctx)
+	writer.Metadata = journal.Tags()
 	content := []byte(data)
 	_, err := writer.Write(content)
 	if err != nil {
@@ -187,9 +235,10 @@ func (s *GCPStorageService) CreateObject(bucketID string, objectID string, data
 	if err != nil {
 		return nil, fmt.Errorf("failed to close writer for object %s: %w", objectID, err)
 	}
+	journal.Created(s.instance, "object-storage", journal.KindObject, bucketID+"/"+objectID, nil)
 
 	// Get object attributes to check encryption
-	attrs, err := obj.Attrs(s.ctx)
+	attrs, err := obj.Attrs(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object attributes: %w", err)
 	}
@@ -221,7 +270,7 @@ func (s *GCPStorageService) CreateObject(bucketID string, objectID string, data
 }
 
 // ReadObjectAtVersion reads a specific version (generation) of an object from a bucket
-func (s *GCPStorageService) ReadObjectAtVersion(bucketID string, objectID string, versionID string) (*Object, error) {
+func (s *GCPStorageService) ReadObjectAtVersion(ctx context.Context, bucketID string, objectID string, versionID string) (*Object, error) {
 	gen, err := strconv.ParseInt(versionID, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid version ID %q: %w", versionID, err)
@@ -229,7 +278,7 @@ func (s *GCPStorageService) ReadObjectAtVersion(bucketID string, objectID string
 	bucket := s.client.Bucket(bucketID)
 	obj := bucket.Object(objectID).Generation(gen)
 
-	reader, err := obj.NewReader(s.ctx)
+	reader, err := obj.NewReader(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reader for object %s version %s: %w", objectID, versionID, err)
 	}
@@ -240,7 +289,7 @@ func (s *GCPStorageService) ReadObjectAtVersion(bucketID string, objectID string
 		return nil, fmt.Errorf("failed to read object content: %w", err)
 	}
 
-	attrs, err := obj.Attrs(s.ctx)
+	attrs, err := obj.Attrs(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object attributes: %w", err)
 	}
@@ -255,11 +304,11 @@ func (s *GCPStorageService) ReadObjectAtVersion(bucketID string, objectID string
 }
 
 // ReadObject reads an object from a bucket
-func (s *GCPStorageService) ReadObject(bucketID string, objectID string) (*Object, error) {
+func (s *GCPStorageService) ReadObject(ctx context.Context, bucketID string, objectID string) (*Object, error) {
 	bucket := s.client.Bucket(bucketID)
 	obj := bucket.Object(objectID)
 
-	reader, err := obj.NewReader(s.ctx)
+	reader, err := obj.NewReader(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reader for object %s: %w", objectID, err)
 	}
@@ -270,7 +319,7 @@ func (s *GCPStorageService) ReadObject(bucketID string, objectID string) (*Objec
 		return nil, fmt.Errorf("failed to read object content: %w", err)
 	}
 
-	attrs, err := obj.Attrs(s.ctx)
+	attrs, err := obj.Attrs(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object attributes: %w", err)
 	}
@@ -285,20 +334,84 @@ func (s *GCPStorageService) ReadObject(bucketID string, objectID string) (*Objec
 }
 
 // DeleteObject deletes an object from a bucket
-func (s *GCPStorageService) DeleteObject(bucketID string, objectID string) error {
+func (s *GCPStorageService) DeleteObject(ctx context.Context, bucketID string, objectID string) error {
 	bucket := s.client.Bucket(bucketID)
 	obj := bucket.Object(objectID)
 
-	err := obj.Delete(s.ctx)
+	err := obj.Delete(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to delete object %s: %w", objectID, err)
 	}
+	journal.Deleted(s.instance, journal.KindObject, bucketID+"/"+objectID)
 
 	return nil
 }
 
+// Sweep deletes a bucket or object journaled by an earlier run. Buckets are emptied first,
+// since they were created by the run.
+func (s *GCPStorageService) Sweep(ctx context.Context, entry journal.Entry) error {
+	var err error
+	switch entry.Kind {
+	case journal.KindObject:
+		bucketID, objectID, _ := strings.Cut(entry.ID, "/")
+		err = s.DeleteObject(ctx, bucketID, objectID)
+	case journal.KindBucket:
+		var objects []Object
+		objects, err = s.ListObjects(ctx, entry.ID)
+		for _, obj := range objects {
+			if err = s.DeleteObject(ctx, entry.ID, obj.ID); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = s.DeleteBucket(ctx, entry.ID)
+		}
+	default:
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	if errors.Is(err, storage.ErrBucketNotExist) || errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+// FindTagged lists the buckets labelled with a run ID, and the objects in other buckets
+// whose metadata carries one
+func (s *GCPStorageService) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	var found []journal.Entry
+	it := s.client.Buckets(ctx, s.instance.Properties.GcpProjectId)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list buckets: %w", err)
+		}
+		if runID := attrs.Labels[journal.TagKey]; runID != "" {
+			found = append(found, journal.Found(s.instance, "object-storage", journal.KindBucket, attrs.Name, runID))
+			continue
+		}
+
+		objects := s.client.Bucket(attrs.Name).Objects(ctx, nil)
+		for {
+			objAttrs, err := objects.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list objects in bucket %s: %w", attrs.Name, err)
+			}
+			if runID := objAttrs.Metadata[journal.TagKey]; runID != "" {
+				found = append(found, journal.Found(s.instance, "object-storage", journal.KindObject, attrs.Name+"/"+objAttrs.Name, runID))
+			}
+		}
+	}
+	return found, nil
+}
+
 // EnsureDefaultResourceExists ensures at least one bucket exists for testing
-func (s *GCPStorageService) EnsureDefaultResourceExists(buckets []Bucket, err error) ([]Bucket, error) {
+func (s *GCPStorageService) EnsureDefaultResourceExists(ctx context.Context, buckets []Bucket, err error) ([]Bucket, error) {
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +425,7 @@ func (s *GCPStorageService) EnsureDefaultResourceExists(buckets []Bucket, err er
 	defaultBucketName := fmt.Sprintf("ccc-test-bucket-%s", strings.ToLower(projectID))
 	fmt.Printf("📦 No buckets found. Creating default test bucket: %s\n", defaultBucketName)
 
-	bucket, err := s.CreateBucket(defaultBucketName)
+	bucket, err := s.CreateBucket(ctx, defaultBucketName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create default bucket: %w", err)
 	}
@@ -322,9 +435,9 @@ func (s *GCPStorageService) EnsureDefaultResourceExists(buckets []Bucket, err er
 }
 
 // GetBucketRetentionDurationDays retrieves the retention policy duration in days for a bucket
-func (s *GCPStorageService) GetBucketRetentionDurationDays(bucketID string) (int, error) {
+func (s *GCPStorageService) GetBucketRetentionDurationDays(ctx context.Context, bucketID string) (int, error) {
 	bucket := s.client.Bucket(bucketID)
-	attrs, err := bucket.Attrs(s.ctx)
+	attrs, err := bucket.Attrs(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get bucket attributes: %w", err)
 	}
@@ -339,11 +452,11 @@ func (s *GCPStorageService) GetBucketRetentionDurationDays(bucketID string) (int
 }
 
 // GetObjectRetentionDurationDays retrieves the retention duration for an object
-func (s *GCPStorageService) GetObjectRetentionDurationDays(bucketID string, objectID string) (int, error) {
+func (s *GCPStorageService) GetObjectRetentionDurationDays(ctx context.Context, bucketID string, objectID string) (int, error) {
 	bucket := s.client.Bucket(bucketID)
 	obj := bucket.Object(objectID)
 
-	attrs, err := obj.Attrs(s.ctx)
+	attrs, err := obj.Attrs(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get object attributes: %w", err)
 	}
@@ -355,23 +468,45 @@ func (s *GCPStorageService) GetObjectRetentionDurationDays(bucketID string, obje
 	}
 
 	// Fall back to bucket-level retention
-	return s.GetBucketRetentionDurationDays(bucketID)
+	return s.GetBucketRetentionDurationDays(ctx, bucketID)
 }
 
 // GetOrProvisionTestableResources returns all GCS buckets as testable resources
-func (s *GCPStorageService) GetOrProvisionTestableResources() ([]types.TestParams, error) {
+func (s *GCPStorageService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
 	projectID := s.instance.Properties.GcpProjectId
 	if projectID == "" {
 		return nil, fmt.Errorf("GcpProjectId not set in CloudParams")
 	}
 
 	// List all buckets and ensure at least one exists
-	buckets, err := s.EnsureDefaultResourceExists(s.ListBuckets())
+	buckets, err := s.ListBuckets(ctx)
+	buckets, err = s.EnsureDefaultResourceExists(ctx, buckets, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list buckets: %w", err)
 	}
 
-	// Convert buckets to TestParams (2 per bucket: service + port)
+	return s.bucketTestParams(buckets), nil
+}
+
+// DiscoverTestableResources lists existing GCS buckets as testable resources without
+// creating a default bucket when none exist
+func (s *GCPStorageService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	if s.instance.Properties.GcpProjectId == "" {
+		return nil, fmt.Errorf("GcpProjectId not set in CloudParams")
+	}
+
+	buckets, err := s.ListBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+
+	return s.bucketTestParams(buckets), nil
+}
+
+// bucketTestParams converts buckets to TestParams (2 per bucket: service + port, or only
+// service when the endpoint is not https)
+func (s *GCPStorageService) bucketTestParams(buckets []Bucket) []types.TestParams {
+	projectID := s.instance.Properties.GcpProjectId
 	resources := make([]types.TestParams, 0, len(buckets)*2)
 	for _, bucket := range buckets {
 		// PerService: Resource-level tests (policy checks, configuration validation)
@@ -383,40 +518,78 @@ func (s *GCPStorageService) GetOrProvisionTestableResources() ([]types.TestParam
 			ProviderServiceType: "storage.googleapis.com/Bucket",
 			ServiceType:         "object-storage",
 			CatalogTypes:        []string{"CCC.ObjStor"},
-			TagFilter:           []string{"@object-storage", "@PerService"},
+			Labels:              bucket.Labels,
+			TagFilter:           s.serviceTagFilter(),
 			Instance:            s.instance,
 		})
 
-		// PerPort: Endpoint-level tests (TLS/SSL, port connectivity)
-		endpoint := fmt.Sprintf("%s.storage.googleapis.com", bucket.Name)
+		// PerPort: Endpoint-level tests (TLS/SSL, port connectivity). An emulator endpoint-url
+		// serving plain HTTP has no TLS to test, so it gets none, as with Azurite.
+		endpoint, port, protocol := s.bucketEndpoint(bucket.Name)
+		if protocol != "https" {
+			continue
+		}
 		resources = append(resources, types.TestParams{
 			ResourceName:        bucket.Name,
 			UID:                 fmt.Sprintf("projects/%s/buckets/%s", projectID, bucket.Name),
 			ReportFile:          fmt.Sprintf("%s-port", bucket.Name),
-			ReportTitle:         fmt.Sprintf("%s:443", endpoint),
+			ReportTitle:         fmt.Sprintf("%s:%s", endpoint, port),
 			HostName:            endpoint,
-			PortNumber:          "443",
-			Protocol:            "https",
+			PortNumber:          port,
+			Protocol:            protocol,
 			ProviderServiceType: "storage.googleapis.com/Bucket",
 			ServiceType:         "object-storage",
 			CatalogTypes:        []string{"CCC.ObjStor"},
+			Labels:              bucket.Labels,
 			TagFilter:           []string{"@object-storage", "@PerPort", "@tls", "~@ftp", "~@telnet", "~@ssh", "~@smtp", "~@dns", "~@ldap"},
 			Instance:            s.instance,
 		})
 	}
 
-	return resources, nil
+	return resources
+}
+
+// serviceTagFilter returns the PerService tag filter. Policy checks query the bucket through
+// gcloud, which cannot reach an emulator, so they are excluded when endpoint-url is set.
+func (s *GCPStorageService) serviceTagFilter() []string {
+	if s.instance.EndpointConfig("object-storage").EndpointURL != "" {
+		return []string{"@object-storage", "@PerService", "~@Policy"}
+	}
+	return []string{"@object-storage", "@PerService"}
+}
+
+// bucketEndpoint returns the host, port and protocol serving the bucket: the virtual-hosted
+// GCS endpoint, or the configured endpoint-url when one is set
+func (s *GCPStorageService) bucketEndpoint(bucketName string) (string, string, string) {
+	endpointURL := s.instance.EndpointConfig("object-storage").EndpointURL
+	if endpointURL == "" {
+		return fmt.Sprintf("%s.storage.googleapis.com", bucketName), "443", "https"
+	}
+
+	u, err := url.Parse(endpointURL)
+	if err != nil || u.Hostname() == "" {
+		fmt.Printf("⚠️  Warning: Invalid object-storage endpoint-url %q: %v\n", endpointURL, err)
+		return endpointURL, "443", "https"
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return u.Hostname(), port, u.Scheme
 }
 
 // CheckUserProvisioned validates that credentials can access GCS
-func (s *GCPStorageService) CheckUserProvisioned() error {
+func (s *GCPStorageService) CheckUserProvisioned(ctx context.Context) error {
 	projectID := s.instance.Properties.GcpProjectId
 	if projectID == "" {
 		return fmt.Errorf("GcpProjectId not set")
 	}
 
 	// Try to list buckets as validation
-	it := s.client.Buckets(s.ctx, projectID)
+	it := s.client.Buckets(ctx, projectID)
 	_, err := it.Next()
 	if err != nil && err != iterator.Done {
 		return fmt.Errorf("credentials not ready for GCS access: %w", err)
@@ -425,18 +598,18 @@ func (s *GCPStorageService) CheckUserProvisioned() error {
 }
 
 // ElevateAccessForInspection is a no-op for GCP (access managed via IAM)
-func (s *GCPStorageService) ElevateAccessForInspection() error {
+func (s *GCPStorageService) ElevateAccessForInspection(ctx context.Context) error {
 	return nil
 }
 
 // ResetAccess is a no-op for GCP (access managed via IAM)
-func (s *GCPStorageService) ResetAccess() error {
+func (s *GCPStorageService) ResetAccess(ctx context.Context) error {
 	return nil
 }
 
 // SetObjectPermission attempts to set object-level ACLs
 // GCP supports uniform bucket-level access which disables object ACLs
-func (s *GCPStorageService) SetObjectPermission(bucketID, objectID string, permissionLevel string) error {
+func (s *GCPStorageService) SetObjectPermission(ctx context.Context, bucketID, objectID string, permissionLevel string) error {
 	bucket := s.client.Bucket(bucketID)
 	obj := bucket.Object(objectID)
 
@@ -453,7 +626,7 @@ func (s *GCPStorageService) SetObjectPermission(bucketID, objectID string, permi
 	case "none":
 		// Remove all public access
 		acl := obj.ACL()
-		err := acl.Delete(s.ctx, storage.AllUsers)
+		err := acl.Delete(ctx, storage.AllUsers)
 		if err != nil {
 			// May fail if uniform bucket-level access is enabled
 			return fmt.Errorf("failed to remove ACL (uniform access may be enabled): %w", err)
@@ -464,7 +637,7 @@ func (s *GCPStorageService) SetObjectPermission(bucketID, objectID string, permi
 	}
 
 	acl := obj.ACL()
-	err := acl.Set(s.ctx, entity, role)
+	err := acl.Set(ctx, entity, role)
 	if err != nil {
 		// Check if it's because uniform bucket-level access is enabled
 		if strings.Contains(err.Error(), "uniformBucketLevelAccess") {
@@ -478,21 +651,21 @@ func (s *GCPStorageService) SetObjectPermission(bucketID, objectID string, permi
 
 // ListDeletedBuckets returns soft-deleted buckets
 // Note: GCS soft delete is at the object level, not bucket level
-func (s *GCPStorageService) ListDeletedBuckets() ([]Bucket, error) {
+func (s *GCPStorageService) ListDeletedBuckets(ctx context.Context) ([]Bucket, error) {
 	return nil, fmt.Errorf("GCS does not support bucket-level soft delete - bucket deletion is immediate")
 }
 
 // RestoreBucket returns an error - GCS doesn't support bucket-level soft delete
-func (s *GCPStorageService) RestoreBucket(bucketID string) error {
+func (s *GCPStorageService) RestoreBucket(ctx context.Context, bucketID string) error {
 	return fmt.Errorf("GCS does not support bucket restoration - bucket deletion is immediate")
 }
 
 // SetBucketRetentionDurationDays sets the retention policy for a bucket
-func (s *GCPStorageService) SetBucketRetentionDurationDays(bucketID string, days int) error {
+func (s *GCPStorageService) SetBucketRetentionDurationDays(ctx context.Context, bucketID string, days int) error {
 	bucket := s.client.Bucket(bucketID)
 
 	// Get current attributes to check if retention policy is locked
-	attrs, err := bucket.Attrs(s.ctx)
+	attrs, err := bucket.Attrs(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get bucket attributes: %w", err)
 	}
@@ -506,7 +679,7 @@ func (s *GCPStorageService) SetBucketRetentionDurationDays(bucketID string, days
 	retentionPeriod := time.Duration(days) * 24 * time.Hour
 
 	// Update bucket with new retention policy
-	_, err = bucket.Update(s.ctx, storage.BucketAttrsToUpdate{
+	_, err = bucket.Update(ctx, storage.BucketAttrsToUpdate{
 		RetentionPolicy: &storage.RetentionPolicy{
 			RetentionPeriod: retentionPeriod,
 		},
@@ -521,10 +694,10 @@ func (s *GCPStorageService) SetBucketRetentionDurationDays(bucketID string, days
 
 // UpdateBucketPolicy simulates updating bucket policy (used for admin action logging tests)
 // Note: Actual IAM policy updates require iam.RoleName type; this is a placeholder
-func (s *GCPStorageService) UpdateBucketPolicy(bucketID string, policyTag string) (*Bucket, error) {
+func (s *GCPStorageService) UpdateBucketPolicy(ctx context.Context, bucketID string, policyTag string) (*Bucket, error) {
 	// Verify bucket exists (this operation is logged in Admin Activity logs)
 	bucket := s.client.Bucket(bucketID)
-	_, err := bucket.Attrs(s.ctx)
+	_, err := bucket.Attrs(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bucket attributes: %w", err)
 	}
@@ -537,9 +710,9 @@ func (s *GCPStorageService) UpdateBucketPolicy(bucketID string, policyTag string
 
 // UpdateResourcePolicy updates the bucket's labels to trigger logging without functional changes.
 // It sets a timestamped label to ensure the bucket is "changed" for Cloud Audit Logs' perspective.
-func (s *GCPStorageService) UpdateResourcePolicy() error {
+func (s *GCPStorageService) UpdateResourcePolicy(ctx context.Context) error {
 	// Get the first bucket to update
-	buckets, err := s.ListBuckets()
+	buckets, err := s.ListBuckets(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list buckets: %w", err)
 	}
@@ -555,7 +728,7 @@ func (s *GCPStorageService) UpdateResourcePolicy() error {
 	bucketAttrsToUpdate := storage.BucketAttrsToUpdate{}
 	bucketAttrsToUpdate.SetLabel("ccc_compliance_test", timestamp)
 
-	_, err = bucket.Update(s.ctx, bucketAttrsToUpdate)
+	_, err = bucket.Update(ctx, bucketAttrsToUpdate)
 	if err != nil {
 		return fmt.Errorf("failed to update bucket labels: %w", err)
 	}
@@ -564,12 +737,12 @@ func (s *GCPStorageService) UpdateResourcePolicy() error {
 }
 
 // TriggerDataWrite performs a data modification to trigger logging (CN04.AR02)
-func (s *GCPStorageService) TriggerDataWrite(resourceID string) error {
+func (s *GCPStorageService) TriggerDataWrite(ctx context.Context, resourceID string) error {
 	return fmt.Errorf("not yet implemented")
 }
 
 // GetResourceRegion returns the resource region (CN06.AR01)
-func (s *GCPStorageService) GetResourceRegion(resourceID string) (string, error) {
+func (s *GCPStorageService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
 	return "", fmt.Errorf("not yet implemented")
 }
 
@@ -580,12 +753,12 @@ func (s *GCPStorageService) IsDataReplicatedToSeparateLocation(resourceID string
 
 // GetReplicationStatus returns replication status including locations (CN08.AR01, CN08.AR02).
 // Populates ReplicationStatus with Locations (constituent regions for multi/dual-region buckets), Status, SyncStatus.
-func (s *GCPStorageService) GetReplicationStatus(resourceID string) (*generic.ReplicationStatus, error) {
+func (s *GCPStorageService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("not yet implemented")
 }
 
 // TearDown deletes objects created during testing
-func (s *GCPStorageService) TearDown() error {
+func (s *GCPStorageService) TearDown(ctx context.Context) error {
 	s.createdMu.Lock()
 	objs := make([]struct{ bucket, object string }, len(s.createdObjs))
 	copy(objs, s.createdObjs)
@@ -593,9 +766,28 @@ func (s *GCPStorageService) TearDown() error {
 	s.createdMu.Unlock()
 
 	for _, r := range objs {
-		if err := s.DeleteObject(r.bucket, r.object); err != nil {
+		if err := s.DeleteObject(ctx, r.bucket, r.object); err != nil {
 			fmt.Printf("   ⚠️  TearDown: could not delete %s/%s: %v\n", r.bucket, r.object, err)
 		}
 	}
 	return nil
 }
+
+// SDKClient returns the Cloud Storage client for policy sdk queries
+func (s *GCPStorageService) SDKClient(ctx context.Context, name, resourceID string) (interface{}, error) {
+	if name != "storage" {
+		return nil, generic.UnknownSDKClient(s, name, "storage")
+	}
+	return gcsSDKClient{client: s.client}, nil
+}
+
+// gcsSDKClient exposes Cloud Storage reads as operations for policy sdk queries, since the
+// storage client itself is organised around bucket and object handles
+type gcsSDKClient struct {
+	client *storage.Client
+}
+
+// GetBucket returns the attributes of a bucket (versioning, retention, encryption, labels, ...)
+func (c gcsSDKClient) GetBucket(ctx context.Context, bucket string) (*storage.BucketAttrs, error) {
+	return c.client.Bucket(bucket).Attrs(ctx)
+}
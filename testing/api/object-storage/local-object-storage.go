@@ -0,0 +1,730 @@
+package objstorage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/logging"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+const (
+	// localDefaultBucket is seeded when the object-storage service lists no resources
+	localDefaultBucket = "ccc-local-bucket"
+
+	// localRetentionDays is the locked default retention applied to every local bucket
+	localRetentionDays = 7
+
+	// localUnpermittedRegion is where buckets live when CCC.Core.CN06 is non-compliant
+	localUnpermittedRegion = "local-unpermitted"
+)
+
+// localObjectVersion is one stored version of an object
+type localObjectVersion struct {
+	versionID string
+	data      string
+}
+
+// localObject is an object and its version history (oldest first)
+type localObject struct {
+	versions    []localObjectVersion
+	deleted     bool      // the latest version is a delete marker
+	permission  string    // object-level permission set via SetObjectPermission ("" = bucket-level only)
+	retainUntil time.Time // zero when no retention applies
+}
+
+// localBucket is an in-memory bucket
+type localBucket struct {
+	name          string
+	region        string
+	retentionDays int
+	objects       map[string]*localObject
+}
+
+// LocalStore is the in-memory state behind the local object storage fake. One store is shared
+// by the service's own client and every identity-scoped client created by the same factory.
+type LocalStore struct {
+	mu             sync.Mutex
+	region         string
+	buckets        map[string]*localBucket
+	deletedBuckets map[string]*localBucket
+	versionCounter int
+}
+
+// NewLocalStore creates a store seeded with the buckets listed in the instance's
+// object-storage "resources" property (or a single default bucket)
+func NewLocalStore(instance types.InstanceConfig) *LocalStore {
+	config := instance.LocalServiceConfig("object-storage")
+
+	region := instance.Properties.Region
+	if !config.Compliant("CCC.Core.CN06") {
+		region = localUnpermittedRegion
+	}
+
+	store := &LocalStore{
+		region:         region,
+		buckets:        make(map[string]*localBucket),
+		deletedBuckets: make(map[string]*localBucket),
+	}
+
+	names := config.Resources
+	if len(names) == 0 {
+		names = []string{localDefaultBucket}
+	}
+	for _, name := range names {
+		store.buckets[name] = store.newBucket(name)
+	}
+	return store
+}
+
+// newBucket returns an empty bucket in the store's region (caller holds no lock requirement)
+func (st *LocalStore) newBucket(name string) *localBucket {
+	return &localBucket{
+		name:          name,
+		region:        st.region,
+		retentionDays: localRetentionDays,
+		objects:       make(map[string]*localObject),
+	}
+}
+
+// nextVersionID returns a new unique version identifier (caller must hold st.mu)
+func (st *LocalStore) nextVersionID() string {
+	st.versionCounter++
+	return fmt.Sprintf("v%06d", st.versionCounter)
+}
+
+// bucket returns the named live bucket (caller must hold st.mu)
+func (st *LocalStore) bucket(bucketID string) (*localBucket, error) {
+	b, ok := st.buckets[bucketID]
+	if !ok {
+		return nil, fmt.Errorf("bucket %s not found", bucketID)
+	}
+	return b, nil
+}
+
+// LocalObjectStorageService implements Service in memory for the offline "local" provider.
+// Each control behaves compliantly unless listed in the service's non-compliant-controls.
+type LocalObjectStorageService struct {
+	instance    types.InstanceConfig
+	config      types.LocalServiceConfig
+	store       *LocalStore
+	auditLog    *logging.LocalAuditLog
+	iamService  *iam.LocalIAMService
+	identity    *iam.Identity // nil when using the service's own (admin) credentials
+	createdObjs []struct{ bucket, object string }
+	createdMu   sync.Mutex
+}
+
+// NewLocalObjectStorageService creates a local object storage service with admin access
+func NewLocalObjectStorageService(instance types.InstanceConfig, store *LocalStore, auditLog *logging.LocalAuditLog) *LocalObjectStorageService {
+	return &LocalObjectStorageService{
+		instance: instance,
+		config:   instance.LocalServiceConfig("object-storage"),
+		store:    store,
+		auditLog: auditLog,
+	}
+}
+
+// NewLocalObjectStorageServiceWithIdentity creates a local object storage service whose calls are
+// authorized against the identity's grants in the local IAM service
+func NewLocalObjectStorageServiceWithIdentity(instance types.InstanceConfig, store *LocalStore, auditLog *logging.LocalAuditLog, iamService *iam.LocalIAMService, identity *iam.Identity) *LocalObjectStorageService {
+	s := NewLocalObjectStorageService(instance, store, auditLog)
+	s.iamService = iamService
+	s.identity = identity
+	return s
+}
+
+// callerName returns the identity recorded in audit entries
+func (s *LocalObjectStorageService) callerName() string {
+	if s.identity == nil {
+		return "local-admin"
+	}
+	return s.identity.UserName
+}
+
+// record writes an audit entry unless CCC.Core.CN04 (logging) is non-compliant
+func (s *LocalObjectStorageService) record(eventType, action, resource string) {
+	if s.auditLog == nil || !s.config.Compliant("CCC.Core.CN04") {
+		return
+	}
+	s.auditLog.Record(eventType, s.callerName(), action, resource, "Succeeded")
+}
+
+// authorize checks the caller holds the required level on the bucket. Admin credentials
+// and a non-compliant CCC.ObjStor.CN01 skip the check. An object-level permission
+// (only settable when CCC.ObjStor.CN02 is non-compliant) can widen access.
+func (s *LocalObjectStorageService) authorize(bucketID string, obj *localObject, required iam.AccessLevel) error {
+	if s.identity == nil || !s.config.Compliant("CCC.ObjStor.CN01") {
+		return nil
+	}
+	level := s.iamService.AccessLevel(s.identity.UserName, bucketID)
+	if iam.HasAccess(level, required) {
+		return nil
+	}
+	if obj != nil && obj.permission != "" && iam.HasAccess(obj.permission, required) {
+		return nil
+	}
+	return fmt.Errorf("access denied: %s has %s access to bucket %s, %s required", s.identity.UserName, level, bucketID, required)
+}
+
+// authorizeAccount checks the caller holds the required level on any bucket (account-scoped operations)
+func (s *LocalObjectStorageService) authorizeAccount(required iam.AccessLevel) error {
+	if s.identity == nil || !s.config.Compliant("CCC.ObjStor.CN01") {
+		return nil
+	}
+	level := s.iamService.HighestAccessLevel(s.identity.UserName)
+	if !iam.HasAccess(level, required) {
+		return fmt.Errorf("access denied: %s has %s access, %s required", s.identity.UserName, level, required)
+	}
+	return nil
+}
+
+// ListBuckets lists all live buckets
+func (s *LocalObjectStorageService) ListBuckets(ctx context.Context) ([]Bucket, error) {
+	if err := s.authorizeAccount(iam.AccessLevelRead); err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	return sortedBuckets(s.store.buckets), nil
+}
+
+// CreateBucket creates a new bucket in the configured region
+func (s *LocalObjectStorageService) CreateBucket(ctx context.Context, bucketID string) (*Bucket, error) {
+	if err := s.authorizeAccount(iam.AccessLevelWrite); err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", bucketID, err)
+	}
+
+	s.store.mu.Lock()
+	if _, exists := s.store.buckets[bucketID]; exists {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to create bucket %s: bucket already exists", bucketID)
+	}
+	b := s.store.newBucket(bucketID)
+	s.store.buckets[bucketID] = b
+	delete(s.store.deletedBuckets, bucketID)
+	s.store.mu.Unlock()
+
+	s.record(logging.LocalEventAdmin, "CreateBucket", bucketID)
+	return &Bucket{ID: b.name, Name: b.name, Region: b.region}, nil
+}
+
+// DeleteBucket deletes a bucket. With CCC.ObjStor.CN03 compliant the bucket is soft-deleted
+// and can be restored with RestoreBucket.
+func (s *LocalObjectStorageService) DeleteBucket(ctx context.Context, bucketID string) error {
+	if err := s.authorize(bucketID, nil, iam.AccessLevelWrite); err != nil {
+		return fmt.Errorf("failed to delete bucket %s: %w", bucketID, err)
+	}
+
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return fmt.Errorf("failed to delete bucket %s: %w", bucketID, err)
+	}
+	delete(s.store.buckets, bucketID)
+	if s.config.Compliant("CCC.ObjStor.CN03") {
+		s.store.deletedBuckets[bucketID] = b
+	}
+	s.store.mu.Unlock()
+
+	s.record(logging.LocalEventAdmin, "DeleteBucket", bucketID)
+	return nil
+}
+
+// ListDeletedBuckets lists soft-deleted buckets
+func (s *LocalObjectStorageService) ListDeletedBuckets(ctx context.Context) ([]Bucket, error) {
+	if !s.config.Compliant("CCC.ObjStor.CN03") {
+		return nil, fmt.Errorf("bucket soft delete is not enabled - bucket deletion is immediate and permanent")
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	return sortedBuckets(s.store.deletedBuckets), nil
+}
+
+// RestoreBucket restores a soft-deleted bucket
+func (s *LocalObjectStorageService) RestoreBucket(ctx context.Context, bucketID string) error {
+	if !s.config.Compliant("CCC.ObjStor.CN03") {
+		return fmt.Errorf("bucket soft delete is not enabled - bucket %s cannot be restored", bucketID)
+	}
+
+	s.store.mu.Lock()
+	b, ok := s.store.deletedBuckets[bucketID]
+	if !ok {
+		s.store.mu.Unlock()
+		return fmt.Errorf("deleted bucket %s not found", bucketID)
+	}
+	if _, exists := s.store.buckets[bucketID]; exists {
+		s.store.mu.Unlock()
+		return fmt.Errorf("cannot restore bucket %s: a bucket with that name already exists", bucketID)
+	}
+	delete(s.store.deletedBuckets, bucketID)
+	s.store.buckets[bucketID] = b
+	s.store.mu.Unlock()
+
+	s.record(logging.LocalEventAdmin, "RestoreBucket", bucketID)
+	return nil
+}
+
+// GetBucketRegion gets the region where a bucket is located
+func (s *LocalObjectStorageService) GetBucketRegion(ctx context.Context, bucketID string) (string, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		return "", err
+	}
+	return b.region, nil
+}
+
+// GetBucketRetentionDurationDays returns the bucket's default retention in days
+func (s *LocalObjectStorageService) GetBucketRetentionDurationDays(ctx context.Context, bucketID string) (int, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		return 0, err
+	}
+	return b.retentionDays, nil
+}
+
+// SetBucketRetentionDurationDays changes the bucket's default retention. With CCC.ObjStor.CN03
+// compliant the retention policy is locked and cannot be modified.
+func (s *LocalObjectStorageService) SetBucketRetentionDurationDays(ctx context.Context, bucketID string, days int) error {
+	if s.config.Compliant("CCC.ObjStor.CN03") {
+		return fmt.Errorf("retention policy on bucket %s is locked and cannot be modified", bucketID)
+	}
+
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return err
+	}
+	b.retentionDays = days
+	s.store.mu.Unlock()
+
+	s.record(logging.LocalEventAdmin, "SetBucketRetention", bucketID)
+	return nil
+}
+
+// UpdateBucketPolicy records a policy change on the bucket
+func (s *LocalObjectStorageService) UpdateBucketPolicy(ctx context.Context, bucketID string, policyTag string) (*Bucket, error) {
+	if err := s.authorize(bucketID, nil, iam.AccessLevelAdmin); err != nil {
+		return nil, fmt.Errorf("failed to update policy on bucket %s: %w", bucketID, err)
+	}
+
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	s.store.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(logging.LocalEventAdmin, "UpdateBucketPolicy:"+policyTag, bucketID)
+	return &Bucket{ID: b.name, Name: b.name, Region: b.region}, nil
+}
+
+// ListObjects lists all live objects in a bucket
+func (s *LocalObjectStorageService) ListObjects(ctx context.Context, bucketID string) ([]Object, error) {
+	if err := s.authorize(bucketID, nil, iam.AccessLevelRead); err != nil {
+		return nil, fmt.Errorf("failed to list objects in bucket %s: %w", bucketID, err)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects in bucket %s: %w", bucketID, err)
+	}
+
+	names := make([]string, 0, len(b.objects))
+	for name, obj := range b.objects {
+		if !obj.deleted {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	objects := make([]Object, 0, len(names))
+	for _, name := range names {
+		latest := b.objects[name].versions[len(b.objects[name].versions)-1]
+		objects = append(objects, Object{
+			ID:       name,
+			BucketID: bucketID,
+			Name:     name,
+			Size:     int64(len(latest.data)),
+		})
+	}
+	return objects, nil
+}
+
+// CreateObject creates or overwrites an object. With CCC.ObjStor.CN05 compliant every write
+// adds a new version; with CCC.ObjStor.CN04 compliant the object is retained for the bucket's
+// retention period, and identity-scoped callers cannot overwrite it while it is retained.
+func (s *LocalObjectStorageService) CreateObject(ctx context.Context, bucketID string, objectID string, data string) (*Object, error) {
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to create object %s in bucket %s: %w", objectID, bucketID, err)
+	}
+	obj := b.objects[objectID]
+	if err := s.authorize(bucketID, obj, iam.AccessLevelWrite); err != nil {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to create object %s in bucket %s: %w", objectID, bucketID, err)
+	}
+	if obj != nil && !obj.deleted && s.identity != nil && time.Now().Before(obj.retainUntil) {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to create object %s in bucket %s: object exists and is protected by a retention lock until %s",
+			objectID, bucketID, obj.retainUntil.Format(time.RFC3339))
+	}
+
+	if obj == nil {
+		obj = &localObject{}
+		b.objects[objectID] = obj
+	}
+
+	version := localObjectVersion{data: data}
+	if s.config.Compliant("CCC.ObjStor.CN05") {
+		version.versionID = s.store.nextVersionID()
+		obj.versions = append(obj.versions, version)
+	} else {
+		obj.versions = []localObjectVersion{version}
+	}
+	obj.deleted = false
+	if s.config.Compliant("CCC.ObjStor.CN04") {
+		obj.retainUntil = time.Now().AddDate(0, 0, b.retentionDays)
+	}
+	s.store.mu.Unlock()
+
+	// Track for TearDown
+	s.createdMu.Lock()
+	s.createdObjs = append(s.createdObjs, struct{ bucket, object string }{bucketID, objectID})
+	s.createdMu.Unlock()
+
+	s.record(logging.LocalEventDataWrite, "PutObject", bucketID)
+
+	return &Object{
+		ID:                  objectID,
+		BucketID:            bucketID,
+		Name:                objectID,
+		Size:                int64(len(data)),
+		Data:                []string{data},
+		Encryption:          "AES256",
+		EncryptionAlgorithm: "AES256",
+		VersionID:           version.versionID,
+	}, nil
+}
+
+// ReadObject reads the latest version of an object
+func (s *LocalObjectStorageService) ReadObject(ctx context.Context, bucketID string, objectID string) (*Object, error) {
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to read object %s from bucket %s: %w", objectID, bucketID, err)
+	}
+	obj := b.objects[objectID]
+	if err := s.authorize(bucketID, obj, iam.AccessLevelRead); err != nil {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to read object %s from bucket %s: %w", objectID, bucketID, err)
+	}
+	if obj == nil || obj.deleted {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to read object %s from bucket %s: object not found", objectID, bucketID)
+	}
+	latest := obj.versions[len(obj.versions)-1]
+	s.store.mu.Unlock()
+
+	s.record(logging.LocalEventDataRead, "GetObject", bucketID)
+	return localObjectResult(bucketID, objectID, latest), nil
+}
+
+// ReadObjectAtVersion reads a specific version of an object, including versions of deleted objects
+func (s *LocalObjectStorageService) ReadObjectAtVersion(ctx context.Context, bucketID string, objectID string, versionID string) (*Object, error) {
+	if !s.config.Compliant("CCC.ObjStor.CN05") {
+		return nil, fmt.Errorf("failed to read object %s version %s from bucket %s: versioning is not enabled", objectID, versionID, bucketID)
+	}
+
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to read object %s version %s from bucket %s: %w", objectID, versionID, bucketID, err)
+	}
+	obj := b.objects[objectID]
+	if err := s.authorize(bucketID, obj, iam.AccessLevelRead); err != nil {
+		s.store.mu.Unlock()
+		return nil, fmt.Errorf("failed to read object %s version %s from bucket %s: %w", objectID, versionID, bucketID, err)
+	}
+	if obj != nil {
+		for _, v := range obj.versions {
+			if v.versionID == versionID {
+				s.store.mu.Unlock()
+				s.record(logging.LocalEventDataRead, "GetObjectVersion", bucketID)
+				return localObjectResult(bucketID, objectID, v), nil
+			}
+		}
+	}
+	s.store.mu.Unlock()
+	return nil, fmt.Errorf("failed to read object %s version %s from bucket %s: version not found", objectID, versionID, bucketID)
+}
+
+// DeleteObject deletes an object. Retained objects cannot be deleted while CCC.ObjStor.CN04 is
+// compliant; with CCC.ObjStor.CN05 compliant previous versions are kept.
+func (s *LocalObjectStorageService) DeleteObject(ctx context.Context, bucketID string, objectID string) error {
+	s.store.mu.Lock()
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		s.store.mu.Unlock()
+		return fmt.Errorf("failed to delete object %s from bucket %s: %w", objectID, bucketID, err)
+	}
+	obj := b.objects[objectID]
+	if err := s.authorize(bucketID, obj, iam.AccessLevelWrite); err != nil {
+		s.store.mu.Unlock()
+		return fmt.Errorf("failed to delete object %s from bucket %s: %w", objectID, bucketID, err)
+	}
+	if obj == nil || obj.deleted {
+		s.store.mu.Unlock()
+		return fmt.Errorf("failed to delete object %s from bucket %s: object not found", objectID, bucketID)
+	}
+	if time.Now().Before(obj.retainUntil) {
+		s.store.mu.Unlock()
+		return fmt.Errorf("failed to delete object %s from bucket %s: object is protected by a retention lock until %s",
+			objectID, bucketID, obj.retainUntil.Format(time.RFC3339))
+	}
+	if s.config.Compliant("CCC.ObjStor.CN05") {
+		obj.deleted = true
+	} else {
+		delete(b.objects, objectID)
+	}
+	s.store.mu.Unlock()
+
+	s.record(logging.LocalEventDataWrite, "DeleteObject", bucketID)
+	return nil
+}
+
+// GetObjectRetentionDurationDays returns the days remaining on the object's retention
+func (s *LocalObjectStorageService) GetObjectRetentionDurationDays(ctx context.Context, bucketID string, objectID string) (int, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		return 0, err
+	}
+	obj := b.objects[objectID]
+	if obj == nil {
+		return 0, fmt.Errorf("object %s not found in bucket %s", objectID, bucketID)
+	}
+	remaining := time.Until(obj.retainUntil)
+	if remaining <= 0 {
+		return 0, nil
+	}
+	return int((remaining + 24*time.Hour - 1) / (24 * time.Hour)), nil
+}
+
+// SetObjectPermission sets an object-level permission. With CCC.ObjStor.CN02 compliant
+// uniform bucket-level access is enforced and this always fails.
+func (s *LocalObjectStorageService) SetObjectPermission(ctx context.Context, bucketID string, objectID string, permissionLevel string) error {
+	if s.config.Compliant("CCC.ObjStor.CN02") {
+		return fmt.Errorf("object-level permissions are disabled - uniform bucket-level access is enforced on bucket %s", bucketID)
+	}
+	switch permissionLevel {
+	case "none", "read", "write":
+	default:
+		return fmt.Errorf("unsupported permission level: %s", permissionLevel)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		return err
+	}
+	obj := b.objects[objectID]
+	if obj == nil {
+		return fmt.Errorf("object %s not found in bucket %s", objectID, bucketID)
+	}
+	obj.permission = permissionLevel
+	return nil
+}
+
+// ListObjectVersions lists all versions of an object, including those of a deleted object
+func (s *LocalObjectStorageService) ListObjectVersions(ctx context.Context, bucketID string, objectID string) ([]ObjectVersion, error) {
+	if err := s.authorize(bucketID, nil, iam.AccessLevelRead); err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s in bucket %s: %w", objectID, bucketID, err)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	b, err := s.store.bucket(bucketID)
+	if err != nil {
+		return nil, err
+	}
+	obj := b.objects[objectID]
+	if obj == nil {
+		return []ObjectVersion{}, nil
+	}
+	versions := make([]ObjectVersion, 0, len(obj.versions))
+	for _, v := range obj.versions {
+		versions = append(versions, ObjectVersion{VersionID: v.versionID, ObjectID: objectID})
+	}
+	return versions, nil
+}
+
+// IsBucketVersioningEnabled reports whether object versioning is enabled (CN05.AR01)
+func (s *LocalObjectStorageService) IsBucketVersioningEnabled(ctx context.Context, bucketID string) (bool, error) {
+	if _, err := s.GetBucketRegion(ctx, bucketID); err != nil {
+		return false, err
+	}
+	return s.config.Compliant("CCC.ObjStor.CN05"), nil
+}
+
+// GetOrProvisionTestableResources returns the store's buckets as testable resources.
+// Only PerService params are returned: there is no endpoint to run TLS checks against,
+// and @Policy scenarios are excluded because their queries call the cloud CLIs.
+func (s *LocalObjectStorageService) GetOrProvisionTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	return s.DiscoverTestableResources(ctx)
+}
+
+// DiscoverTestableResources returns the store's buckets as testable resources
+func (s *LocalObjectStorageService) DiscoverTestableResources(ctx context.Context) ([]types.TestParams, error) {
+	buckets, err := s.ListBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+
+	resources := make([]types.TestParams, 0, len(buckets))
+	for _, bucket := range buckets {
+		resources = append(resources, types.TestParams{
+			ResourceName:        bucket.Name,
+			UID:                 bucket.ID,
+			ReportFile:          fmt.Sprintf("%s-service", bucket.Name),
+			ReportTitle:         bucket.Name,
+			ProviderServiceType: "local:object-storage",
+			ServiceType:         "object-storage",
+			CatalogTypes:        []string{"CCC.ObjStor"},
+			Labels:              s.config.Labels,
+			TagFilter:           []string{"@object-storage", "@PerService", "~@Policy"},
+			Instance:            s.instance,
+		})
+	}
+	return resources, nil
+}
+
+// CheckUserProvisioned always succeeds: local grants take effect immediately
+func (s *LocalObjectStorageService) CheckUserProvisioned(ctx context.Context) error {
+	return nil
+}
+
+// ElevateAccessForInspection is a no-op: there is no network access to elevate
+func (s *LocalObjectStorageService) ElevateAccessForInspection(ctx context.Context) error {
+	return nil
+}
+
+// ResetAccess is a no-op: there is no network access to reset
+func (s *LocalObjectStorageService) ResetAccess(ctx context.Context) error {
+	return nil
+}
+
+// UpdateResourcePolicy records a no-op policy update on every bucket
+func (s *LocalObjectStorageService) UpdateResourcePolicy(ctx context.Context) error {
+	buckets, err := s.ListBuckets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list buckets: %w", err)
+	}
+	if len(buckets) == 0 {
+		return fmt.Errorf("no buckets found to update policy")
+	}
+	for _, b := range buckets {
+		s.record(logging.LocalEventAdmin, "PutBucketPolicy", b.ID)
+	}
+	return nil
+}
+
+// TriggerDataWrite writes an object to the bucket to produce a data write event (CN04.AR02)
+func (s *LocalObjectStorageService) TriggerDataWrite(ctx context.Context, resourceID string) error {
+	objectID := fmt.Sprintf("ccc-data-write-%d.txt", time.Now().UnixNano())
+	_, err := s.CreateObject(ctx, resourceID, objectID, "data write trigger")
+	return err
+}
+
+// GetResourceRegion returns the bucket region (CN06.AR01)
+func (s *LocalObjectStorageService) GetResourceRegion(ctx context.Context, resourceID string) (string, error) {
+	return s.GetBucketRegion(ctx, resourceID)
+}
+
+// GetReplicationStatus returns replication status (CN08.AR01, CN08.AR02). With CCC.Core.CN08
+// compliant the bucket is replicated to the service's replica-region.
+func (s *LocalObjectStorageService) GetReplicationStatus(ctx context.Context, resourceID string) (*generic.ReplicationStatus, error) {
+	region, err := s.GetBucketRegion(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.config.Compliant("CCC.Core.CN08") || s.config.ReplicaRegion == "" {
+		return &generic.ReplicationStatus{
+			Locations:  []generic.LocationRegion{{Value: region}},
+			Status:     "Disabled",
+			SyncStatus: "Unknown",
+		}, nil
+	}
+	return &generic.ReplicationStatus{
+		Locations:  []generic.LocationRegion{{Value: region}, {Value: s.config.ReplicaRegion}},
+		Status:     "Enabled",
+		SyncStatus: "InSync",
+	}, nil
+}
+
+// TearDown purges objects created during testing, bypassing retention locks
+func (s *LocalObjectStorageService) TearDown(ctx context.Context) error {
+	s.createdMu.Lock()
+	objs := s.createdObjs
+	s.createdObjs = nil
+	s.createdMu.Unlock()
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	for _, r := range objs {
+		if b, ok := s.store.buckets[r.bucket]; ok {
+			delete(b.objects, r.object)
+		}
+	}
+	return nil
+}
+
+// sortedBuckets returns the buckets ordered by name (caller must hold the store lock)
+func sortedBuckets(buckets map[string]*localBucket) []Bucket {
+	out := make([]Bucket, 0, len(buckets))
+	for _, b := range buckets {
+		out = append(out, Bucket{ID: b.name, Name: b.name, Region: b.region})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// localObjectResult converts a stored version to an Object
+func localObjectResult(bucketID, objectID string, v localObjectVersion) *Object {
+	return &Object{
+		ID:        objectID,
+		BucketID:  bucketID,
+		Name:      objectID,
+		Size:      int64(len(v.data)),
+		Data:      []string{v.data},
+		VersionID: v.versionID,
+	}
+}
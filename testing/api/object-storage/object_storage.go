@@ -1,27 +1,21 @@
 package objstorage
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/logging"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
-// serviceParamString retrieves a string value from service params by camelCase key
-func serviceParamString(serviceParams map[string]interface{}, key string) string {
-	if serviceParams == nil {
-		return ""
-	}
-	if v, ok := serviceParams[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
-}
-
 // Bucket represents a storage bucket/container
 type Bucket struct {
-	ID     string // Unique identifier (name for AWS S3, Azure Storage Account + Container)
-	Name   string // Human-readable name
-	Region string // Geographic region
+	ID     string   // Unique identifier (name for AWS S3, Azure Storage Account + Container)
+	Name   string   // Human-readable name
+	Region string   // Geographic region
+	Labels []string // Tags (AWS, Azure storage account) or labels (GCP) as "key=value"
 }
 
 // ObjectVersion represents a version of an object when versioning is enabled
@@ -48,29 +42,107 @@ type Service interface {
 	generic.Service // Extends the base Service interface
 
 	// Bucket operations
-	ListBuckets() ([]Bucket, error)
-	CreateBucket(bucketID string) (*Bucket, error)
-	DeleteBucket(bucketID string) error
-	GetBucketRegion(bucketID string) (string, error)
-	GetBucketRetentionDurationDays(bucketID string) (int, error)
-	SetBucketRetentionDurationDays(bucketID string, days int) error
-	ListDeletedBuckets() ([]Bucket, error)
-	RestoreBucket(bucketID string) error
-	UpdateBucketPolicy(bucketID string, policyTag string) (*Bucket, error)
+	ListBuckets(ctx context.Context) ([]Bucket, error)
+	CreateBucket(ctx context.Context, bucketID string) (*Bucket, error)
+	DeleteBucket(ctx context.Context, bucketID string) error
+	GetBucketRegion(ctx context.Context, bucketID string) (string, error)
+	GetBucketRetentionDurationDays(ctx context.Context, bucketID string) (int, error)
+	SetBucketRetentionDurationDays(ctx context.Context, bucketID string, days int) error
+	ListDeletedBuckets(ctx context.Context) ([]Bucket, error)
+	RestoreBucket(ctx context.Context, bucketID string) error
+	UpdateBucketPolicy(ctx context.Context, bucketID string, policyTag string) (*Bucket, error)
 
 	// Object operations
-	ListObjects(bucketID string) ([]Object, error)
-	CreateObject(bucketID string, objectID string, data string) (*Object, error)
-	ReadObject(bucketID string, objectID string) (*Object, error)
-	ReadObjectAtVersion(bucketID string, objectID string, versionID string) (*Object, error)
-	DeleteObject(bucketID string, objectID string) error
-	GetObjectRetentionDurationDays(bucketID string, objectID string) (int, error)
+	ListObjects(ctx context.Context, bucketID string) ([]Object, error)
+	CreateObject(ctx context.Context, bucketID string, objectID string, data string) (*Object, error)
+	ReadObject(ctx context.Context, bucketID string, objectID string) (*Object, error)
+	ReadObjectAtVersion(ctx context.Context, bucketID string, objectID string, versionID string) (*Object, error)
+	DeleteObject(ctx context.Context, bucketID string, objectID string) error
+	GetObjectRetentionDurationDays(ctx context.Context, bucketID string, objectID string) (int, error)
 
 	// SetObjectPermission attempts to set object-level permissions
 	// AWS: Should fail if uniform bucket-level access is enforced (ACLs disabled)
 	// Azure: Always fails (doesn't support object-level permissions)
-	SetObjectPermission(bucketID string, objectID string, permissionLevel string) error
+	SetObjectPermission(ctx context.Context, bucketID string, objectID string, permissionLevel string) error
 
 	// ListObjectVersions lists all versions of an object (when versioning is enabled)
-	ListObjectVersions(bucketID string, objectID string) ([]ObjectVersion, error)
+	ListObjectVersions(ctx context.Context, bucketID string, objectID string) ([]ObjectVersion, error)
+}
+
+func init() {
+	generic.RegisterService(generic.ServiceDefinition{
+		Name:          "object-storage",
+		CatalogTypes:  []string{"CCC.ObjStor"},
+		TagFilter:     []string{"@object-storage", "@PerService"},
+		ElevateAccess: true,
+		Config:        types.ObjectStorageConfig{},
+		Providers: map[string]generic.ProviderImplementation{
+			"aws": {
+				New: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewAWSS3Service(env.Ctx, *env.Instance)
+					if err != nil {
+						return nil, err
+					}
+					return service, nil
+				},
+				NewWithIdentity: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewAWSS3ServiceWithCredentials(env.Ctx, *env.Instance, env.Identity)
+					if err != nil {
+						return nil, err
+					}
+					if err := service.ElevateAccessForInspection(env.Ctx); err != nil {
+						fmt.Printf("⚠️  Warning: Failed to elevate access for object-storage: %v\n", err)
+					}
+					return service, nil
+				},
+			},
+			"azure": {
+				New: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewAzureBlobService(env.Ctx, env.Instance)
+					if err != nil {
+						return nil, err
+					}
+					return service, nil
+				},
+				NewWithIdentity: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewAzureBlobServiceWithCredentials(env.Ctx, env.Instance.CloudParams(), *env.Instance, env.Identity)
+					if err != nil {
+						return nil, err
+					}
+					return service, nil
+				},
+			},
+			"gcp": {
+				New: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewGCPStorageService(env.Ctx, *env.Instance)
+					if err != nil {
+						return nil, err
+					}
+					return service, nil
+				},
+				NewWithIdentity: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewGCPStorageServiceWithCredentials(env.Ctx, *env.Instance, env.Identity)
+					if err != nil {
+						return nil, err
+					}
+					return service, nil
+				},
+			},
+			"local": {
+				New: func(env generic.ServiceEnv) (generic.Service, error) {
+					return NewLocalObjectStorageService(*env.Instance, sharedLocalStore(env), logging.SharedLocalAuditLog(env)), nil
+				},
+				NewWithIdentity: func(env generic.ServiceEnv) (generic.Service, error) {
+					return NewLocalObjectStorageServiceWithIdentity(*env.Instance, sharedLocalStore(env), logging.SharedLocalAuditLog(env), iam.SharedLocalIAMService(env), env.Identity), nil
+				},
+			},
+		},
+	})
+}
+
+// sharedLocalStore returns the in-memory buckets shared by the local object storage clients
+// of a factory, whichever identity they act as
+func sharedLocalStore(env generic.ServiceEnv) *LocalStore {
+	v, _ := env.Shared("local-object-store", func() (interface{}, error) { return NewLocalStore(*env.Instance), nil })
+	return v.(*LocalStore)
 }
@@ -1,6 +1,7 @@
 package vpc
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -8,21 +9,21 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
-func (s *AWSVPCService) CountDefaultVpcs() (int, error) {
-	vpcs, err := s.describeDefaultVpcs()
+func (s *AWSVPCService) CountDefaultVpcs(ctx context.Context) (int, error) {
+	vpcs, err := s.describeDefaultVpcs(ctx)
 	if err != nil {
 		return 0, err
 	}
 	return len(vpcs), nil
 }
 
-func (s *AWSVPCService) IsDefaultVpc(vpcID string) (bool, error) {
+func (s *AWSVPCService) IsDefaultVpc(ctx context.Context, vpcID string) (bool, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return false, fmt.Errorf("vpcID is required")
 	}
 
-	out, err := s.client.DescribeVpcs(s.ctx, &ec2.DescribeVpcsInput{
+	out, err := s.client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
 		VpcIds: []string{vpcIDStr},
 	})
 	if err != nil {
@@ -35,13 +36,13 @@ func (s *AWSVPCService) IsDefaultVpc(vpcID string) (bool, error) {
 	return aws.ToBool(out.Vpcs[0].IsDefault), nil
 }
 
-func (s *AWSVPCService) EvaluateDefaultVpcControl(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) EvaluateDefaultVpcControl(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	isDefault, err := s.IsDefaultVpc(vpcIDStr)
+	isDefault, err := s.IsDefaultVpc(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -67,8 +68,8 @@ func (s *AWSVPCService) EvaluateDefaultVpcControl(vpcID string) (map[string]inte
 	}, nil
 }
 
-func (s *AWSVPCService) ListDefaultVpcs() ([]DefaultVPC, error) {
-	vpcs, err := s.describeDefaultVpcs()
+func (s *AWSVPCService) ListDefaultVpcs(ctx context.Context) ([]DefaultVPC, error) {
+	vpcs, err := s.describeDefaultVpcs(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +84,7 @@ func (s *AWSVPCService) ListDefaultVpcs() ([]DefaultVPC, error) {
 	return out, nil
 }
 
-func (s *AWSVPCService) describeDefaultVpcs() ([]types.Vpc, error) {
+func (s *AWSVPCService) describeDefaultVpcs(ctx context.Context) ([]types.Vpc, error) {
 	input := &ec2.DescribeVpcsInput{
 		Filters: []types.Filter{
 			{
@@ -93,7 +94,7 @@ func (s *AWSVPCService) describeDefaultVpcs() ([]types.Vpc, error) {
 		},
 	}
 
-	resp, err := s.client.DescribeVpcs(s.ctx, input)
+	resp, err := s.client.DescribeVpcs(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe default VPCs: %w", err)
 	}
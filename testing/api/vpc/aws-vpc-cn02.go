@@ -1,21 +1,22 @@
 package vpc
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
-func (s *AWSVPCService) ListPublicSubnets(vpcID string) ([]interface{}, error) {
-	return s.listPublicSubnets(vpcID)
+func (s *AWSVPCService) ListPublicSubnets(ctx context.Context, vpcID string) ([]interface{}, error) {
+	return s.listPublicSubnets(ctx, vpcID)
 }
 
-func (s *AWSVPCService) SummarizePublicSubnets(vpcID string) (string, error) {
+func (s *AWSVPCService) SummarizePublicSubnets(ctx context.Context, vpcID string) (string, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return "", fmt.Errorf("vpcID is required")
 	}
 
-	outcome, err := s.EvaluatePublicSubnetDefaultIPControl(vpcIDStr)
+	outcome, err := s.EvaluatePublicSubnetDefaultIPControl(ctx, vpcIDStr)
 	if err != nil {
 		return "", err
 	}
@@ -29,13 +30,13 @@ func (s *AWSVPCService) SummarizePublicSubnets(vpcID string) (string, error) {
 	), nil
 }
 
-func (s *AWSVPCService) EvaluatePublicSubnetDefaultIPControl(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) EvaluatePublicSubnetDefaultIPControl(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	publicSubnets, err := s.listPublicSubnets(vpcIDStr)
+	publicSubnets, err := s.listPublicSubnets(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
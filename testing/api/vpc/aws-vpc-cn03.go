@@ -1,6 +1,7 @@
 package vpc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,7 +21,7 @@ type cn03TrialMatrix struct {
 	DisallowedRequesterVpcIDs []string
 }
 
-func (s *AWSVPCService) EvaluatePeerAgainstAllowList(peerVpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) EvaluatePeerAgainstAllowList(ctx context.Context, peerVpcID string) (map[string]interface{}, error) {
 	peerVpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", peerVpcID))
 	if peerVpcIDStr == "" {
 		return nil, fmt.Errorf("peerVpcID is required")
@@ -56,12 +57,20 @@ func (s *AWSVPCService) EvaluatePeerAgainstAllowList(peerVpcID string) (map[stri
 	}, nil
 }
 
-func (s *AWSVPCService) AttemptVpcPeeringDryRun(requesterVpcID, peerVpcID string) (map[string]interface{}, error) {
-	return s.attemptVpcPeeringDryRunWithOwner(requesterVpcID, peerVpcID, cn03PeerOwnerID())
+func (s *AWSVPCService) AttemptVpcPeeringDryRun(ctx context.Context, requesterVpcID, peerVpcID string) (map[string]interface{}, error) {
+	return s.attemptVpcPeeringDryRunWithOwner(ctx, requesterVpcID, peerVpcID, cn03PeerOwnerID())
 }
 
-func (s *AWSVPCService) RunVpcPeeringDryRunTrialsFromFile(filePath string) (map[string]interface{}, error) {
-	matrix, resolvedPath, err := s.loadCN03TrialMatrix(filePath)
+func (s *AWSVPCService) RunVpcPeeringDryRunTrialsFromFile(ctx context.Context, filePath string) (map[string]interface{}, error) {
+	return runCN03TrialMatrix(filePath, func(requesterVpcID, peerVpcID, peerOwnerID string) (map[string]interface{}, error) {
+		return s.attemptVpcPeeringDryRunWithOwner(ctx, requesterVpcID, peerVpcID, peerOwnerID)
+	})
+}
+
+// runCN03TrialMatrix loads the trial matrix from filePath and dry-runs every requester
+// against the receiver using dryRun, comparing each outcome with the matrix expectation.
+func runCN03TrialMatrix(filePath string, dryRun func(requesterVpcID, peerVpcID, peerOwnerID string) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	matrix, resolvedPath, err := loadCN03TrialMatrix(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +83,7 @@ func (s *AWSVPCService) RunVpcPeeringDryRunTrialsFromFile(filePath string) (map[
 
 	runTrials := func(requesterIDs []string, expectedAllowed bool) error {
 		for _, requesterID := range requesterIDs {
-			evidence, dryRunErr := s.attemptVpcPeeringDryRunWithOwner(requesterID, matrix.ReceiverVpcID, matrix.PeerOwnerID)
+			evidence, dryRunErr := dryRun(requesterID, matrix.ReceiverVpcID, matrix.PeerOwnerID)
 			if dryRunErr != nil {
 				return dryRunErr
 			}
@@ -124,7 +133,7 @@ func (s *AWSVPCService) RunVpcPeeringDryRunTrialsFromFile(filePath string) (map[
 	}, nil
 }
 
-func (s *AWSVPCService) attemptVpcPeeringDryRunWithOwner(requesterVpcID, peerVpcID, peerOwnerID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) attemptVpcPeeringDryRunWithOwner(ctx context.Context, requesterVpcID, peerVpcID, peerOwnerID string) (map[string]interface{}, error) {
 	requesterVpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", requesterVpcID))
 	peerVpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", peerVpcID))
 	peerOwnerIDStr := strings.TrimSpace(fmt.Sprintf("%v", peerOwnerID))
@@ -157,7 +166,7 @@ func (s *AWSVPCService) attemptVpcPeeringDryRunWithOwner(requesterVpcID, peerVpc
 		"Reason":         "request denied",
 	}
 
-	_, err := s.client.CreateVpcPeeringConnection(s.ctx, input)
+	_, err := s.client.CreateVpcPeeringConnection(ctx, input)
 	if err == nil {
 		evidence["DryRunAllowed"] = true
 		evidence["ExitCode"] = 0
@@ -203,6 +212,12 @@ func (s *AWSVPCService) enrichCN03EnforcementEvidence(requesterVpcID string, evi
 		return evidence
 	}
 
+	return applyCN03Expectation(requesterVpcID, entries, evidence)
+}
+
+// applyCN03Expectation compares the dry-run outcome in evidence with the allow-list
+// expectation for requesterVpcID and records the guardrail alignment or conflict.
+func applyCN03Expectation(requesterVpcID string, entries []cn03AllowedVpcEntry, evidence map[string]interface{}) map[string]interface{} {
 	allowListDefined := len(entries) > 0
 	requesterInAllowList := false
 	for _, e := range entries {
@@ -258,7 +273,7 @@ func (s *AWSVPCService) enrichCN03EnforcementEvidence(requesterVpcID string, evi
 	return evidence
 }
 
-func (s *AWSVPCService) loadCN03TrialMatrix(filePath string) (cn03TrialMatrix, string, error) {
+func loadCN03TrialMatrix(filePath string) (cn03TrialMatrix, string, error) {
 	resolvedPath := strings.TrimSpace(filePath)
 	if resolvedPath == "" {
 		resolvedPath = strings.TrimSpace(os.Getenv("CN03_PEER_TRIAL_MATRIX_FILE"))
@@ -393,7 +408,7 @@ func (s *AWSVPCService) resolveCN03AllowedVpcEntriesWithOrigin() ([]cn03AllowedV
 	return s.resolveCN03VpcEntriesWithOrigin(
 		[]string{"CN03_ALLOWED_REQUESTER_VPC_ID_", "CN03_ALLOWED_PEER_VPC_ID_"},
 		[]string{"CN03_ALLOWED_REQUESTER_VPC_IDS", "CN03_ALLOWED_PEER_VPC_IDS"},
-		"cn03-allowed-requester-vpc-ids",
+		s.instance.VpcServiceConfig().Cn03AllowedRequesterVpcIds,
 		func(matrix cn03TrialMatrix) []string { return matrix.AllowedRequesterVpcIDs },
 	)
 }
@@ -404,19 +419,19 @@ func (s *AWSVPCService) resolveCN03DisallowedVpcEntriesWithOrigin() ([]cn03Allow
 	return s.resolveCN03VpcEntriesWithOrigin(
 		[]string{"CN03_DISALLOWED_REQUESTER_VPC_ID_"},
 		[]string{"CN03_DISALLOWED_REQUESTER_VPC_IDS"},
-		"cn03-disallowed-requester-vpc-ids",
+		s.instance.VpcServiceConfig().Cn03DisallowedRequesterVpcIds,
 		func(matrix cn03TrialMatrix) []string { return matrix.DisallowedRequesterVpcIDs },
 	)
 }
 
 // resolveCN03VpcEntriesWithOrigin is the shared resolver for both allow and
 // disallow lists. indexedPrefixes are scanned for indexed env vars; csvEnvKeys
-// are read as comma-separated lists; yamlKey is the environment.yaml vpc
-// service property key; matrixFn extracts the relevant IDs from the trial matrix.
+// are read as comma-separated lists; yamlIDs are the IDs from the environment.yaml
+// vpc service block; matrixFn extracts the relevant IDs from the trial matrix.
 func (s *AWSVPCService) resolveCN03VpcEntriesWithOrigin(
 	indexedPrefixes []string,
 	csvEnvKeys []string,
-	yamlKey string,
+	yamlIDs []string,
 	matrixFn func(cn03TrialMatrix) []string,
 ) ([]cn03AllowedVpcEntry, error) {
 	seen := make(map[string]struct{})
@@ -438,7 +453,7 @@ func (s *AWSVPCService) resolveCN03VpcEntriesWithOrigin(
 
 	// terraform-fixture: trial matrix file (also written by export-cn03-artifacts.sh)
 	if path := strings.TrimSpace(os.Getenv("CN03_PEER_TRIAL_MATRIX_FILE")); path != "" {
-		if matrix, _, err := s.loadCN03TrialMatrix(path); err == nil {
+		if matrix, _, err := loadCN03TrialMatrix(path); err == nil {
 			add(matrixFn(matrix), "terraform-fixture")
 		}
 	}
@@ -449,11 +464,7 @@ func (s *AWSVPCService) resolveCN03VpcEntriesWithOrigin(
 	}
 
 	// yaml-guardrail: defined in environment.yaml vpc service properties
-	if svcProps := s.instance.ServiceProperties("vpc"); svcProps != nil {
-		if raw, ok := svcProps[yamlKey]; ok {
-			add(cn03StringSlice(raw), "yaml-guardrail")
-		}
-	}
+	add(yamlIDs, "yaml-guardrail")
 
 	return entries, nil
 }
@@ -461,17 +472,24 @@ func (s *AWSVPCService) resolveCN03VpcEntriesWithOrigin(
 // ValidateAllowListClassification evaluates every VPC in the CN03 allow-list
 // from all sources and returns an aggregate result with per-VPC origin info.
 // Attach "{result.Results}" to the test output to see the full per-VPC breakdown.
-func (s *AWSVPCService) ValidateAllowListClassification() (map[string]interface{}, error) {
+func (s *AWSVPCService) ValidateAllowListClassification(ctx context.Context) (map[string]interface{}, error) {
 	entries, err := s.resolveCN03AllowedVpcEntriesWithOrigin()
 	if err != nil {
 		return nil, err
 	}
+	return classifyCN03AllowList(entries, func(peerVpcID string) (map[string]interface{}, error) {
+		return s.EvaluatePeerAgainstAllowList(ctx, peerVpcID)
+	})
+}
 
+// classifyCN03AllowList evaluates each allow-list entry with evaluate and aggregates
+// the per-VPC results.
+func classifyCN03AllowList(entries []cn03AllowedVpcEntry, evaluate func(peerVpcID string) (map[string]interface{}, error)) (map[string]interface{}, error) {
 	results := make([]interface{}, 0, len(entries))
 	misclassified := make([]string, 0)
 
 	for _, entry := range entries {
-		eval, err := s.EvaluatePeerAgainstAllowList(entry.VpcID)
+		eval, err := evaluate(entry.VpcID)
 		if err != nil {
 			return nil, err
 		}
@@ -496,23 +514,23 @@ func (s *AWSVPCService) ValidateAllowListClassification() (map[string]interface{
 // against receiverVpcID and returns an aggregate enforcement result with per-VPC
 // origin info. A guardrail mismatch (disallowed VPC was permitted) counts as a
 // violation. Attach "{result.Results}" to see the full per-VPC breakdown.
-func (s *AWSVPCService) ValidateDisallowListEnforcement(receiverVpcID string) (map[string]interface{}, error) {
-	return s.validateEnforcementBatch(receiverVpcID, false)
+func (s *AWSVPCService) ValidateDisallowListEnforcement(ctx context.Context, receiverVpcID string) (map[string]interface{}, error) {
+	return s.validateEnforcementBatch(ctx, receiverVpcID, false)
 }
 
 // ValidateAllowListEnforcement dry-runs every VPC in the CN03 allow-list
 // against receiverVpcID and returns an aggregate enforcement result with per-VPC
 // origin info. A guardrail mismatch (allowed VPC was denied) counts as a
 // violation. Attach "{result.Results}" to see the full per-VPC breakdown.
-func (s *AWSVPCService) ValidateAllowListEnforcement(receiverVpcID string) (map[string]interface{}, error) {
-	return s.validateEnforcementBatch(receiverVpcID, true)
+func (s *AWSVPCService) ValidateAllowListEnforcement(ctx context.Context, receiverVpcID string) (map[string]interface{}, error) {
+	return s.validateEnforcementBatch(ctx, receiverVpcID, true)
 }
 
 // validateEnforcementBatch is the shared implementation for both enforcement
 // validators. When expectAllowed is false, entries come from the disallow-list
 // and the passing condition is DryRunAllowed=false. When expectAllowed is true,
 // entries come from the allow-list and the passing condition is DryRunAllowed=true.
-func (s *AWSVPCService) validateEnforcementBatch(receiverVpcID string, expectAllowed bool) (map[string]interface{}, error) {
+func (s *AWSVPCService) validateEnforcementBatch(ctx context.Context, receiverVpcID string, expectAllowed bool) (map[string]interface{}, error) {
 	receiverVpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", receiverVpcID))
 	if receiverVpcIDStr == "" {
 		return nil, fmt.Errorf("receiverVpcID is required")
@@ -532,12 +550,20 @@ func (s *AWSVPCService) validateEnforcementBatch(receiverVpcID string, expectAll
 		return nil, err
 	}
 
+	peerOwnerID := cn03PeerOwnerID()
+	return summarizeCN03Enforcement(receiverVpcIDStr, listType, expectAllowed, entries, func(requesterVpcID string) (map[string]interface{}, error) {
+		return s.attemptVpcPeeringDryRunWithOwner(ctx, requesterVpcID, receiverVpcIDStr, peerOwnerID)
+	})
+}
+
+// summarizeCN03Enforcement dry-runs each list entry with dryRun and aggregates guardrail
+// mismatches into the enforcement result returned by the Validate*Enforcement methods.
+func summarizeCN03Enforcement(receiverVpcIDStr, listType string, expectAllowed bool, entries []cn03AllowedVpcEntry, dryRun func(requesterVpcID string) (map[string]interface{}, error)) (map[string]interface{}, error) {
 	results := make([]interface{}, 0, len(entries))
 	violations := make([]string, 0)
-	peerOwnerID := cn03PeerOwnerID()
 
 	for _, entry := range entries {
-		evidence, dryRunErr := s.attemptVpcPeeringDryRunWithOwner(entry.VpcID, receiverVpcIDStr, peerOwnerID)
+		evidence, dryRunErr := dryRun(entry.VpcID)
 		if dryRunErr != nil {
 			return nil, dryRunErr
 		}
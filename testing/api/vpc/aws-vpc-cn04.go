@@ -1,6 +1,7 @@
 package vpc
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -10,25 +11,25 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
-func (s *AWSVPCService) ListVpcFlowLogs(vpcID string) ([]interface{}, error) {
-	return s.listVpcFlowLogs(vpcID)
+func (s *AWSVPCService) ListVpcFlowLogs(ctx context.Context, vpcID string) ([]interface{}, error) {
+	return s.listVpcFlowLogs(ctx, vpcID)
 }
 
-func (s *AWSVPCService) HasActiveAllTrafficFlowLogs(vpcID string) (bool, error) {
-	outcome, err := s.EvaluateVpcFlowLogsControl(vpcID)
+func (s *AWSVPCService) HasActiveAllTrafficFlowLogs(ctx context.Context, vpcID string) (bool, error) {
+	outcome, err := s.EvaluateVpcFlowLogsControl(ctx, vpcID)
 	if err != nil {
 		return false, err
 	}
 	return boolFromEvidence(outcome["Compliant"]), nil
 }
 
-func (s *AWSVPCService) SummarizeVpcFlowLogs(vpcID string) (string, error) {
+func (s *AWSVPCService) SummarizeVpcFlowLogs(ctx context.Context, vpcID string) (string, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return "", fmt.Errorf("vpcID is required")
 	}
 
-	outcome, err := s.EvaluateVpcFlowLogsControl(vpcIDStr)
+	outcome, err := s.EvaluateVpcFlowLogsControl(ctx, vpcIDStr)
 	if err != nil {
 		return "", err
 	}
@@ -42,13 +43,13 @@ func (s *AWSVPCService) SummarizeVpcFlowLogs(vpcID string) (string, error) {
 	), nil
 }
 
-func (s *AWSVPCService) EvaluateVpcFlowLogsControl(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) EvaluateVpcFlowLogsControl(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", vpcID))
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	flowLogs, err := s.listVpcFlowLogs(vpcIDStr)
+	flowLogs, err := s.listVpcFlowLogs(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -99,13 +100,13 @@ func (s *AWSVPCService) EvaluateVpcFlowLogsControl(vpcID string) (map[string]int
 	}, nil
 }
 
-func (s *AWSVPCService) PrepareFlowLogDeliveryObservation(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) PrepareFlowLogDeliveryObservation(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", vpcID))
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	flowLogs, err := s.listVpcFlowLogs(vpcIDStr)
+	flowLogs, err := s.listVpcFlowLogs(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -140,13 +141,13 @@ func (s *AWSVPCService) PrepareFlowLogDeliveryObservation(vpcID string) (map[str
 	}, nil
 }
 
-func (s *AWSVPCService) GenerateTestTraffic(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) GenerateTestTraffic(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", vpcID))
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	subnetSelection, err := s.SelectPublicSubnetForTest(vpcIDStr)
+	subnetSelection, err := s.SelectPublicSubnetForTest(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +156,7 @@ func (s *AWSVPCService) GenerateTestTraffic(vpcID string) (map[string]interface{
 		return nil, fmt.Errorf("no subnet selected for VPC %s", vpcIDStr)
 	}
 
-	resource, err := s.CreateTestResourceInSubnet(subnetID)
+	resource, err := s.CreateTestResourceInSubnet(ctx, subnetID)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +168,8 @@ func (s *AWSVPCService) GenerateTestTraffic(vpcID string) (map[string]interface{
 	// Give the launched resource a brief window to emit baseline network events.
 	time.Sleep(10 * time.Second)
 
-	externalIPEvidence, inspectErr := s.GetResourceExternalIpAssignment(resourceID)
-	cleanupResult, cleanupErr := s.DeleteTestResource(resourceID)
+	externalIPEvidence, inspectErr := s.GetResourceExternalIpAssignment(ctx, resourceID)
+	cleanupResult, cleanupErr := s.DeleteTestResource(ctx, resourceID)
 
 	out := map[string]interface{}{
 		"VpcId":        vpcIDStr,
@@ -195,13 +196,13 @@ func (s *AWSVPCService) GenerateTestTraffic(vpcID string) (map[string]interface{
 	return out, nil
 }
 
-func (s *AWSVPCService) ObserveRecentFlowLogDelivery(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) ObserveRecentFlowLogDelivery(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", vpcID))
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	flowLogs, err := s.listVpcFlowLogs(vpcIDStr)
+	flowLogs, err := s.listVpcFlowLogs(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -235,13 +236,13 @@ func (s *AWSVPCService) ObserveRecentFlowLogDelivery(vpcID string) (map[string]i
 	}, nil
 }
 
-func (s *AWSVPCService) listVpcFlowLogs(vpcID string) ([]interface{}, error) {
+func (s *AWSVPCService) listVpcFlowLogs(ctx context.Context, vpcID string) ([]interface{}, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	out, err := s.client.DescribeFlowLogs(s.ctx, &ec2.DescribeFlowLogsInput{
+	out, err := s.client.DescribeFlowLogs(ctx, &ec2.DescribeFlowLogsInput{
 		Filter: []types.Filter{
 			{
 				Name:   aws.String("resource-id"),
@@ -1,6 +1,7 @@
 package vpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/smithy-go"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
 )
 
 // ── TestResourceService implementation ──────────────────────────────────────
@@ -21,13 +23,13 @@ import (
 // Private helpers (listPublicSubnets, describeInstance, etc.) are also used by
 // CN02's public API methods (ListPublicSubnets, EvaluatePublicSubnetDefaultIPControl).
 
-func (s *AWSVPCService) SelectPublicSubnetForTest(vpcID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) SelectPublicSubnetForTest(ctx context.Context, vpcID string) (map[string]interface{}, error) {
 	vpcIDStr := strings.TrimSpace(fmt.Sprintf("%v", vpcID))
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	publicSubnets, err := s.listPublicSubnets(vpcIDStr)
+	publicSubnets, err := s.listPublicSubnets(ctx, vpcIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -61,13 +63,13 @@ func (s *AWSVPCService) SelectPublicSubnetForTest(vpcID string) (map[string]inte
 	}, nil
 }
 
-func (s *AWSVPCService) CreateTestResourceInSubnet(subnetID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) CreateTestResourceInSubnet(ctx context.Context, subnetID string) (map[string]interface{}, error) {
 	subnetIDStr := strings.TrimSpace(fmt.Sprintf("%v", subnetID))
 	if subnetIDStr == "" {
 		return nil, fmt.Errorf("subnetID is required")
 	}
 
-	amiID, err := s.resolveTestAmiID()
+	amiID, err := s.resolveTestAmiID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("could not resolve AMI for test instance: %w", err)
 	}
@@ -91,8 +93,11 @@ func (s *AWSVPCService) CreateTestResourceInSubnet(subnetID string) (map[string]
 			},
 		},
 	}
+	for key, value := range journal.Tags() {
+		input.TagSpecifications[0].Tags = append(input.TagSpecifications[0].Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
 
-	out, err := s.client.RunInstances(s.ctx, input)
+	out, err := s.client.RunInstances(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create test resource in subnet %s: %w", subnetIDStr, err)
 	}
@@ -104,9 +109,10 @@ func (s *AWSVPCService) CreateTestResourceInSubnet(subnetID string) (map[string]
 	if resourceID == "" {
 		return nil, fmt.Errorf("failed to create test resource in subnet %s: missing instance id", subnetIDStr)
 	}
+	s.trackInstance(resourceID, true)
 
 	// Best-effort wait so subsequent describe calls have stable state.
-	_ = s.waitForInstanceTerminalOrRunning(resourceID, 2*time.Minute)
+	_ = s.waitForInstanceTerminalOrRunning(ctx, resourceID, 2*time.Minute)
 
 	return map[string]interface{}{
 		"ResourceId":   resourceID,
@@ -117,13 +123,91 @@ func (s *AWSVPCService) CreateTestResourceInSubnet(subnetID string) (map[string]
 	}, nil
 }
 
-func (s *AWSVPCService) GetResourceExternalIpAssignment(resourceID string) (map[string]interface{}, error) {
+// trackInstance records (or forgets) a test instance so TearDown can terminate any left
+// behind, and journals it so cleanup can terminate it if this process dies first
+func (s *AWSVPCService) trackInstance(instanceID string, created bool) {
+	s.createdMu.Lock()
+	defer s.createdMu.Unlock()
+	if created {
+		s.createdInstances[instanceID] = true
+		journal.Created(s.instance, "vpc", journal.KindInstance, instanceID, nil)
+	} else {
+		delete(s.createdInstances, instanceID)
+		journal.Deleted(s.instance, journal.KindInstance, instanceID)
+	}
+}
+
+// Sweep terminates a test instance journaled by an earlier run
+func (s *AWSVPCService) Sweep(ctx context.Context, entry journal.Entry) error {
+	if entry.Kind != journal.KindInstance {
+		return fmt.Errorf("cannot sweep %s resources", entry.Kind)
+	}
+	_, err := s.client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{entry.ID}})
+	if err != nil && !isEC2NotFoundError(err) {
+		return fmt.Errorf("failed to terminate test instance %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// FindTagged lists the test instances carrying the run-ID tag that are not yet terminated
+func (s *AWSVPCService) FindTagged(ctx context.Context) ([]journal.Entry, error) {
+	var found []journal.Entry
+	pager := ec2.NewDescribeInstancesPaginator(s.client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("tag-key"), Values: []string{journal.TagKey}},
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+		},
+	})
+	for pager.HasMorePages() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list test instances: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				for _, tag := range instance.Tags {
+					if aws.ToString(tag.Key) == journal.TagKey {
+						found = append(found, journal.Found(s.instance, "vpc", journal.KindInstance, aws.ToString(instance.InstanceId), aws.ToString(tag.Value)))
+					}
+				}
+			}
+		}
+	}
+	return found, nil
+}
+
+// TearDown terminates test instances that were created but never deleted, e.g. because
+// the scenario failed or the run was interrupted between create and delete
+func (s *AWSVPCService) TearDown(ctx context.Context) error {
+	s.createdMu.Lock()
+	ids := make([]string, 0, len(s.createdInstances))
+	for id := range s.createdInstances {
+		ids = append(ids, id)
+	}
+	s.createdMu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sort.Strings(ids)
+	fmt.Printf("🧹 Terminating %d leftover VPC test instance(s): %s\n", len(ids), strings.Join(ids, ", "))
+	_, err := s.client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: ids})
+	if err != nil && !isEC2NotFoundError(err) {
+		return fmt.Errorf("failed to terminate test instances %s: %w", strings.Join(ids, ", "), err)
+	}
+	for _, id := range ids {
+		s.trackInstance(id, false)
+	}
+	return nil
+}
+
+func (s *AWSVPCService) GetResourceExternalIpAssignment(ctx context.Context, resourceID string) (map[string]interface{}, error) {
 	resourceIDStr := strings.TrimSpace(fmt.Sprintf("%v", resourceID))
 	if resourceIDStr == "" {
 		return nil, fmt.Errorf("resourceID is required")
 	}
 
-	instance, err := s.describeInstance(resourceIDStr)
+	instance, err := s.describeInstance(ctx, resourceIDStr)
 	if err != nil {
 		return nil, err
 	}
@@ -140,15 +224,18 @@ func (s *AWSVPCService) GetResourceExternalIpAssignment(resourceID string) (map[
 	}, nil
 }
 
-func (s *AWSVPCService) DeleteTestResource(resourceID string) (map[string]interface{}, error) {
+func (s *AWSVPCService) DeleteTestResource(ctx context.Context, resourceID string) (map[string]interface{}, error) {
 	resourceIDStr := strings.TrimSpace(fmt.Sprintf("%v", resourceID))
 	if resourceIDStr == "" {
 		return nil, fmt.Errorf("resourceID is required")
 	}
 
-	_, err := s.client.TerminateInstances(s.ctx, &ec2.TerminateInstancesInput{
+	_, err := s.client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{
 		InstanceIds: []string{resourceIDStr},
 	})
+	if err == nil || isEC2NotFoundError(err) {
+		s.trackInstance(resourceIDStr, false)
+	}
 	if err != nil {
 		if isEC2NotFoundError(err) {
 			return map[string]interface{}{
@@ -171,7 +258,7 @@ func (s *AWSVPCService) DeleteTestResource(resourceID string) (map[string]interf
 		}, nil
 	}
 
-	waitErr := s.waitForInstanceTermination(resourceIDStr, waitTimeout)
+	waitErr := s.waitForInstanceTermination(ctx, resourceIDStr, waitTimeout)
 	if waitErr != nil {
 		return map[string]interface{}{
 			"ResourceId":    resourceIDStr,
@@ -192,13 +279,13 @@ func (s *AWSVPCService) DeleteTestResource(resourceID string) (map[string]interf
 
 // listPublicSubnets returns all subnets in vpcID that have a route to an IGW.
 // Also used by CN02 (ListPublicSubnets, EvaluatePublicSubnetDefaultIPControl).
-func (s *AWSVPCService) listPublicSubnets(vpcID string) ([]interface{}, error) {
+func (s *AWSVPCService) listPublicSubnets(ctx context.Context, vpcID string) ([]interface{}, error) {
 	vpcIDStr := fmt.Sprintf("%v", vpcID)
 	if vpcIDStr == "" {
 		return nil, fmt.Errorf("vpcID is required")
 	}
 
-	subnetsOut, err := s.client.DescribeSubnets(s.ctx, &ec2.DescribeSubnetsInput{
+	subnetsOut, err := s.client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
 		Filters: []types.Filter{
 			{
 				Name:   aws.String("vpc-id"),
@@ -217,7 +304,7 @@ func (s *AWSVPCService) listPublicSubnets(vpcID string) ([]interface{}, error) {
 			continue
 		}
 
-		isPublic, routeTableID, err := s.isSubnetPublic(vpcIDStr, subnetID)
+		isPublic, routeTableID, err := s.isSubnetPublic(ctx, vpcIDStr, subnetID)
 		if err != nil {
 			return nil, err
 		}
@@ -238,8 +325,8 @@ func (s *AWSVPCService) listPublicSubnets(vpcID string) ([]interface{}, error) {
 
 // isSubnetPublic reports whether the subnet has a 0.0.0.0/0 route via an IGW.
 // Checks the subnet-specific route table first, falling back to the VPC main table.
-func (s *AWSVPCService) isSubnetPublic(vpcID, subnetID string) (bool, string, error) {
-	rtOut, err := s.client.DescribeRouteTables(s.ctx, &ec2.DescribeRouteTablesInput{
+func (s *AWSVPCService) isSubnetPublic(ctx context.Context, vpcID, subnetID string) (bool, string, error) {
+	rtOut, err := s.client.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
 		Filters: []types.Filter{
 			{
 				Name:   aws.String("association.subnet-id"),
@@ -256,7 +343,7 @@ func (s *AWSVPCService) isSubnetPublic(vpcID, subnetID string) (bool, string, er
 		routeTables = rtOut.RouteTables
 	} else {
 		// Fall back to the main route table for the VPC.
-		mainOut, err := s.client.DescribeRouteTables(s.ctx, &ec2.DescribeRouteTablesInput{
+		mainOut, err := s.client.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
 			Filters: []types.Filter{
 				{
 					Name:   aws.String("vpc-id"),
@@ -291,8 +378,8 @@ func (s *AWSVPCService) isSubnetPublic(vpcID, subnetID string) (bool, string, er
 	return false, "", nil
 }
 
-func (s *AWSVPCService) describeInstance(instanceID string) (types.Instance, error) {
-	out, err := s.client.DescribeInstances(s.ctx, &ec2.DescribeInstancesInput{
+func (s *AWSVPCService) describeInstance(ctx context.Context, instanceID string) (types.Instance, error) {
+	out, err := s.client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
 		InstanceIds: []string{instanceID},
 	})
 	if err != nil {
@@ -316,10 +403,10 @@ func (s *AWSVPCService) describeInstance(instanceID string) (types.Instance, err
 // waitForInstanceTerminalOrRunning waits until the instance reaches a terminal
 // or stable running state (running, stopped, terminated, shutting-down).
 // Used after RunInstances to ensure subsequent describe calls see stable state.
-func (s *AWSVPCService) waitForInstanceTerminalOrRunning(instanceID string, timeout time.Duration) error {
+func (s *AWSVPCService) waitForInstanceTerminalOrRunning(ctx context.Context, instanceID string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	for {
-		instance, err := s.describeInstance(instanceID)
+		instance, err := s.describeInstance(ctx, instanceID)
 		if err != nil {
 			return err
 		}
@@ -340,10 +427,10 @@ func (s *AWSVPCService) waitForInstanceTerminalOrRunning(instanceID string, time
 // waitForInstanceTermination waits until the instance is terminated or absent.
 // Distinguished from waitForInstanceTerminalOrRunning: this polls specifically
 // for a terminated outcome and returns an error on timeout.
-func (s *AWSVPCService) waitForInstanceTermination(instanceID string, timeout time.Duration) error {
+func (s *AWSVPCService) waitForInstanceTermination(ctx context.Context, instanceID string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	for {
-		instance, err := s.describeInstance(instanceID)
+		instance, err := s.describeInstance(ctx, instanceID)
 		if err != nil {
 			if isEC2NotFoundError(err) || strings.Contains(strings.ToLower(err.Error()), "not found") {
 				return nil
@@ -387,12 +474,12 @@ func cnTestAmiID() string {
 // resolveTestAmiID returns the AMI ID to use for test instances.
 // Uses CN_TEST_AMI_ID (or fallback env vars) if set; otherwise queries EC2 for
 // the latest Amazon Linux 2023 x86_64 AMI in the current region.
-func (s *AWSVPCService) resolveTestAmiID() (string, error) {
+func (s *AWSVPCService) resolveTestAmiID(ctx context.Context) (string, error) {
 	if id := cnTestAmiID(); id != "" {
 		return id, nil
 	}
 
-	out, err := s.client.DescribeImages(s.ctx, &ec2.DescribeImagesInput{
+	out, err := s.client.DescribeImages(ctx, &ec2.DescribeImagesInput{
 		Owners: []string{"amazon"},
 		Filters: []types.Filter{
 			{Name: aws.String("name"), Values: []string{"al2023-ami-*-x86_64"}},
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -15,32 +16,42 @@ import (
 
 // AWSVPCService implements VPC Service for AWS EC2/VPC.
 type AWSVPCService struct {
-	client      *ec2.Client
-	ctx         context.Context
-	instance ccctypes.InstanceConfig
+	client           *ec2.Client
+	instance         ccctypes.InstanceConfig
+	createdInstances map[string]bool // test instances launched by CreateTestResourceInSubnet and not yet deleted
+	createdMu        sync.Mutex
 }
 
 // NewAWSVPCService creates a new AWS VPC service using default credentials.
 func NewAWSVPCService(ctx context.Context, instance ccctypes.InstanceConfig) (*AWSVPCService, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(instance.Properties.Region))
+	cfg, err := config.LoadDefaultConfig(ctx, generic.AWSLoadOptions(instance, "vpc",
+		config.WithRegion(instance.Properties.Region),
+	)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	return &AWSVPCService{
-		client:   ec2.NewFromConfig(cfg),
-		ctx:      ctx,
-		instance: instance,
+		client:           ec2.NewFromConfig(cfg),
+		instance:         instance,
+		createdInstances: make(map[string]bool),
 	}, nil
 }
 
-func (s *AWSVPCService) GetOrProvisionTestableResources() ([]ccctypes.TestParams, error) {
+// GetOrProvisionTestableResources returns the tagged VPCs; VPC fixtures are created by
+// Terraform, so nothing is provisioned here.
+func (s *AWSVPCService) GetOrProvisionTestableResources(ctx context.Context) ([]ccctypes.TestParams, error) {
+	return s.DiscoverTestableResources(ctx)
+}
+
+// DiscoverTestableResources lists VPCs tagged for the CCC.VPC control set.
+func (s *AWSVPCService) DiscoverTestableResources(ctx context.Context) ([]ccctypes.TestParams, error) {
 	// Only return VPCs tagged CFIControlSet=CCC.VPC — this excludes:
 	//   - CN03 peer VPCs (tagged CFIControl=CCC.VPC.CN03, not CFIControlSet)
 	//   - Default VPC and any other account VPCs (no CFI tags)
 	// Both compliant and intentionally non-compliant (CFIVpcRole=bad) VPCs are
 	// included so the report reflects real compliance state for all fixtures.
-	output, err := s.client.DescribeVpcs(s.ctx, &ec2.DescribeVpcsInput{
+	output, err := s.client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
 		Filters: []types.Filter{
 			{
 				Name:   aws.String("tag:CFIControlSet"),
@@ -66,6 +77,7 @@ func (s *AWSVPCService) GetOrProvisionTestableResources() ([]ccctypes.TestParams
 			ProviderServiceType: "ec2:vpc",
 			ServiceType:         "vpc",
 			CatalogTypes:        []string{"CCC.VPC"},
+			Labels:              tagLabels(vpc.Tags),
 			TagFilter:           []string{"@MAIN", "@CCC.VPC"},
 			Instance:            s.instance,
 		})
@@ -74,23 +86,22 @@ func (s *AWSVPCService) GetOrProvisionTestableResources() ([]ccctypes.TestParams
 	return resources, nil
 }
 
-func (s *AWSVPCService) CheckUserProvisioned() error {
-	_, err := s.client.DescribeVpcs(s.ctx, &ec2.DescribeVpcsInput{MaxResults: aws.Int32(5)})
+func (s *AWSVPCService) CheckUserProvisioned(ctx context.Context) error {
+	_, err := s.client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{MaxResults: aws.Int32(5)})
 	if err != nil {
 		return fmt.Errorf("credentials not ready for EC2/VPC access: %w", err)
 	}
 	return nil
 }
 
-func (s *AWSVPCService) ElevateAccessForInspection() error { return nil }
-func (s *AWSVPCService) ResetAccess() error                { return nil }
-func (s *AWSVPCService) UpdateResourcePolicy() error       { return nil }
-func (s *AWSVPCService) TriggerDataWrite(_ string) error   { return nil }
-func (s *AWSVPCService) TearDown() error                   { return nil }
-func (s *AWSVPCService) GetResourceRegion(_ string) (string, error) {
+func (s *AWSVPCService) ElevateAccessForInspection(ctx context.Context) error { return nil }
+func (s *AWSVPCService) ResetAccess(ctx context.Context) error                { return nil }
+func (s *AWSVPCService) UpdateResourcePolicy(ctx context.Context) error       { return nil }
+func (s *AWSVPCService) TriggerDataWrite(ctx context.Context, _ string) error { return nil }
+func (s *AWSVPCService) GetResourceRegion(ctx context.Context, _ string) (string, error) {
 	return s.instance.Properties.Region, nil
 }
-func (s *AWSVPCService) GetReplicationStatus(_ string) (*generic.ReplicationStatus, error) {
+func (s *AWSVPCService) GetReplicationStatus(ctx context.Context, _ string) (*generic.ReplicationStatus, error) {
 	return nil, fmt.Errorf("replication status not applicable for VPC service")
 }
 
@@ -103,6 +114,15 @@ func tagValue(tags []types.Tag, key string) string {
 	return ""
 }
 
+// tagLabels converts EC2 tags to labels
+func tagLabels(tags []types.Tag) []string {
+	values := make(map[string]string, len(tags))
+	for _, t := range tags {
+		values[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return ccctypes.LabelsFromMap(values)
+}
+
 func boolFromEvidence(value interface{}) bool {
 	switch typedValue := value.(type) {
 	case bool:
@@ -113,3 +133,11 @@ func boolFromEvidence(value interface{}) bool {
 		return strings.EqualFold(strings.TrimSpace(fmt.Sprintf("%v", value)), "true")
 	}
 }
+
+// SDKClient returns the EC2 client for policy sdk queries
+func (s *AWSVPCService) SDKClient(ctx context.Context, name, resourceID string) (interface{}, error) {
+	if name != "ec2" {
+		return nil, generic.UnknownSDKClient(s, name, "ec2")
+	}
+	return s.client, nil
+}
@@ -1,9 +1,11 @@
 package vpc
 
+import "context"
+
 // CN01Service covers CCC.VPC.CN01: no default VPC should exist.
 type CN01Service interface {
-	CountDefaultVpcs() (int, error)
-	IsDefaultVpc(vpcID string) (bool, error)
-	EvaluateDefaultVpcControl(vpcID string) (map[string]interface{}, error)
-	ListDefaultVpcs() ([]DefaultVPC, error)
+	CountDefaultVpcs(ctx context.Context) (int, error)
+	IsDefaultVpc(ctx context.Context, vpcID string) (bool, error)
+	EvaluateDefaultVpcControl(ctx context.Context, vpcID string) (map[string]interface{}, error)
+	ListDefaultVpcs(ctx context.Context) ([]DefaultVPC, error)
 }
@@ -1,9 +1,11 @@
 package vpc
 
+import "context"
+
 // CN03Service covers CCC.VPC.CN03: VPC peering restricted to allowed list.
 type CN03Service interface {
-	EvaluatePeerAgainstAllowList(peerVpcID string) (map[string]interface{}, error)
-	AttemptVpcPeeringDryRun(requesterVpcID, peerVpcID string) (map[string]interface{}, error)
-	LoadVpcPeeringTrialMatrix(filePath string) (map[string]interface{}, error)
-	RunVpcPeeringDryRunTrialsFromFile(filePath string) (map[string]interface{}, error)
+	EvaluatePeerAgainstAllowList(ctx context.Context, peerVpcID string) (map[string]interface{}, error)
+	AttemptVpcPeeringDryRun(ctx context.Context, requesterVpcID, peerVpcID string) (map[string]interface{}, error)
+	LoadVpcPeeringTrialMatrix(ctx context.Context, filePath string) (map[string]interface{}, error)
+	RunVpcPeeringDryRunTrialsFromFile(ctx context.Context, filePath string) (map[string]interface{}, error)
 }
@@ -1,6 +1,9 @@
 package vpc
 
-import "github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+import (
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	ccctypes "github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
 
 // DefaultVPC is a minimal representation of a default VPC.
 // It is used for CCC.VPC controls which can be verified from control-plane metadata.
@@ -19,3 +22,29 @@ type Service interface {
 	CN04Service
 	TestResourceService
 }
+
+func init() {
+	generic.RegisterService(generic.ServiceDefinition{
+		Name:         "vpc",
+		CatalogTypes: []string{"CCC.VPC"},
+		TagFilter:    []string{"@MAIN", "@CCC.VPC"},
+		Config:       ccctypes.VpcServiceConfig{},
+		Providers: map[string]generic.ProviderImplementation{
+			// Each caller gets a fresh EC2 client. VPC tests run with the runner's ambient
+			// credentials; per-identity clients can be added when needed for negative testing.
+			"aws": {
+				New: func(env generic.ServiceEnv) (generic.Service, error) {
+					service, err := NewAWSVPCService(env.Ctx, *env.Instance)
+					if err != nil {
+						return nil, err
+					}
+					return service, nil
+				},
+				Uncached: true,
+			},
+			"local": {New: func(env generic.ServiceEnv) (generic.Service, error) {
+				return NewLocalVPCService(*env.Instance), nil
+			}},
+		},
+	})
+}
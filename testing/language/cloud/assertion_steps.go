@@ -0,0 +1,111 @@
+package cloud
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// registerAssertionSteps registers the assertions the features use that the generic steps
+// do not provide
+func (cw *CloudWorld) registerAssertionSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^"([^"]*)" is an array containing "([^"]*)"$`, cw.fieldIsArrayContaining)
+	ctx.Step(`^"([^"]*)" is not empty$`, cw.fieldIsNotEmpty)
+	ctx.Step(`^"([^"]*)" is not equal to "([^"]*)"$`, cw.fieldIsNotEqualTo)
+	ctx.Step(`^"([^"]*)" should equal "([^"]*)"$`, cw.fieldShouldEqual)
+	ctx.Step(`^"([^"]*)" should have length greater than "([^"]*)"$`, cw.fieldShouldHaveLengthGreaterThan)
+	ctx.Step(`^"([^"]*)" should contain one of "([^"]*)"$`, cw.fieldShouldContainOneOf)
+}
+
+// fieldIsArrayContaining checks that field is a list of scalars, such as the permitted-regions
+// rule, holding value
+func (cw *CloudWorld) fieldIsArrayContaining(field, value string) error {
+	actual := cw.HandleResolve(field)
+	expected := fmt.Sprintf("%v", cw.HandleResolve(value))
+	v := reflect.ValueOf(actual)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("expected %s to be an array, got %T", field, actual)
+	}
+	for i := 0; i < v.Len(); i++ {
+		if fmt.Sprintf("%v", v.Index(i).Interface()) == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected %s to contain '%s', got %v", field, expected, actual)
+}
+
+// fieldIsNotEmpty fails when field is unset, an empty string or an empty list
+func (cw *CloudWorld) fieldIsNotEmpty(field string) error {
+	actual := cw.HandleResolve(field)
+	if actual == nil {
+		return fmt.Errorf("expected %s to not be empty, got nil", field)
+	}
+	v := reflect.ValueOf(actual)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if v.Len() == 0 {
+			return fmt.Errorf("expected %s to not be empty", field)
+		}
+	}
+	return nil
+}
+
+// fieldIsNotEqualTo compares like the generic `"{x}" is "y"` step, but fails on a match
+func (cw *CloudWorld) fieldIsNotEqualTo(field, value string) error {
+	actual := fmt.Sprintf("%v", cw.HandleResolve(field))
+	if actual == fmt.Sprintf("%v", cw.HandleResolve(value)) {
+		return fmt.Errorf("expected %s to differ from %s, both are '%s'", field, value, actual)
+	}
+	return nil
+}
+
+// fieldShouldEqual compares like the generic `"{x}" is "y"` step
+func (cw *CloudWorld) fieldShouldEqual(field, value string) error {
+	actual := fmt.Sprintf("%v", cw.HandleResolve(field))
+	expected := fmt.Sprintf("%v", cw.HandleResolve(value))
+	if actual != expected {
+		return fmt.Errorf("expected %s to equal '%s', got '%s'", field, expected, actual)
+	}
+	return nil
+}
+
+// fieldShouldHaveLengthGreaterThan checks the length of a list, map or string
+func (cw *CloudWorld) fieldShouldHaveLengthGreaterThan(field, length string) error {
+	threshold, err := strconv.Atoi(fmt.Sprintf("%v", cw.HandleResolve(length)))
+	if err != nil {
+		return fmt.Errorf("invalid length '%s': %w", length, err)
+	}
+	actual := cw.HandleResolve(field)
+	v := reflect.ValueOf(actual)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if v.Len() <= threshold {
+			return fmt.Errorf("expected %s to have length greater than %d, got %d", field, threshold, v.Len())
+		}
+		return nil
+	}
+	return fmt.Errorf("expected %s to have a length, got %T", field, actual)
+}
+
+// fieldShouldContainOneOf checks that field, or its message if it is an error, contains one of
+// the comma-separated values, like the generic `is a string containing one of` table step
+func (cw *CloudWorld) fieldShouldContainOneOf(field, values string) error {
+	actual := cw.HandleResolve(field)
+	actualStr := fmt.Sprintf("%v", actual)
+	if err, ok := actual.(error); ok {
+		actualStr = err.Error()
+	}
+	var expected []string
+	for _, value := range strings.Split(values, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			expected = append(expected, value)
+			if strings.Contains(actualStr, value) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("expected %s to contain one of %v, but got '%s'", field, expected, actualStr)
+}
@@ -0,0 +1,159 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/cucumber/godog"
+)
+
+// contextType is the reflect.Type of context.Context
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// registerCallSteps registers the `I call "{x}" with "Method"` steps. They behave like the
+// generic steps of the same name, but pass the scenario context to methods whose first
+// parameter is a context.Context, so service calls are cancelled with the run. They must
+// be registered before the generic steps, since godog uses the first matching definition.
+func (cw *CloudWorld) registerCallSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^I call "([^"]*)" with "([^"]*)"$`, func(stepCtx context.Context, field, method string) error {
+		return cw.callMethod(stepCtx, field, method)
+	})
+	ctx.Step(`^I call "([^"]*)" with "([^"]*)" using argument "([^"]*)"$`, func(stepCtx context.Context, field, method, arg string) error {
+		return cw.callMethod(stepCtx, field, method, arg)
+	})
+	ctx.Step(`^I call "([^"]*)" with "([^"]*)" using arguments "([^"]*)" and "([^"]*)"$`, func(stepCtx context.Context, field, method, arg1, arg2 string) error {
+		return cw.callMethod(stepCtx, field, method, arg1, arg2)
+	})
+	ctx.Step(`^I call "([^"]*)" with "([^"]*)" using arguments "([^"]*)", "([^"]*)", and "([^"]*)"$`, func(stepCtx context.Context, field, method, arg1, arg2, arg3 string) error {
+		return cw.callMethod(stepCtx, field, method, arg1, arg2, arg3)
+	})
+	ctx.Step(`^I call "([^"]*)" with "([^"]*)" using arguments "([^"]*)", "([^"]*)", "([^"]*)", and "([^"]*)"$`, func(stepCtx context.Context, field, method, arg1, arg2, arg3, arg4 string) error {
+		return cw.callMethod(stepCtx, field, method, arg1, arg2, arg3, arg4)
+	})
+}
+
+// registerResultSteps registers assertions on stored call results that replace generic steps
+// of the same name. The generic versions format a stored error with %v, which loses its
+// chain; these wrap it with %w so errors.Is still sees ErrNotApplicable.
+func (cw *CloudWorld) registerResultSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^"([^"]*)" is not an error$`, cw.fieldIsNotError)
+}
+
+// fieldIsNotError fails when field resolves to an error, wrapping that error
+func (cw *CloudWorld) fieldIsNotError(field string) error {
+	if err, ok := cw.HandleResolve(field).(error); ok {
+		return fmt.Errorf("expected %s to not be an error, but got: %w", field, err)
+	}
+	return nil
+}
+
+// callMethod resolves field, calls method on it with the resolved args and stores the
+// outcome in "result": the returned error if it is non-nil, otherwise the first return value.
+// As in the generic steps, a method that cannot be found or called is also stored as an
+// error rather than failing the step; scenarios assert on "{result}".
+func (cw *CloudWorld) callMethod(stepCtx context.Context, field, method string, args ...string) error {
+	result, err := cw.resolveAndInvoke(stepCtx, field, method, args)
+	if err != nil {
+		cw.Props["result"] = err
+	} else {
+		cw.Props["result"] = result
+	}
+	return nil
+}
+
+// resolveAndInvoke looks up method on the value of field, converts args to its parameters
+// and calls it
+func (cw *CloudWorld) resolveAndInvoke(stepCtx context.Context, field, method string, args []string) (interface{}, error) {
+	fn := reflect.ValueOf(cw.HandleResolve(field))
+	if fn.IsValid() {
+		fn = fn.MethodByName(method)
+	}
+	if !fn.IsValid() {
+		return nil, fmt.Errorf("method %s not found", method)
+	}
+
+	fnType := fn.Type()
+	var in []reflect.Value
+	if fnType.NumIn() > 0 && fnType.In(0) == contextType {
+		in = append(in, reflect.ValueOf(stepCtx))
+	}
+	if fnType.IsVariadic() || fnType.NumIn() != len(in)+len(args) {
+		return nil, fmt.Errorf("%s.%s takes %d argument(s), got %d", field, method, fnType.NumIn()-len(in), len(args))
+	}
+	for _, arg := range args {
+		value, err := convertArgument(cw.HandleResolve(arg), fnType.In(len(in)))
+		if err != nil {
+			return nil, fmt.Errorf("argument %q to %s: %w", arg, method, err)
+		}
+		in = append(in, value)
+	}
+
+	return invoke(fn, in)
+}
+
+// invoke calls fn, converting a panic into an error, and splits a trailing error return
+func invoke(fn reflect.Value, in []reflect.Value) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	out := fn.Call(in)
+	if n := len(out); n > 0 && out[n-1].Type() == reflect.TypeOf((*error)(nil)).Elem() {
+		if !out[n-1].IsNil() {
+			return nil, out[n-1].Interface().(error)
+		}
+		out = out[:n-1]
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out[0].Interface(), nil
+}
+
+// convertArgument converts a resolved step argument to the parameter type t. Strings are
+// parsed for numeric and boolean parameters, since literal arguments resolve to strings.
+func convertArgument(value interface{}, t reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("nil is not a valid %s", t)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if s, ok := value.(string); ok {
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(s, 10, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(n).Convert(t), nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(b).Convert(t), nil
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(s, t.Bits())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(f).Convert(t), nil
+		}
+	}
+	// Numeric conversions only: converting between strings and numbers would yield runes
+	if v.Kind() != reflect.String && t.Kind() != reflect.String && v.Type().ConvertibleTo(t) {
+		return v.Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %T as %s", value, t)
+}
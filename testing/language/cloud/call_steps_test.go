@@ -0,0 +1,40 @@
+package cloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type callTarget struct{}
+
+func (callTarget) Join(ctx context.Context, a, b, c string, n int) string {
+	return strings.Join([]string{a, b, c}, "-") + strings.Repeat("!", n)
+}
+
+func TestCallMethodWithFourArguments(t *testing.T) {
+	cw := NewCloudWorld()
+	cw.Props["target"] = callTarget{}
+
+	if err := cw.callMethod(context.Background(), "{target}", "Join", "a", "b", "c", "2"); err != nil {
+		t.Fatalf("callMethod: %v", err)
+	}
+	if cw.Props["result"] != "a-b-c!!" {
+		t.Errorf("result = %v, want a-b-c!!", cw.Props["result"])
+	}
+}
+
+func TestCallMethodStoresNotFoundInResult(t *testing.T) {
+	cw := NewCloudWorld()
+	cw.Props["target"] = callTarget{}
+
+	for _, field := range []string{"{target}", "{missing}"} {
+		if err := cw.callMethod(context.Background(), field, "Split"); err != nil {
+			t.Fatalf("%s: step failed instead of storing the error: %v", field, err)
+		}
+		err, ok := cw.Props["result"].(error)
+		if !ok || err.Error() != "method Split not found" {
+			t.Errorf("%s: result = %v, want the error \"method Split not found\"", field, cw.Props["result"])
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -17,6 +18,8 @@ import (
 
 	"github.com/cucumber/godog"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/factory"
+	apigeneric "github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 	generic "github.com/robmoffat/standard-cucumber-steps/go"
 	"gopkg.in/yaml.v3"
@@ -161,7 +164,12 @@ func (cw *CloudWorld) iAttachToTestOutput(content, name string) error {
 
 // RegisterSteps registers all cloud-specific step definitions
 func (cw *CloudWorld) RegisterSteps(ctx *godog.ScenarioContext) {
-	// Register generic steps first
+	// Method calls inject the scenario context, so they take precedence over the generic ones
+	cw.registerCallSteps(ctx)
+	cw.registerResultSteps(ctx)
+	cw.registerAssertionSteps(ctx)
+
+	// Then the generic steps
 	cw.PropsWorld.RegisterSteps(ctx)
 
 	// CFI-specific attachment step
@@ -197,6 +205,22 @@ func (cw *CloudWorld) RegisterSteps(ctx *godog.ScenarioContext) {
 
 	// Placeholder for scenarios with no concrete assertion yet
 	ctx.Step(`^no-op required$`, cw.noOpRequired)
+
+	// Operations the backend cannot support (e.g. ARM calls against an emulator) skip the
+	// scenario rather than failing it
+	ctx.StepContext().After(func(stepCtx context.Context, st *godog.Step, status godog.StepResultStatus, err error) (context.Context, error) {
+		if isNotApplicable(err) {
+			fmt.Printf("⏭️  Not applicable: %v\n", err)
+			return stepCtx, godog.ErrSkip
+		}
+		return stepCtx, err
+	})
+}
+
+// isNotApplicable reports whether err came from an operation that is not applicable to the
+// current backend
+func isNotApplicable(err error) bool {
+	return errors.Is(err, apigeneric.ErrNotApplicable)
 }
 
 // noOpRequired is a placeholder step for scenarios that document controls without yet having a concrete test.
@@ -555,6 +579,8 @@ func (cw *CloudWorld) aCloudAPIForProviderIn(instanceArg string, apiName string)
 		provider = factory.ProviderAzure
 	case "gcp":
 		provider = factory.ProviderGCP
+	case "local":
+		provider = factory.ProviderLocal
 	default:
 		return fmt.Errorf("unsupported cloud provider %q in instance %q", instance.Properties.Provider, instance.ID)
 	}
@@ -607,12 +633,13 @@ func (cw *CloudWorld) requireEnvironmentVariableAs(envKey string, alias string)
 //   - Pass if service type doesn't match (policy not applicable)
 //   - Fail if policy file is missing
 //   - Pass/Fail based on policy evaluation otherwise
-func (cw *CloudWorld) attemptPolicyCheck(checkName, control, ar, serviceType, resourceName, provider string) error {
+func (cw *CloudWorld) attemptPolicyCheck(stepCtx context.Context, checkName, control, ar, serviceType, resourceName, provider string) error {
 	// Resolve any variable references
 	checkNameResolved := fmt.Sprintf("%v", cw.HandleResolve(checkName))
 	controlResolved := fmt.Sprintf("%v", cw.HandleResolve(control))
 	arResolved := fmt.Sprintf("%v", cw.HandleResolve(ar))
 	providerResolved := fmt.Sprintf("%v", cw.HandleResolve(provider))
+	serviceTypeResolved := fmt.Sprintf("%v", cw.HandleResolve(serviceType))
 
 	// Build the policy file path directly
 	// Directory structure: policy/{CatalogType}/{Control}/{AR}/{check-name}/{provider}.yaml
@@ -643,18 +670,46 @@ func (cw *CloudWorld) attemptPolicyCheck(checkName, control, ar, serviceType, re
 		return fmt.Errorf("failed to read policy file %s: %w", policyPath, err)
 	}
 
-	// Parse the YAML to get the service_type
+	// Parse the YAML to get the service_type and query form
 	var policyDef struct {
-		ServiceType string `yaml:"service_type"`
+		ServiceType string          `yaml:"service_type"`
+		SDK         *types.SDKQuery `yaml:"sdk"`
 	}
 	if err := yaml.Unmarshal(data, &policyDef); err != nil {
 		cw.Props["result"] = false
 		return fmt.Errorf("failed to parse policy file %s: %w", policyPath, err)
 	}
 
+	if policyDef.SDK != nil {
+		if policyDef.SDK.Provider != providerResolved {
+			cw.Props["result"] = false
+			return fmt.Errorf("policy %s is an sdk query for provider %s, not %s", policyPath, policyDef.SDK.Provider, providerResolved)
+		}
+	} else if recorder.Replaying() {
+		// Shell queries call the cloud CLIs, which the HTTP recorder cannot replay
+		return fmt.Errorf("policy check %s calls the %s CLI and is %w when replaying recorded traffic", checkNameResolved, providerResolved, apigeneric.ErrNotApplicable)
+	}
+
 	// Create policy checker and run the policy using Props for parameter substitution
 	checker := NewPolicyChecker(policyBaseDir)
-	result, err := checker.RunPolicy(cw.Props, policyPath)
+	// Point the aws CLI at the same emulator endpoint as the SDK clients (MinIO, LocalStack)
+	if instance, ok := cw.Props["Instance"].(types.InstanceConfig); ok && providerResolved == "aws" {
+		if endpointURL := instance.EndpointConfig(serviceTypeResolved).EndpointURL; endpointURL != "" {
+			checker.Env = append(checker.Env, "AWS_ENDPOINT_URL="+endpointURL)
+		}
+	}
+	// SDK queries use the clients of the instance's cached factory, so they share the
+	// credentials, endpoints and recorder of the services under test
+	if instance, ok := cw.Props["Instance"].(types.InstanceConfig); ok {
+		checker.Service = func(serviceType string) (apigeneric.Service, error) {
+			f, err := factory.NewFactory(factory.CloudProvider(instance.Properties.Provider), instance)
+			if err != nil {
+				return nil, err
+			}
+			return f.GetServiceAPI(serviceType)
+		}
+	}
+	result, err := checker.RunPolicy(stepCtx, cw.Props, policyPath)
 	if err != nil {
 		cw.Props["result"] = false
 		return fmt.Errorf("failed to run policy %s: %w", policyPath, err)
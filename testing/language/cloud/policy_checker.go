@@ -1,14 +1,18 @@
 package cloud
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/PaesslerAG/jsonpath"
+	apigeneric "github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/login"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 	"gopkg.in/yaml.v3"
@@ -18,6 +22,30 @@ import (
 type PolicyChecker struct {
 	// Base directory for policy files
 	PolicyBaseDir string
+
+	// Extra KEY=VALUE pairs added to the environment of policy queries
+	Env []string
+
+	// Service returns the service of a type, whose SDK clients run `sdk` queries
+	Service func(serviceType string) (apigeneric.Service, error)
+}
+
+// evaluatedPolicies records every policy file loaded during the run, for the run manifest
+var evaluatedPolicies struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+// EvaluatedPolicies returns the paths of the policy files loaded so far, sorted
+func EvaluatedPolicies() []string {
+	evaluatedPolicies.mu.Lock()
+	defer evaluatedPolicies.mu.Unlock()
+	paths := make([]string, 0, len(evaluatedPolicies.paths))
+	for path := range evaluatedPolicies.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
 }
 
 // NewPolicyChecker creates a new policy checker
@@ -34,6 +62,13 @@ func (c *PolicyChecker) LoadPolicy(policyPath string) (*types.PolicyDefinition,
 		return nil, fmt.Errorf("failed to read policy file %s: %w", policyPath, err)
 	}
 
+	evaluatedPolicies.mu.Lock()
+	if evaluatedPolicies.paths == nil {
+		evaluatedPolicies.paths = make(map[string]bool)
+	}
+	evaluatedPolicies.paths[policyPath] = true
+	evaluatedPolicies.mu.Unlock()
+
 	var policy types.PolicyDefinition
 	if err := yaml.Unmarshal(data, &policy); err != nil {
 		return nil, fmt.Errorf("failed to parse policy file %s: %w", policyPath, err)
@@ -109,7 +144,7 @@ func (c *PolicyChecker) ExecuteQuery(query string) (string, error) {
 	}
 
 	cmd := exec.Command("sh", "-c", cleanQuery)
-	cmd.Env = login.EnvForPolicyQuery(cleanQuery)
+	cmd.Env = append(login.EnvForPolicyQuery(cleanQuery), c.Env...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return string(output), fmt.Errorf("query execution failed: %w\nOutput: %s", err, string(output))
@@ -250,8 +285,9 @@ func resolveExpectedValues(expected []any, props map[string]interface{}) map[str
 	return allowed
 }
 
-// RunPolicy executes a complete policy check using values from Props
-func (c *PolicyChecker) RunPolicy(props map[string]interface{}, policyPath string) (*types.PolicyResult, error) {
+// RunPolicy executes a complete policy check using values from Props. SDK queries are
+// made with ctx; shell queries are not cancelled with it.
+func (c *PolicyChecker) RunPolicy(ctx context.Context, props map[string]interface{}, policyPath string) (*types.PolicyResult, error) {
 	// Load the policy
 	policyDef, err := c.LoadPolicy(policyPath)
 	if err != nil {
@@ -269,17 +305,29 @@ func (c *PolicyChecker) RunPolicy(props map[string]interface{}, policyPath strin
 		Passed:          true, // Will be set to false if any rule fails
 	}
 
-	// Substitute parameters in the query
-	queryExecuted, err := c.SubstituteParams(policyDef.Query, props)
-	if err != nil {
-		result.QueryError = err.Error()
-		result.Passed = false
-		return result, nil
+	// Substitute parameters in the query and execute it
+	var output string
+	if policyDef.SDK != nil {
+		result.QueryTemplate = policyDef.SDK.String()
+		var query types.SDKQuery
+		query, err = c.SubstituteSDKParams(*policyDef.SDK, props)
+		if err != nil {
+			result.QueryError = err.Error()
+			result.Passed = false
+			return result, nil
+		}
+		result.QueryExecuted = query.String()
+		resourceID, _ := props["ResourceName"].(string)
+		output, err = c.ExecuteSDKQuery(ctx, policyDef.ServiceType, resourceID, query)
+	} else {
+		result.QueryExecuted, err = c.SubstituteParams(policyDef.Query, props)
+		if err != nil {
+			result.QueryError = err.Error()
+			result.Passed = false
+			return result, nil
+		}
+		output, err = c.ExecuteQuery(result.QueryExecuted)
 	}
-	result.QueryExecuted = queryExecuted
-
-	// Execute the query
-	output, err := c.ExecuteQuery(result.QueryExecuted)
 	result.QueryOutput = output
 	if err != nil {
 		result.QueryError = err.Error()
@@ -0,0 +1,201 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apigeneric "github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// SubstituteSDKParams replaces parameter placeholders in the string values of an SDK query's
+// params and args, like SubstituteParams does for a shell query
+func (c *PolicyChecker) SubstituteSDKParams(query types.SDKQuery, props map[string]interface{}) (types.SDKQuery, error) {
+	params, err := c.substituteValue(query.Params, props)
+	if err != nil {
+		return query, err
+	}
+	args, err := c.substituteValue(query.Args, props)
+	if err != nil {
+		return query, err
+	}
+	query.Params, _ = params.(map[string]interface{})
+	query.Args, _ = args.([]interface{})
+	return query, nil
+}
+
+// substituteValue substitutes the placeholders in every string of a decoded YAML value
+func (c *PolicyChecker) substituteValue(value interface{}, props map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return c.SubstituteParams(v, props)
+	case map[string]interface{}:
+		if v == nil {
+			return v, nil
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			substituted, err := c.substituteValue(item, props)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = substituted
+		}
+		return out, nil
+	case []interface{}:
+		if v == nil {
+			return v, nil
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			substituted, err := c.substituteValue(item, props)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = substituted
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+// readOnlyOperationPrefixes are the prefixes of the SDK operations a policy may call. Policies
+// only inspect configuration, so operations that could change the resource are refused.
+var readOnlyOperationPrefixes = []string{"Get", "List", "Describe", "Head"}
+
+// isReadOnlyOperation reports whether operation is named like a read-only SDK operation
+func isReadOnlyOperation(operation string) bool {
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecuteSDKQuery calls a read-only operation of an SDK client of the service under test and
+// returns its result as JSON. The service comes from c.Service and must implement
+// generic.SDKClientProvider; resourceID names the resource the query inspects.
+func (c *PolicyChecker) ExecuteSDKQuery(ctx context.Context, serviceType, resourceID string, query types.SDKQuery) (string, error) {
+	if !isReadOnlyOperation(query.Operation) {
+		return "", fmt.Errorf("sdk query %s: only %s operations may be called", query, strings.Join(readOnlyOperationPrefixes, "/"))
+	}
+	if c.Service == nil {
+		return "", fmt.Errorf("sdk query %s needs the service under test", query)
+	}
+	service, err := c.Service(serviceType)
+	if err != nil {
+		return "", fmt.Errorf("failed to get service '%s': %w", serviceType, err)
+	}
+	clients, ok := service.(apigeneric.SDKClientProvider)
+	if !ok {
+		return "", fmt.Errorf("%s service (%T) has no SDK clients for policy queries", serviceType, service)
+	}
+	client, err := clients.SDKClient(ctx, query.Service, resourceID)
+	if err != nil {
+		return "", err
+	}
+
+	fn := reflect.ValueOf(client).MethodByName(query.Operation)
+	if !fn.IsValid() {
+		return "", fmt.Errorf("%s client (%T) has no operation %s", query.Service, client, query.Operation)
+	}
+	in, err := sdkArguments(ctx, fn.Type(), query)
+	if err != nil {
+		return "", fmt.Errorf("%s.%s: %w", query.Service, query.Operation, err)
+	}
+
+	result, err := invoke(fn, in)
+	if err != nil {
+		return "", fmt.Errorf("query execution failed: %w", err)
+	}
+	return sdkResultJSON(result)
+}
+
+// sdkArguments builds the arguments of an SDK operation: the context, then either the input
+// struct decoded from params (AWS) or the positional args (Azure, GCP). Trailing pointer
+// arguments that are not given, such as options structs, are nil; AWS functional options
+// are left out.
+func sdkArguments(ctx context.Context, fnType reflect.Type, query types.SDKQuery) ([]reflect.Value, error) {
+	if fnType.NumIn() == 0 || fnType.In(0) != contextType {
+		return nil, fmt.Errorf("operation does not take a context")
+	}
+	in := []reflect.Value{reflect.ValueOf(ctx)}
+
+	numArgs := fnType.NumIn() - 1
+	if fnType.IsVariadic() {
+		numArgs--
+	}
+
+	if query.Params != nil {
+		if len(query.Args) > 0 {
+			return nil, fmt.Errorf("set params or args, not both")
+		}
+		if numArgs != 1 || fnType.In(1).Kind() != reflect.Ptr || fnType.In(1).Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("operation takes %d positional argument(s); use args instead of params", numArgs)
+		}
+		input, err := decodeSDKValue(query.Params, fnType.In(1))
+		if err != nil {
+			return nil, fmt.Errorf("params: %w", err)
+		}
+		return append(in, input), nil
+	}
+
+	if len(query.Args) > numArgs {
+		return nil, fmt.Errorf("operation takes %d argument(s), got %d", numArgs, len(query.Args))
+	}
+	for i := 0; i < numArgs; i++ {
+		t := fnType.In(i + 1)
+		if i >= len(query.Args) {
+			if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+				return nil, fmt.Errorf("missing argument %d (%s)", i+1, t)
+			}
+			in = append(in, reflect.Zero(t))
+			continue
+		}
+		arg, err := decodeSDKValue(query.Args[i], t)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		in = append(in, arg)
+	}
+	return in, nil
+}
+
+// decodeSDKValue converts a decoded YAML value to the SDK type t through JSON, so maps fill
+// struct fields by name and strings fill string-based enums
+func decodeSDKValue(value interface{}, t reflect.Type) (reflect.Value, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	target := reflect.New(t)
+	if err := json.Unmarshal(data, target.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot use %s as %s: %w", data, t, err)
+	}
+	return target.Elem(), nil
+}
+
+// sdkResultJSON marshals an operation's result for the rules. The ResultMetadata of AWS
+// outputs describes the request rather than the resource, so it is left out.
+func sdkResultJSON(result interface{}) (string, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %T result: %w", result, err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", fmt.Errorf("failed to marshal %T result: %w", result, err)
+	}
+	if object, ok := decoded.(map[string]interface{}); ok {
+		delete(object, "ResultMetadata")
+	}
+	data, err = json.MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %T result: %w", result, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,96 @@
+package cloud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+func TestSDKArgumentsDecodesAWSInput(t *testing.T) {
+	fnType := reflect.ValueOf(&s3.Client{}).MethodByName("ListObjectsV2").Type()
+
+	query := types.SDKQuery{
+		Provider:  "aws",
+		Service:   "s3",
+		Operation: "ListObjectsV2",
+		Params: map[string]interface{}{
+			"Bucket":       "my-bucket",
+			"MaxKeys":      10,
+			"EncodingType": "url",
+		},
+	}
+	in, err := sdkArguments(context.Background(), fnType, query)
+	if err != nil {
+		t.Fatalf("sdkArguments: %v", err)
+	}
+	if len(in) != 2 {
+		t.Fatalf("got %d arguments, want the context and the input", len(in))
+	}
+
+	input, ok := in[1].Interface().(*s3.ListObjectsV2Input)
+	if !ok {
+		t.Fatalf("input is %s, want *s3.ListObjectsV2Input", in[1].Type())
+	}
+	if input.Bucket == nil || *input.Bucket != "my-bucket" {
+		t.Errorf("Bucket = %v, want my-bucket", input.Bucket)
+	}
+	if input.MaxKeys == nil || *input.MaxKeys != 10 {
+		t.Errorf("MaxKeys = %v, want 10", input.MaxKeys)
+	}
+	if input.EncodingType != s3types.EncodingTypeUrl {
+		t.Errorf("EncodingType = %q, want %q", input.EncodingType, s3types.EncodingTypeUrl)
+	}
+}
+
+func TestSDKArgumentsRejectsBadParams(t *testing.T) {
+	fnType := reflect.ValueOf(&s3.Client{}).MethodByName("GetPublicAccessBlock").Type()
+
+	tests := map[string]types.SDKQuery{
+		"wrong type":      {Params: map[string]interface{}{"Bucket": []interface{}{"a", "b"}}},
+		"params and args": {Params: map[string]interface{}{"Bucket": "b"}, Args: []interface{}{"b"}},
+		"positional args": {Args: []interface{}{"b"}},
+		"too many args":   {Args: []interface{}{"a", "b"}},
+	}
+	for name, query := range tests {
+		if _, err := sdkArguments(context.Background(), fnType, query); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDecodeSDKValue(t *testing.T) {
+	v, err := decodeSDKValue(map[string]interface{}{
+		"BlockPublicAcls":       true,
+		"RestrictPublicBuckets": false,
+	}, reflect.TypeOf(&s3types.PublicAccessBlockConfiguration{}))
+	if err != nil {
+		t.Fatalf("decodeSDKValue: %v", err)
+	}
+	config := v.Interface().(*s3types.PublicAccessBlockConfiguration)
+	if config.BlockPublicAcls == nil || !*config.BlockPublicAcls {
+		t.Errorf("BlockPublicAcls = %v, want true", config.BlockPublicAcls)
+	}
+	if config.RestrictPublicBuckets == nil || *config.RestrictPublicBuckets {
+		t.Errorf("RestrictPublicBuckets = %v, want false", config.RestrictPublicBuckets)
+	}
+	if config.IgnorePublicAcls != nil {
+		t.Errorf("IgnorePublicAcls = %v, want unset", *config.IgnorePublicAcls)
+	}
+}
+
+func TestIsReadOnlyOperation(t *testing.T) {
+	for _, op := range []string{"GetPublicAccessBlock", "ListBuckets", "DescribeTrails", "HeadBucket"} {
+		if !isReadOnlyOperation(op) {
+			t.Errorf("%s should be read-only", op)
+		}
+	}
+	for _, op := range []string{"DeleteBucket", "PutBucketPolicy", "CreateBucket", "getBucket"} {
+		if isReadOnlyOperation(op) {
+			t.Errorf("%s should not be read-only", op)
+		}
+	}
+}
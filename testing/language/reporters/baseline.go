@@ -0,0 +1,279 @@
+package reporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Baseline classifications of a finding
+const (
+	NewFailure   = "new-failure"    // Failing now, but not in the baseline
+	Fixed        = "fixed"          // Failing in the baseline, not failing now
+	StillFailing = "still-failing"  // Failing in both
+	Unchanged    = "unchanged"      // Not failing in either
+	NotInCurrent = "not-in-current" // In the baseline but not produced by this run (not classified)
+)
+
+// BaselineFinding is a finding classified against the baseline
+type BaselineFinding struct {
+	Key            string `json:"key"`
+	Instance       string `json:"instance,omitempty"`
+	ResourceUID    string `json:"resourceUid,omitempty"`
+	Control        string `json:"control,omitempty"`
+	Scenario       string `json:"scenario"`
+	Classification string `json:"classification"`
+	BaselineStatus string `json:"baselineStatus,omitempty"`
+	CurrentStatus  string `json:"currentStatus,omitempty"`
+}
+
+// BaselineCounts counts findings per classification
+type BaselineCounts struct {
+	NewFailures  int `json:"newFailures"`
+	Fixed        int `json:"fixed"`
+	StillFailing int `json:"stillFailing"`
+	Unchanged    int `json:"unchanged"`
+	NotInCurrent int `json:"notInCurrent"`
+}
+
+// BaselineDiff is the comparison of a run's findings against a baseline run
+type BaselineDiff struct {
+	Baseline string            `json:"baseline"`
+	Counts   BaselineCounts    `json:"counts"`
+	Findings []BaselineFinding `json:"findings"`
+}
+
+// LoadOCSFFindings reads the findings of a previous run from a combined.ocsf.json file, or
+// from the combined.ocsf.json in a run directory
+func LoadOCSFFindings(path string) ([]OCSFFinding, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, "combined.ocsf.json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+	var findings []OCSFFinding
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse findings in %s: %w", path, err)
+	}
+	return findings, nil
+}
+
+// findingKey identifies a finding across runs: instance, resource UID, control and scenario
+func findingKey(f OCSFFinding) (key, resourceUID, control string) {
+	if len(f.Resources) > 0 {
+		resourceUID = f.Resources[0].UID
+	}
+	if ccc := f.Unmapped.Compliance["CCC"]; len(ccc) > 0 {
+		control = ccc[0]
+	}
+	key = strings.Join([]string{f.Unmapped.Instance, resourceUID, control, f.Message}, "|")
+	return key, resourceUID, control
+}
+
+// isFailing reports whether a finding status counts as a failure: FAIL, or a service ERROR
+func isFailing(statusCode string) bool {
+	return statusCode == "FAIL" || statusCode == "ERROR"
+}
+
+// CompareBaseline classifies the current findings against the baseline findings.
+// baselinePath is recorded in the diff for reference.
+func CompareBaseline(baselinePath string, baseline, current []OCSFFinding) BaselineDiff {
+	diff := BaselineDiff{Baseline: baselinePath, Findings: []BaselineFinding{}}
+
+	before := make(map[string]OCSFFinding, len(baseline))
+	for _, f := range baseline {
+		key, _, _ := findingKey(f)
+		before[key] = f
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, f := range current {
+		key, resourceUID, control := findingKey(f)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		entry := BaselineFinding{
+			Key:           key,
+			Instance:      f.Unmapped.Instance,
+			ResourceUID:   resourceUID,
+			Control:       control,
+			Scenario:      f.Message,
+			CurrentStatus: f.StatusCode,
+		}
+		old, existed := before[key]
+		wasFailing := existed && isFailing(old.StatusCode)
+		if existed {
+			entry.BaselineStatus = old.StatusCode
+		}
+		switch {
+		case isFailing(f.StatusCode) && wasFailing:
+			entry.Classification = StillFailing
+			diff.Counts.StillFailing++
+		case isFailing(f.StatusCode):
+			entry.Classification = NewFailure
+			diff.Counts.NewFailures++
+		case wasFailing:
+			entry.Classification = Fixed
+			diff.Counts.Fixed++
+		default:
+			entry.Classification = Unchanged
+			diff.Counts.Unchanged++
+		}
+		diff.Findings = append(diff.Findings, entry)
+	}
+
+	for _, f := range baseline {
+		key, resourceUID, control := findingKey(f)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		diff.Findings = append(diff.Findings, BaselineFinding{
+			Key:            key,
+			Instance:       f.Unmapped.Instance,
+			ResourceUID:    resourceUID,
+			Control:        control,
+			Scenario:       f.Message,
+			Classification: NotInCurrent,
+			BaselineStatus: f.StatusCode,
+		})
+		diff.Counts.NotInCurrent++
+	}
+
+	sort.SliceStable(diff.Findings, func(i, j int) bool {
+		ri, rj := classificationRank(diff.Findings[i].Classification), classificationRank(diff.Findings[j].Classification)
+		if ri != rj {
+			return ri < rj
+		}
+		return diff.Findings[i].Key < diff.Findings[j].Key
+	})
+	return diff
+}
+
+// classificationRank orders the diff report: regressions first
+func classificationRank(classification string) int {
+	switch classification {
+	case NewFailure:
+		return 0
+	case Fixed:
+		return 1
+	case StillFailing:
+		return 2
+	case NotInCurrent:
+		return 3
+	}
+	return 4
+}
+
+// WriteBaselineDiff writes baseline-diff.json and baseline-diff.html to outputDir and
+// prints the new failures and fixes to the console
+func WriteBaselineDiff(outputDir string, diff BaselineDiff) error {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline diff: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "baseline-diff.json"), data, 0644); err != nil {
+		return fmt.Errorf("write baseline-diff.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "baseline-diff.html"), []byte(generateBaselineHTML(diff)), 0644); err != nil {
+		return fmt.Errorf("write baseline-diff.html: %w", err)
+	}
+
+	fmt.Println(generateBaselineText(diff))
+	return nil
+}
+
+func generateBaselineText(diff BaselineDiff) string {
+	var buf bytes.Buffer
+	buf.WriteString("\nBaseline Comparison (" + diff.Baseline + ")\n")
+	buf.WriteString(fmt.Sprintf("   New failures:  %d\n", diff.Counts.NewFailures))
+	buf.WriteString(fmt.Sprintf("   Fixed:         %d\n", diff.Counts.Fixed))
+	buf.WriteString(fmt.Sprintf("   Still failing: %d\n", diff.Counts.StillFailing))
+	buf.WriteString(fmt.Sprintf("   Unchanged:     %d\n", diff.Counts.Unchanged))
+	if diff.Counts.NotInCurrent > 0 {
+		buf.WriteString(fmt.Sprintf("   Not run:       %d (in the baseline only)\n", diff.Counts.NotInCurrent))
+	}
+	for _, f := range diff.Findings {
+		switch f.Classification {
+		case NewFailure:
+			buf.WriteString(fmt.Sprintf("   ❌ NEW  %s %s: %s\n", f.ResourceUID, f.Control, f.Scenario))
+		case Fixed:
+			buf.WriteString(fmt.Sprintf("   ✅ FIXED %s %s: %s\n", f.ResourceUID, f.Control, f.Scenario))
+		}
+	}
+	return buf.String()
+}
+
+func generateBaselineHTML(diff BaselineDiff) string {
+	var buf bytes.Buffer
+	buf.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>CCC Compliance Baseline Comparison</title>
+    <style>
+        body { font-family: Arial, sans-serif; margin: 20px; background: #f5f5f5; }
+        .container { max-width: 1400px; margin: 0 auto; background: white; padding: 20px; box-shadow: 0 0 10px rgba(0,0,0,0.1); overflow-x: auto; }
+        h1 { color: #333; border-bottom: 3px solid #4CAF50; padding-bottom: 10px; }
+        table { width: 100%; border-collapse: collapse; margin-top: 15px; }
+        th, td { border: 1px solid #ddd; padding: 8px; text-align: left; vertical-align: top; }
+        th { background: #2196F3; color: white; }
+        .new-failure { background: #ffebee; }
+        .fixed { background: #e8f5e9; }
+        .still-failing { background: #fff8e1; }
+        .not-in-current { color: #777; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>CCC Compliance Baseline Comparison</h1>
+`)
+	buf.WriteString(fmt.Sprintf("        <p>Baseline: %s</p>\n", escapeHTML(diff.Baseline)))
+	buf.WriteString(fmt.Sprintf("        <p>New failures: <strong>%d</strong> &middot; Fixed: %d &middot; Still failing: %d &middot; Unchanged: %d &middot; Not run: %d</p>\n",
+		diff.Counts.NewFailures, diff.Counts.Fixed, diff.Counts.StillFailing, diff.Counts.Unchanged, diff.Counts.NotInCurrent))
+	buf.WriteString(`        <table>
+            <thead>
+                <tr>
+                    <th>Change</th>
+                    <th>Instance</th>
+                    <th>Resource</th>
+                    <th>Control</th>
+                    <th>Scenario</th>
+                    <th>Baseline</th>
+                    <th>Current</th>
+                </tr>
+            </thead>
+            <tbody>
+`)
+	for _, f := range diff.Findings {
+		if f.Classification == Unchanged {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("                <tr class=\"%s\">\n", f.Classification))
+		for _, cell := range []string{f.Classification, f.Instance, f.ResourceUID, f.Control, f.Scenario, f.BaselineStatus, f.CurrentStatus} {
+			if cell == "" {
+				cell = "—"
+			}
+			buf.WriteString(fmt.Sprintf("                    <td>%s</td>\n", escapeHTML(cell)))
+		}
+		buf.WriteString("                </tr>\n")
+	}
+	buf.WriteString(`            </tbody>
+        </table>
+    </div>
+</body>
+</html>`)
+	return buf.String()
+}
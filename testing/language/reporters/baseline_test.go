@@ -0,0 +1,98 @@
+package reporters
+
+import "testing"
+
+// finding returns a finding for scenario on bucket-1 with the given status code
+func finding(scenario, statusCode string) OCSFFinding {
+	return OCSFFinding{
+		Message:    scenario,
+		StatusCode: statusCode,
+		Resources:  []OCSFResource{{UID: "bucket-1"}},
+		Unmapped: OCSFUnmapped{
+			Instance:   "main-aws",
+			Compliance: map[string][]string{"CCC": {"CCC.ObjStor.CN01"}},
+		},
+	}
+}
+
+func TestCompareBaseline(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline string // status in the baseline, "" if absent
+		current  string // status in this run, "" if absent
+		want     string
+	}{
+		{"new failure", "PASS", "FAIL", NewFailure},
+		{"new failure, not in baseline", "", "FAIL", NewFailure},
+		{"new error", "PASS", "ERROR", NewFailure},
+		{"fixed", "FAIL", "PASS", Fixed},
+		{"fixed error", "ERROR", "PASS", Fixed},
+		{"fixed by waiver", "FAIL", "WAIVED", Fixed},
+		{"still failing", "FAIL", "FAIL", StillFailing},
+		{"error then failure", "ERROR", "FAIL", StillFailing},
+		{"unchanged pass", "PASS", "PASS", Unchanged},
+		{"new pass", "", "PASS", Unchanged},
+		{"still waived", "WAIVED", "WAIVED", Unchanged},
+		{"waiver expired", "WAIVED", "FAIL", NewFailure},
+		{"not in current", "FAIL", "", NotInCurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var baseline, current []OCSFFinding
+			if tt.baseline != "" {
+				baseline = append(baseline, finding("Scenario", tt.baseline))
+			}
+			if tt.current != "" {
+				current = append(current, finding("Scenario", tt.current))
+			}
+
+			diff := CompareBaseline("baseline.ocsf.json", baseline, current)
+			if len(diff.Findings) != 1 {
+				t.Fatalf("got %d findings, want 1: %+v", len(diff.Findings), diff.Findings)
+			}
+			got := diff.Findings[0]
+			if got.Classification != tt.want {
+				t.Errorf("classification = %s, want %s", got.Classification, tt.want)
+			}
+			if got.BaselineStatus != tt.baseline || got.CurrentStatus != tt.current {
+				t.Errorf("statuses = %q -> %q, want %q -> %q", got.BaselineStatus, got.CurrentStatus, tt.baseline, tt.current)
+			}
+			if got.Instance != "main-aws" || got.ResourceUID != "bucket-1" || got.Control != "CCC.ObjStor.CN01" || got.Scenario != "Scenario" {
+				t.Errorf("finding = %+v, want main-aws bucket-1 CCC.ObjStor.CN01 Scenario", got)
+			}
+		})
+	}
+}
+
+func TestCompareBaselineCountsAndKeys(t *testing.T) {
+	otherResource := finding("Scenario", "FAIL")
+	otherResource.Resources[0].UID = "bucket-2"
+	otherInstance := finding("Scenario", "PASS")
+	otherInstance.Unmapped.Instance = "main-gcp"
+
+	baseline := []OCSFFinding{
+		finding("Scenario", "PASS"),
+		finding("Fixed", "FAIL"),
+		finding("Removed", "PASS"),
+		otherInstance,
+	}
+	current := []OCSFFinding{
+		finding("Scenario", "FAIL"),
+		finding("Scenario", "FAIL"), // Duplicates are classified once
+		finding("Fixed", "PASS"),
+		otherResource,
+		otherInstance,
+	}
+
+	diff := CompareBaseline("base", baseline, current)
+	want := BaselineCounts{NewFailures: 2, Fixed: 1, Unchanged: 1, NotInCurrent: 1}
+	if diff.Counts != want {
+		t.Errorf("counts = %+v, want %+v", diff.Counts, want)
+	}
+	if diff.Baseline != "base" {
+		t.Errorf("baseline = %q, want base", diff.Baseline)
+	}
+	if len(diff.Findings) != 5 {
+		t.Errorf("got %d findings, want 5", len(diff.Findings))
+	}
+}
@@ -0,0 +1,241 @@
+package reporters
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// instanceCell holds one control's results for one instance in the cross-instance summary.
+// Each badge list runs parallel to its scenario list, e.g. "NotTestable" for a passing scenario.
+type instanceCell struct {
+	Passing       []string
+	PassingBadges []string
+	Failing       []string
+	FailingBadges []string
+	Waived        []string // Failures accepted by a waiver
+	WaivedBadges  []string
+}
+
+// badged returns the scenarios that carry a badge, with their badges
+func badged(scenarios, badges []string) ([]string, []string) {
+	var names, withBadges []string
+	for i, badge := range badges {
+		if badge != "" {
+			names = append(names, scenarios[i])
+			withBadges = append(withBadges, badge)
+		}
+	}
+	return names, withBadges
+}
+
+// writeCrossInstanceSummary writes summary.html with one row per control and one column per
+// instance, so the same CCC control can be compared across providers side by side
+func writeCrossInstanceSummary(outputDir string, instances []string, results []SummaryResult, serviceErrors []ServiceError) error {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].suite < results[j].suite
+	})
+
+	// Aggregate by control -> instance -> passing/failing scenario names
+	byControl := make(map[string]map[string]*instanceCell)
+	for _, r := range results {
+		if !strings.HasPrefix(r.Control, controlPattern) {
+			continue
+		}
+		if byControl[r.Control] == nil {
+			byControl[r.Control] = make(map[string]*instanceCell)
+		}
+		cell := byControl[r.Control][r.Instance]
+		if cell == nil {
+			cell = &instanceCell{}
+			byControl[r.Control][r.Instance] = cell
+		}
+		scenarioName := r.ScenarioName
+		if scenarioName == "" {
+			scenarioName = r.Scenario
+		}
+		switch r.Outcome {
+		case "FAILING":
+			cell.Failing = append(cell.Failing, scenarioName)
+			cell.FailingBadges = append(cell.FailingBadges, r.Badge)
+		case "WAIVED":
+			cell.Waived = append(cell.Waived, scenarioName)
+			cell.WaivedBadges = append(cell.WaivedBadges, r.Badge)
+		default:
+			cell.Passing = append(cell.Passing, scenarioName)
+			cell.PassingBadges = append(cell.PassingBadges, r.Badge)
+		}
+	}
+
+	controls := make([]string, 0, len(byControl))
+	for c := range byControl {
+		controls = append(controls, c)
+	}
+	sort.Strings(controls)
+
+	html := generateCrossInstanceHTML(controls, instances, byControl, serviceErrors)
+	summaryPath := filepath.Join(outputDir, "summary.html")
+	if err := os.WriteFile(summaryPath, []byte(html), 0644); err != nil {
+		return fmt.Errorf("write summary.html: %w", err)
+	}
+
+	fmt.Println(generateCrossInstanceText(controls, instances, byControl) + serviceErrorsText(serviceErrors))
+	return nil
+}
+
+func generateCrossInstanceHTML(controls []string, instances []string, byControl map[string]map[string]*instanceCell, serviceErrors []ServiceError) string {
+	var buf bytes.Buffer
+	buf.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>CCC Compliance Test Summary</title>
+    <style>
+        body { font-family: Arial, sans-serif; margin: 20px; background: #f5f5f5; }
+        .container { max-width: 1400px; margin: 0 auto; background: white; padding: 20px; box-shadow: 0 0 10px rgba(0,0,0,0.1); overflow-x: auto; }
+        h1 { color: #333; border-bottom: 3px solid #4CAF50; padding-bottom: 10px; }
+        table { width: 100%; border-collapse: collapse; margin-top: 15px; }
+        th, td { border: 1px solid #ddd; padding: 8px; text-align: left; vertical-align: top; }
+        th { background: #2196F3; color: white; }
+        tr:nth-child(even) { background: #f9f9f9; }
+        .passing { background: #e8f5e9; }
+        .failing { background: #ffebee; }
+        .noop { background: #fff8e1; }
+        .waived { background: #fff3e0; }
+        .cell-list { margin: 0; padding-left: 16px; }
+        .cell-list li { margin: 4px 0; }
+        .badge { display: inline-block; padding: 4px 10px; margin: 2px 0; border-radius: 12px;
+            font-size: 0.9em; font-weight: 500; }
+        .badge-passing { background: #c8e6c9; color: #2e7d32; border: 1px solid #81c784; }
+        .badge-failing { background: #ffcdd2; color: #c62828; border: 1px solid #e57373; }
+        .badge-waived { background: #ffe0b2; color: #e65100; border: 1px solid #ffb74d; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>CCC Compliance Test Summary</h1>
+        <table>
+            <thead>
+                <tr>
+                    <th>Control</th>
+`)
+	for _, inst := range instances {
+		buf.WriteString(fmt.Sprintf("                    <th>%s</th>\n", escapeHTML(inst)))
+	}
+	buf.WriteString(`                </tr>
+            </thead>
+            <tbody>
+`)
+	for _, ctrl := range controls {
+		buf.WriteString("                <tr>\n")
+		buf.WriteString(fmt.Sprintf("                    <td><strong>%s</strong></td>\n", escapeHTML(ctrl)))
+		for _, inst := range instances {
+			cell := byControl[ctrl][inst]
+			if cell == nil {
+				buf.WriteString("                    <td class=\"noop\">—</td>\n")
+				continue
+			}
+			class := "passing"
+			if len(cell.Failing) > 0 {
+				class = "failing"
+			} else if len(cell.Waived) > 0 {
+				class = "waived"
+			}
+			var b strings.Builder
+			b.WriteString(fmt.Sprintf("<span class=\"badge badge-passing\">%d passing</span> ", len(cell.Passing)))
+			// Passing scenarios are only listed when badged (e.g. NotTestable or Duplicate),
+			// so a pass that tested nothing is not hidden in the count
+			if names, badges := badged(cell.Passing, cell.PassingBadges); len(names) > 0 {
+				b.WriteString(scenarioListHTML(names, badges, "passing"))
+			}
+			if len(cell.Failing) > 0 {
+				b.WriteString(fmt.Sprintf("<span class=\"badge badge-failing\">%d failing</span>", len(cell.Failing)))
+				b.WriteString(scenarioListHTML(cell.Failing, cell.FailingBadges, "failing"))
+			}
+			if len(cell.Waived) > 0 {
+				b.WriteString(fmt.Sprintf("<span class=\"badge badge-waived\">%d waived</span>", len(cell.Waived)))
+				b.WriteString(scenarioListHTML(cell.Waived, cell.WaivedBadges, "waived"))
+			}
+			buf.WriteString(fmt.Sprintf("                    <td class=\"%s\">%s</td>\n", class, b.String()))
+		}
+		buf.WriteString("                </tr>\n")
+	}
+	buf.WriteString(`            </tbody>
+        </table>
+`)
+	buf.WriteString(serviceErrorsHTML(serviceErrors, true))
+	buf.WriteString(`    </div>
+</body>
+</html>`)
+	return buf.String()
+}
+
+func generateCrossInstanceText(controls []string, instances []string, byControl map[string]map[string]*instanceCell) string {
+	var buf bytes.Buffer
+	controlColWidth := 55 // Wide enough for "CCC.XXX.YYYY.ARZZ - Description"
+	colWidth := 30
+	separatorLen := controlColWidth + len(instances)*(3+colWidth)
+
+	buf.WriteString("\n" + strings.Repeat("=", separatorLen) + "\n")
+	buf.WriteString("CCC Compliance Test Summary\n")
+	buf.WriteString(strings.Repeat("=", separatorLen) + "\n\n")
+	pad := func(s string, w int) string {
+		if len(s) >= w {
+			return s[:w-2] + ".."
+		}
+		return s + strings.Repeat(" ", w-len(s))
+	}
+
+	buf.WriteString(pad("Control", controlColWidth))
+	for _, inst := range instances {
+		buf.WriteString(" | ")
+		buf.WriteString(pad(inst, colWidth))
+	}
+	buf.WriteString("\n")
+	buf.WriteString(strings.Repeat("-", separatorLen))
+	buf.WriteString("\n")
+
+	for _, ctrl := range controls {
+		buf.WriteString(pad(ctrl, controlColWidth))
+		for _, inst := range instances {
+			buf.WriteString(" | ")
+			cell := byControl[ctrl][inst]
+			if cell == nil {
+				buf.WriteString(pad("-", colWidth))
+				continue
+			}
+			buf.WriteString(pad(fmt.Sprintf("PASS %d / FAIL %d / WAIVED %d", len(cell.Passing), len(cell.Failing), len(cell.Waived)), colWidth))
+		}
+		buf.WriteString("\n")
+	}
+
+	// Badged scenarios, e.g. NotTested failures or NotTestable passes, are listed below the
+	// table since the counts do not show them
+	var notes []string
+	for _, ctrl := range controls {
+		for _, inst := range instances {
+			cell := byControl[ctrl][inst]
+			if cell == nil {
+				continue
+			}
+			for _, list := range [][2][]string{{cell.Passing, cell.PassingBadges}, {cell.Failing, cell.FailingBadges}, {cell.Waived, cell.WaivedBadges}} {
+				names, badges := badged(list[0], list[1])
+				for i := range names {
+					notes = append(notes, fmt.Sprintf("%s [%s] %s: %s", ctrl, inst, names[i], badges[i]))
+				}
+			}
+		}
+	}
+	if len(notes) > 0 {
+		buf.WriteString(strings.Repeat("-", separatorLen) + "\n")
+		for _, note := range notes {
+			buf.WriteString(note + "\n")
+		}
+	}
+
+	buf.WriteString(strings.Repeat("=", separatorLen) + "\n")
+	return buf.String()
+}
@@ -14,6 +14,7 @@ type TestParams = types.TestParams
 type FormatterFactory struct {
 	params             TestParams
 	attachmentProvider types.AttachmentProvider
+	tally              ScenarioTally // Failed and waived scenarios, counted by the summary formatter
 }
 
 // NewFormatterFactory creates a new formatter factory with the given parameters
@@ -51,6 +52,12 @@ func (ff *FormatterFactory) GetOCSFFormatterFunc() func(string, io.Writer) forma
 // GetSummaryFormatterFunc returns a summary formatter function (collects to global, report generated at end)
 func (ff *FormatterFactory) GetSummaryFormatterFunc() func(string, io.Writer) formatters.Formatter {
 	return func(suite string, out io.Writer) formatters.Formatter {
-		return NewSummaryFormatter(suite, out)
+		return newSummaryFormatter(suite, out, ff.params, &ff.tally)
 	}
 }
+
+// Tally returns the failed and waived scenarios counted by the summary formatters this
+// factory has created
+func (ff *FormatterFactory) Tally() *ScenarioTally {
+	return &ff.tally
+}
@@ -27,6 +27,7 @@ type HTMLFormatter struct {
 		totalScenarios  int
 		passedScenarios int
 		failedScenarios int
+		waivedScenarios int
 		totalSteps      int
 		passedSteps     int
 		failedSteps     int
@@ -36,11 +37,16 @@ type HTMLFormatter struct {
 	bodyBuffer         bytes.Buffer
 	scenarioOpened     bool
 	featureOpened      bool
-	stepKeywords       map[string]string              // Maps step AST node IDs to their keywords (Given/When/Then/And/But)
-	backgroundSteps    map[string]bool                // Maps step AST node IDs to whether they're from Background
+	stepKeywords       map[string]string        // Maps step AST node IDs to their keywords (Given/When/Then/And/But)
+	backgroundSteps    map[string]bool          // Maps step AST node IDs to whether they're from Background
 	attachmentProvider types.AttachmentProvider // Provider for accessing attachments from PropsWorld
-	params             *TestParams                    // Optional test parameters
-	allTags            map[string]bool                // Tracks all unique tags seen
+	params             *TestParams              // Optional test parameters
+	allTags            map[string]bool          // Tracks all unique tags seen
+	stepStartTime      time.Time                // Start time of the current step
+	currentFeature     string                   // Name of the current feature, used to match waivers
+	currentWaiver      *Waiver                  // Waiver covering the current scenario, if any
+	scenarioFailed     bool                     // Whether a step of the current scenario failed
+	scenarioWaived     bool                     // Whether an unexpired waiver accepts the current scenario's failure
 }
 
 // Feature captures feature information
@@ -57,6 +63,8 @@ func (f *HTMLFormatter) Feature(gd *messages.GherkinDocument, uri string, c []by
 					f.attachmentProvider.ClearAttachments()
 				}
 			}
+			f.applyWaiverCounts()
+			f.bodyBuffer.WriteString(f.waiverHTML())
 			fmt.Fprintf(&f.bodyBuffer, `</div>`)
 			f.scenarioOpened = false
 		}
@@ -81,6 +89,7 @@ func (f *HTMLFormatter) Feature(gd *messages.GherkinDocument, uri string, c []by
 		}
 
 		fmt.Fprintf(&f.bodyBuffer, `<div class="feature"><div class="feature-header"><strong>Feature:</strong> %s</div><div>`, gd.Feature.Name)
+		f.currentFeature = gd.Feature.Name
 		f.featureOpened = true
 	}
 }
@@ -98,6 +107,8 @@ func (f *HTMLFormatter) Pickle(pickle *messages.Pickle) {
 				f.attachmentProvider.ClearAttachments()
 			}
 		}
+		f.applyWaiverCounts()
+		f.bodyBuffer.WriteString(f.waiverHTML())
 		fmt.Fprintf(&f.bodyBuffer, `</div>`)
 	}
 
@@ -122,6 +133,35 @@ func (f *HTMLFormatter) Pickle(pickle *messages.Pickle) {
 	f.stats.totalScenarios++
 	fmt.Fprintf(&f.bodyBuffer, `<div class="scenario" data-tags="%s"><strong>Scenario:</strong> %s %s`, tagsAttr, pickle.Name, tagsHTML)
 	f.scenarioOpened = true
+	f.scenarioFailed = false
+	f.scenarioWaived = false
+	f.currentWaiver = MatchWaiver(f.params, f.currentFeature, pickle.Name)
+}
+
+// applyWaiverCounts settles the result of the scenario being closed: an unexpired waiver
+// moves a failed scenario from failed to waived, an expired one leaves it failed
+func (f *HTMLFormatter) applyWaiverCounts() {
+	if f.currentWaiver == nil || !f.scenarioFailed || f.scenarioWaived {
+		return
+	}
+	if !f.currentWaiver.Expired(time.Now()) {
+		f.scenarioWaived = true
+		f.stats.failedScenarios--
+		f.stats.waivedScenarios++
+	}
+}
+
+// waiverHTML renders the waiver covering a failed scenario, once applyWaiverCounts has
+// settled whether it applies
+func (f *HTMLFormatter) waiverHTML() string {
+	w := f.currentWaiver
+	if w == nil || !f.scenarioFailed {
+		return ""
+	}
+	if !f.scenarioWaived {
+		return fmt.Sprintf(`<div class="waiver expired"><strong>⚑ Waiver expired:</strong> %s</div>`, escapeHTML(w.Describe()))
+	}
+	return fmt.Sprintf(`<div class="waiver"><strong>⚑ Waived:</strong> %s</div>`, escapeHTML(w.Describe()))
 }
 
 // TestRunStarted is required by the formatters.Formatter interface
@@ -152,6 +192,8 @@ func (f *HTMLFormatter) Summary() {
 				f.attachmentProvider.ClearAttachments()
 			}
 		}
+		f.applyWaiverCounts()
+		f.bodyBuffer.WriteString(f.waiverHTML())
 		fmt.Fprintf(&f.bodyBuffer, `</div>`)
 	}
 
@@ -166,9 +208,6 @@ func (f *HTMLFormatter) Summary() {
 	fmt.Fprint(f.out, html)
 }
 
-// Track step start time (stored temporarily as we can't keep state)
-var stepStartTime time.Time
-
 // getStepKeyword returns the keyword for a step by looking up its AST node IDs
 func (f *HTMLFormatter) getStepKeyword(step *messages.PickleStep) string {
 	// Check if we have any AST node IDs for this step
@@ -186,12 +225,12 @@ func (f *HTMLFormatter) getStepKeyword(step *messages.PickleStep) string {
 
 // Defined is required by the formatters.Formatter interface
 func (f *HTMLFormatter) Defined(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
-	stepStartTime = time.Now()
+	f.stepStartTime = time.Now()
 }
 
 // Passed is required by the formatters.Formatter interface
 func (f *HTMLFormatter) Passed(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
-	duration := time.Since(stepStartTime)
+	duration := time.Since(f.stepStartTime)
 	f.stats.totalSteps++
 	f.stats.passedSteps++
 	keyword := f.getStepKeyword(step)
@@ -202,7 +241,7 @@ func (f *HTMLFormatter) Passed(pickle *messages.Pickle, step *messages.PickleSte
 
 // Skipped is required by the formatters.Formatter interface
 func (f *HTMLFormatter) Skipped(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
-	duration := time.Since(stepStartTime)
+	duration := time.Since(f.stepStartTime)
 	f.stats.totalSteps++
 	f.stats.skippedSteps++
 	keyword := f.getStepKeyword(step)
@@ -213,7 +252,7 @@ func (f *HTMLFormatter) Skipped(pickle *messages.Pickle, step *messages.PickleSt
 
 // Undefined is required by the formatters.Formatter interface
 func (f *HTMLFormatter) Undefined(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
-	duration := time.Since(stepStartTime)
+	duration := time.Since(f.stepStartTime)
 	f.stats.totalSteps++
 	f.stats.undefinedSteps++
 	keyword := f.getStepKeyword(step)
@@ -224,10 +263,11 @@ func (f *HTMLFormatter) Undefined(pickle *messages.Pickle, step *messages.Pickle
 
 // Failed is required by the formatters.Formatter interface
 func (f *HTMLFormatter) Failed(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition, err error) {
-	duration := time.Since(stepStartTime)
+	duration := time.Since(f.stepStartTime)
 	f.stats.totalSteps++
 	f.stats.failedSteps++
 	f.stats.failedScenarios++ // Track failed scenario
+	f.scenarioFailed = true
 	keyword := f.getStepKeyword(step)
 	argHTML := formatStepArgument(step.Argument)
 	errMsg := ""
@@ -240,7 +280,7 @@ func (f *HTMLFormatter) Failed(pickle *messages.Pickle, step *messages.PickleSte
 
 // Pending is required by the formatters.Formatter interface
 func (f *HTMLFormatter) Pending(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
-	duration := time.Since(stepStartTime)
+	duration := time.Since(f.stepStartTime)
 	keyword := f.getStepKeyword(step)
 	argHTML := formatStepArgument(step.Argument)
 	fmt.Fprintf(&f.bodyBuffer, `<div class="step pending"><strong>%s</strong> %s<span class="timestamp" style="float: right;">%s</span>%s</div>`,
@@ -426,7 +466,7 @@ func (f *HTMLFormatter) appendStructFieldsToTable(tableRows *strings.Builder, v
 
 func (f *HTMLFormatter) generateHTML() string {
 	totalRunTime := f.stats.endTime.Sub(f.stats.startTime)
-	passedScenarios := f.stats.totalScenarios - f.stats.failedScenarios
+	passedScenarios := f.stats.totalScenarios - f.stats.failedScenarios - f.stats.waivedScenarios
 
 	// Generate test parameters table if params are available
 	paramsTable := ""
@@ -476,6 +516,8 @@ func (f *HTMLFormatter) generateHTML() string {
         .failed { background: #ffcdd2; border-left: 4px solid #f44336; }
         .skipped { background: #fff9c4; border-left: 4px solid #FFC107; }
         .undefined { background: #e0e0e0; border-left: 4px solid #9E9E9E; }
+        .waiver { background: #ffe0b2; border-left: 4px solid #FF9800; padding: 5px 10px; margin: 5px 0; }
+        .waiver.expired { background: #ffcdd2; border-left-color: #f44336; }
         .error-message { color: #f44336; font-family: monospace; margin: 10px 0; padding: 10px; background: #ffebee; }
         .timestamp { color: #666; font-size: 0.9em; }
         .tags { margin-left: 10px; font-size: 0.85em; color: #666; }
@@ -495,7 +537,7 @@ func (f *HTMLFormatter) generateHTML() string {
             <p>Generated: %s</p>
             <p>Total Run Time: %s</p>
             <p>Features: %d</p>
-            <p>Scenarios: %d (✅ %d | ❌ %d)</p>
+            <p>Scenarios: %d (✅ %d | ❌ %d | ⚑ %d waived)</p>
             <p>Steps: %d (✅ %d | ❌ %d | ⏭️ %d | ❓ %d)</p>
         </div>
         <div class="filter-bar">
@@ -559,6 +601,7 @@ func (f *HTMLFormatter) generateHTML() string {
 		f.stats.totalScenarios,
 		passedScenarios,
 		f.stats.failedScenarios,
+		f.stats.waivedScenarios,
 		f.stats.totalSteps,
 		f.stats.passedSteps,
 		f.stats.failedSteps,
@@ -11,6 +11,17 @@ import (
 	messages "github.com/cucumber/messages/go/v21"
 )
 
+// productVersion is reported as metadata.product.version in every finding
+var productVersion = "dev"
+
+// SetProductVersion sets the product version reported in findings, e.g. the module version
+// or git commit of the runner. Call it before any formatter is created.
+func SetProductVersion(version string) {
+	if version != "" {
+		productVersion = version
+	}
+}
+
 // OCSFFormatter is a godog formatter that generates OCSF JSON reports
 type OCSFFormatter struct {
 	out             io.Writer
@@ -67,6 +78,17 @@ type OCSFProduct struct {
 // OCSFUnmapped represents the unmapped section
 type OCSFUnmapped struct {
 	Compliance map[string][]string `json:"compliance"`
+	Instance   string              `json:"instance,omitempty"` // Instance ID, so combined multi-instance output can be split again
+	Waiver     *OCSFWaiver         `json:"waiver,omitempty"`   // Waiver covering the scenario, if any
+}
+
+// OCSFWaiver records the waiver that covers a finding
+type OCSFWaiver struct {
+	Ticket        string `json:"ticket"`
+	Owner         string `json:"owner"`
+	Justification string `json:"justification"`
+	Expires       string `json:"expires"`
+	Expired       bool   `json:"expired"`
 }
 
 // OCSFFindingInfo represents the finding_info section
@@ -138,11 +160,32 @@ func applyExclusionStatusOverride(finding *OCSFFinding) {
 	}
 }
 
+// applyWaiverStatusOverride marks a failed finding covered by an unexpired waiver as WAIVED
+// (suppressed); a failed finding whose waiver has expired stays FAIL and says so
+func applyWaiverStatusOverride(finding *OCSFFinding) {
+	w := finding.Unmapped.Waiver
+	if w == nil || finding.StatusCode != "FAIL" {
+		return
+	}
+	if finding.StatusDetail != "" {
+		finding.StatusDetail += "\n"
+	}
+	if w.Expired {
+		finding.StatusDetail += fmt.Sprintf("✗ Waiver %s expired on %s", w.Ticket, w.Expires)
+		return
+	}
+	finding.StatusCode = "WAIVED"
+	finding.Status = "Suppressed"
+	finding.StatusID = 3
+	finding.StatusDetail += fmt.Sprintf("⚑ Waived by %s (%s, until %s): %s", w.Ticket, w.Owner, w.Expires, w.Justification)
+}
+
 // Pickle captures pickle (scenario) information
 func (f *OCSFFormatter) Pickle(pickle *messages.Pickle) {
 	// Save the previous scenario if one was in progress
 	if f.scenarioStarted && f.currentScenario != nil {
 		applyExclusionStatusOverride(f.currentScenario)
+		applyWaiverStatusOverride(f.currentScenario)
 		f.findings = append(f.findings, *f.currentScenario)
 	}
 
@@ -184,7 +227,7 @@ func (f *OCSFFormatter) Pickle(pickle *messages.Pickle) {
 				Name:       productName,
 				UID:        productName,
 				VendorName: "FINOS",
-				Version:    "0.1",
+				Version:    productVersion,
 			},
 			Profiles: tagNames,
 			Version:  "1.4.0",
@@ -219,6 +262,20 @@ func (f *OCSFFormatter) Pickle(pickle *messages.Pickle) {
 		TypeName:     "Compliance Finding: Test",
 	}
 
+	if f.params != nil {
+		finding.Unmapped.Instance = f.params.Instance.ID
+	}
+
+	if w := MatchWaiver(f.params, f.currentFeature, pickle.Name); w != nil {
+		finding.Unmapped.Waiver = &OCSFWaiver{
+			Ticket:        w.Ticket,
+			Owner:         w.Owner,
+			Justification: w.Justification,
+			Expires:       w.Expires,
+			Expired:       w.Expired(now),
+		}
+	}
+
 	// Add resources section if params are available
 	if f.params != nil && (f.params.UID != "" || f.params.HostName != "") {
 		resourceName := f.params.HostName
@@ -270,6 +327,7 @@ func (f *OCSFFormatter) TestRunFinished(msg *messages.TestRunFinished) {
 	// Finalize any pending scenario
 	if f.scenarioStarted && f.currentScenario != nil {
 		applyExclusionStatusOverride(f.currentScenario)
+		applyWaiverStatusOverride(f.currentScenario)
 		f.findings = append(f.findings, *f.currentScenario)
 		f.scenarioStarted = false
 	}
@@ -280,6 +338,7 @@ func (f *OCSFFormatter) Summary() {
 	// Finalize any pending scenario
 	if f.scenarioStarted && f.currentScenario != nil {
 		applyExclusionStatusOverride(f.currentScenario)
+		applyWaiverStatusOverride(f.currentScenario)
 		f.findings = append(f.findings, *f.currentScenario)
 		f.scenarioStarted = false
 	}
@@ -0,0 +1,159 @@
+package reporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// ServiceError is a service that could not be started (factory, client or discovery failure),
+// so none of its scenarios ran
+type ServiceError struct {
+	Instance string
+	Service  string
+	Stage    string // factory, service, discovery or features
+	Message  string
+}
+
+// RecordServiceError adds an errored service to the summary report
+func RecordServiceError(instanceID, serviceType, stage string, err error) {
+	summaryCollector.mu.Lock()
+	defer summaryCollector.mu.Unlock()
+	summaryCollector.serviceErrors = append(summaryCollector.serviceErrors, ServiceError{
+		Instance: instanceID,
+		Service:  serviceType,
+		Stage:    stage,
+		Message:  err.Error(),
+	})
+}
+
+// WriteServiceErrorOCSF writes <service>-error.ocsf.json to outputDir holding a single ERROR
+// finding for the service, so combined.ocsf.json shows it was not assessed. Returns the path.
+func WriteServiceErrorOCSF(outputDir string, instance types.InstanceConfig, serviceType, stage string, err error) (string, error) {
+	now := time.Now()
+	message := fmt.Sprintf("%s service errored during %s", serviceType, stage)
+
+	finding := OCSFFinding{
+		Message: message,
+		Metadata: OCSFMetadata{
+			EventCode: message,
+			Product: OCSFProduct{
+				Name:       "CCC-Complete",
+				UID:        "CCC-Complete",
+				VendorName: "FINOS",
+				Version:    productVersion,
+			},
+			Profiles: []string{},
+			Version:  "1.4.0",
+		},
+		SeverityID:   3,
+		Severity:     "Medium",
+		Status:       "New",
+		StatusCode:   "ERROR",
+		StatusDetail: err.Error(),
+		StatusID:     1,
+		Unmapped: OCSFUnmapped{
+			Compliance: map[string][]string{},
+			Instance:   instance.ID,
+		},
+		ActivityName: "Test",
+		ActivityID:   1,
+		FindingInfo: OCSFFindingInfo{
+			CreatedTime:   now.Unix(),
+			CreatedTimeDT: now.Format(time.RFC3339),
+			Desc:          fmt.Sprintf("Service could not be tested: %v", err),
+			Title:         message,
+			Types:         []string{},
+			UID:           fmt.Sprintf("ccc-service-error-%s-%s-%d", instance.ID, serviceType, now.Unix()),
+		},
+		CategoryName: "Findings",
+		CategoryUID:  2,
+		ClassName:    "Compliance Finding",
+		ClassUID:     2004,
+		Time:         now.Unix(),
+		TimeDT:       now.Format(time.RFC3339),
+		TypeUID:      200401,
+		TypeName:     "Compliance Finding: Test",
+		Resources: []OCSFResource{
+			{
+				CloudPartition: instance.Properties.Provider,
+				Region:         instance.Properties.Region,
+				Data: OCSFResourceData{
+					Details: fmt.Sprintf("%s service on instance %s", serviceType, instance.ID),
+					Metadata: OCSFResourceMetadata{
+						Name:     serviceType,
+						Status:   "ERROR",
+						Findings: []string{},
+						Tags:     []string{},
+						Type:     serviceType,
+						Region:   instance.Properties.Region,
+					},
+				},
+				Group: OCSFResourceGroup{Name: serviceType},
+				Name:  serviceType,
+				Type:  serviceType,
+				UID:   fmt.Sprintf("%s/%s", instance.ID, serviceType),
+			},
+		},
+	}
+
+	data, merr := json.MarshalIndent([]OCSFFinding{finding}, "", "    ")
+	if merr != nil {
+		return "", fmt.Errorf("failed to marshal error finding: %w", merr)
+	}
+	path := filepath.Join(outputDir, serviceType+"-error.ocsf.json")
+	if werr := os.WriteFile(path, data, 0644); werr != nil {
+		return "", fmt.Errorf("failed to write error finding: %w", werr)
+	}
+	return path, nil
+}
+
+// serviceErrorsHTML renders the errored services section of summary.html ("" if none)
+func serviceErrorsHTML(errs []ServiceError, showInstance bool) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	buf.WriteString("        <h2>Errored Services</h2>\n")
+	buf.WriteString("        <table>\n            <thead>\n                <tr>\n")
+	if showInstance {
+		buf.WriteString("                    <th>Instance</th>\n")
+	}
+	buf.WriteString("                    <th>Service</th>\n                    <th>Stage</th>\n                    <th>Error</th>\n")
+	buf.WriteString("                </tr>\n            </thead>\n            <tbody>\n")
+	for _, e := range errs {
+		buf.WriteString("                <tr class=\"failing\">\n")
+		if showInstance {
+			buf.WriteString(fmt.Sprintf("                    <td>%s</td>\n", escapeHTML(e.Instance)))
+		}
+		buf.WriteString(fmt.Sprintf("                    <td><strong>%s</strong></td>\n", escapeHTML(e.Service)))
+		buf.WriteString(fmt.Sprintf("                    <td>%s</td>\n", escapeHTML(e.Stage)))
+		buf.WriteString(fmt.Sprintf("                    <td>%s</td>\n", escapeHTML(e.Message)))
+		buf.WriteString("                </tr>\n")
+	}
+	buf.WriteString("            </tbody>\n        </table>\n")
+	return buf.String()
+}
+
+// serviceErrorsText renders the errored services for the console summary ("" if none)
+func serviceErrorsText(errs []ServiceError) string {
+	if len(errs) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	buf.WriteString("\nErrored Services (not assessed)\n")
+	for _, e := range errs {
+		name := e.Service
+		if e.Instance != "" {
+			name = e.Instance + "/" + e.Service
+		}
+		buf.WriteString(fmt.Sprintf("  ❌ %s [%s]: %s\n", name, e.Stage, strings.TrimSpace(e.Message)))
+	}
+	return buf.String()
+}
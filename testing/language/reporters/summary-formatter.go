@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cucumber/godog/formatters"
 	messages "github.com/cucumber/messages/go/v21"
@@ -21,16 +22,23 @@ type SummaryResult struct {
 	ScenarioName string // Scenario with exclusion tag suffix (e.g. "Name - NotTestable")
 	Badge        string // .e.g., "Not Testable"
 	IsPolicy     bool
-	Outcome      string // "PASSING", "FAILING" - same logic as OCSF
-	exclusionTag string // NotTested, NotTestable, Duplicate
+	Outcome      string  // "PASSING", "FAILING", "WAIVED" - same logic as OCSF
+	Instance     string  // Instance ID from environment.yaml (e.g. main-aws)
+	exclusionTag string  // NotTested, NotTestable, Duplicate
+	waiver       *Waiver // Waiver covering the scenario, if any
 	failed       bool
+	stepFailed   bool   // A step failed, or was undefined or pending, so godog failed the scenario
+	suite        string // Suite that produced the result; keeps ordering stable across parallel runs
 }
 
-// summaryCollector holds all results for the summary report
+// summaryCollector holds all results for the summary report.
+// Formatters for different resources may run concurrently, so each formatter buffers
+// its own results and appends them under mu when its suite finishes.
 var summaryCollector struct {
-	mu      sync.Mutex
-	results []SummaryResult
-	current *SummaryResult
+	mu            sync.Mutex
+	results       []SummaryResult
+	current       *SummaryResult
+	serviceErrors []ServiceError
 }
 
 // Control tag pattern: CCC.XXX.YYYY or CCC.XXX.YYYY.ARZZ
@@ -39,8 +47,13 @@ var controlPattern = "CCC."
 // SummaryFormatter is a godog formatter that collects results for a summary report
 type SummaryFormatter struct {
 	out            io.Writer
+	suite          string
+	instance       string
 	currentFeature string
 	currentResult  *SummaryResult
+	results        []SummaryResult
+	params         *TestParams    // Optional test parameters, used to match waivers
+	tally          *ScenarioTally // Optional; counts failed and waived scenarios for the runner
 }
 
 // Feature captures feature information (control ID and description, as in html-formatter)
@@ -96,9 +109,24 @@ func (f *SummaryFormatter) Pickle(pickle *messages.Pickle) {
 		IsPolicy:     isPolicy,
 
 		exclusionTag: exclusionTag,
+		waiver:       MatchWaiver(f.params, control, pickle.Name),
 	}
 }
 
+// Badges shown for failures covered by a waiver
+const (
+	waivedBadge        = "Waived"
+	waiverExpiredBadge = "Waiver expired"
+)
+
+// joinBadges appends a badge to an existing one, e.g. "NotTested, Waived"
+func joinBadges(badge, extra string) string {
+	if badge == "" {
+		return extra
+	}
+	return badge + ", " + extra
+}
+
 func (f *SummaryFormatter) finalizeResult() {
 	if f.currentResult == nil {
 		return
@@ -116,16 +144,31 @@ func (f *SummaryFormatter) finalizeResult() {
 		}
 	}
 
-	summaryCollector.mu.Lock()
-	summaryCollector.results = append(summaryCollector.results, SummaryResult{
+	// A failure covered by a waiver is shown as waived until the waiver expires
+	badge := r.Badge
+	waiverApplies := r.waiver != nil && !r.waiver.Expired(time.Now())
+	if r.stepFailed && f.tally != nil {
+		f.tally.add(waiverApplies)
+	}
+	if r.waiver != nil && outcome == "FAILING" {
+		if !waiverApplies {
+			badge = joinBadges(badge, waiverExpiredBadge+" ("+r.waiver.Ticket+")")
+		} else {
+			outcome = "WAIVED"
+			badge = joinBadges(badge, waivedBadge+" ("+r.waiver.Ticket+")")
+		}
+	}
+
+	f.results = append(f.results, SummaryResult{
 		Control:      r.Control,
 		Scenario:     r.Scenario,
 		ScenarioName: r.ScenarioName,
 		IsPolicy:     r.IsPolicy,
-		Badge:        r.Badge,
+		Badge:        badge,
 		Outcome:      outcome,
+		Instance:     f.instance,
+		suite:        f.suite,
 	})
-	summaryCollector.mu.Unlock()
 
 	f.currentResult = nil
 }
@@ -136,11 +179,17 @@ func (f *SummaryFormatter) TestRunStarted() {}
 // TestRunFinished is required by the formatters.Formatter interface
 func (f *SummaryFormatter) TestRunFinished(msg *messages.TestRunFinished) {}
 
-// Summary finalizes the last scenario; the actual report is generated by GenerateSummaryReport
+// Summary finalizes the last scenario and hands this suite's results to the collector;
+// the actual report is generated by GenerateSummaryReport
 func (f *SummaryFormatter) Summary() {
 	if f.currentResult != nil {
 		f.finalizeResult()
 	}
+
+	summaryCollector.mu.Lock()
+	summaryCollector.results = append(summaryCollector.results, f.results...)
+	summaryCollector.mu.Unlock()
+	f.results = nil
 }
 
 // Defined is required by the formatters.Formatter interface
@@ -164,6 +213,7 @@ func (f *SummaryFormatter) Skipped(pickle *messages.Pickle, step *messages.Pickl
 func (f *SummaryFormatter) Undefined(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
 	if f.currentResult != nil {
 		f.currentResult.failed = true
+		f.currentResult.stepFailed = true
 	}
 }
 
@@ -171,6 +221,7 @@ func (f *SummaryFormatter) Undefined(pickle *messages.Pickle, step *messages.Pic
 func (f *SummaryFormatter) Failed(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition, err error) {
 	if f.currentResult != nil {
 		f.currentResult.failed = true
+		f.currentResult.stepFailed = true
 	}
 }
 
@@ -178,12 +229,25 @@ func (f *SummaryFormatter) Failed(pickle *messages.Pickle, step *messages.Pickle
 func (f *SummaryFormatter) Pending(pickle *messages.Pickle, step *messages.PickleStep, def *formatters.StepDefinition) {
 	if f.currentResult != nil {
 		f.currentResult.failed = true
+		f.currentResult.stepFailed = true
 	}
 }
 
 // NewSummaryFormatter creates a summary formatter that collects results for later report generation
 func NewSummaryFormatter(suite string, out io.Writer) formatters.Formatter {
-	return &SummaryFormatter{out: out}
+	return &SummaryFormatter{out: out, suite: suite}
+}
+
+// NewSummaryFormatterWithParams creates a summary formatter that tags its results with the
+// instance under test, so results from several instances can be compared side by side
+func NewSummaryFormatterWithParams(suite string, out io.Writer, params TestParams) formatters.Formatter {
+	return newSummaryFormatter(suite, out, params, nil)
+}
+
+// newSummaryFormatter creates a summary formatter for one resource that also counts its
+// failed and waived scenarios in tally
+func newSummaryFormatter(suite string, out io.Writer, params TestParams, tally *ScenarioTally) formatters.Formatter {
+	return &SummaryFormatter{out: out, suite: suite, instance: params.Instance.ID, params: &params, tally: tally}
 }
 
 // SummaryData holds the aggregated summary for report generation
@@ -197,17 +261,80 @@ type SummaryData struct {
 	PassingBehaviouralBadges []string
 	FailingBehavioural       []string
 	FailingBehaviouralBadges []string
+	Waived                   []string // Failures accepted by a waiver, @Policy and @Behavioural
+	WaivedBadges             []string
 }
 
 // GenerateSummaryReport produces summary.html and prints to the console.
-// Call this after all test runs have completed.
+// When the collected results span more than one instance, summary.html holds the
+// cross-instance view with one column per instance (see GenerateInstanceSummaryReport
+// for the per-instance reports). Call this after all test runs have completed.
 func GenerateSummaryReport(outputDir string) error {
 	summaryCollector.mu.Lock()
 	results := make([]SummaryResult, len(summaryCollector.results))
 	copy(results, summaryCollector.results)
 	summaryCollector.results = nil
+	serviceErrors := summaryCollector.serviceErrors
+	summaryCollector.serviceErrors = nil
 	summaryCollector.mu.Unlock()
 
+	// Instances in the order they were run
+	instances := summaryInstances(results, serviceErrors)
+	if len(instances) > 1 {
+		return writeCrossInstanceSummary(outputDir, instances, results, serviceErrors)
+	}
+	return writeSummaryReport(outputDir, results, serviceErrors)
+}
+
+// GenerateInstanceSummaryReport writes summary.html for a single instance's results without
+// removing them from the collector, so GenerateSummaryReport can still build the cross-instance view.
+func GenerateInstanceSummaryReport(outputDir string, instanceID string) error {
+	summaryCollector.mu.Lock()
+	var results []SummaryResult
+	for _, r := range summaryCollector.results {
+		if r.Instance == instanceID {
+			results = append(results, r)
+		}
+	}
+	var serviceErrors []ServiceError
+	for _, e := range summaryCollector.serviceErrors {
+		if e.Instance == instanceID {
+			serviceErrors = append(serviceErrors, e)
+		}
+	}
+	summaryCollector.mu.Unlock()
+
+	return writeSummaryReport(outputDir, results, serviceErrors)
+}
+
+// summaryInstances returns the distinct instance IDs in results and errored services,
+// in first-seen order
+func summaryInstances(results []SummaryResult, serviceErrors []ServiceError) []string {
+	seen := make(map[string]bool)
+	var instances []string
+	add := func(instance string) {
+		if !seen[instance] {
+			seen[instance] = true
+			instances = append(instances, instance)
+		}
+	}
+	for _, r := range results {
+		add(r.Instance)
+	}
+	for _, e := range serviceErrors {
+		add(e.Instance)
+	}
+	return instances
+}
+
+// writeSummaryReport aggregates results per control into summary.html and prints the text table.
+// Errored services are listed after the table.
+func writeSummaryReport(outputDir string, results []SummaryResult, serviceErrors []ServiceError) error {
+	// Suites may finish in any order when run in parallel; group by suite for deterministic output
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].suite < results[j].suite
+	})
+
 	// Aggregate by control -> columns -> scenario names
 	byControl := make(map[string]*SummaryData)
 	for _, r := range results {
@@ -223,6 +350,9 @@ func GenerateSummaryReport(outputDir string) error {
 			scenarioName = r.Scenario
 		}
 		switch {
+		case r.Outcome == "WAIVED":
+			d.Waived = append(d.Waived, scenarioName)
+			d.WaivedBadges = append(d.WaivedBadges, r.Badge)
 		case r.IsPolicy && r.Outcome == "PASSING":
 			d.PassingPolicy = append(d.PassingPolicy, scenarioName)
 			d.PassingPolicyBadges = append(d.PassingPolicyBadges, r.Badge)
@@ -245,20 +375,20 @@ func GenerateSummaryReport(outputDir string) error {
 	}
 	sort.Strings(controls)
 
-	html := generateSummaryHTML(controls, byControl)
+	html := generateSummaryHTML(controls, byControl, serviceErrors)
 	summaryPath := filepath.Join(outputDir, "summary.html")
 	if err := os.WriteFile(summaryPath, []byte(html), 0644); err != nil {
 		return fmt.Errorf("write summary.html: %w", err)
 	}
 
 	// Print to console
-	text := generateSummaryText(controls, byControl)
+	text := generateSummaryText(controls, byControl) + serviceErrorsText(serviceErrors)
 	fmt.Println(text)
 
 	return nil
 }
 
-func generateSummaryHTML(controls []string, byControl map[string]*SummaryData) string {
+func generateSummaryHTML(controls []string, byControl map[string]*SummaryData, serviceErrors []ServiceError) string {
 	var buf bytes.Buffer
 	buf.WriteString(`<!DOCTYPE html>
 <html>
@@ -276,12 +406,14 @@ func generateSummaryHTML(controls []string, byControl map[string]*SummaryData) s
         .passing { background: #e8f5e9; }
         .failing { background: #ffebee; }
         .noop { background: #fff8e1; }
+        .waived { background: #fff3e0; }
         .cell-list { margin: 0; padding-left: 16px; }
         .cell-list li { margin: 4px 0; }
         .badge { display: inline-block; padding: 4px 10px; margin: 2px 0; border-radius: 12px;
             font-size: 0.9em; font-weight: 500; }
         .badge-passing { background: #c8e6c9; color: #2e7d32; border: 1px solid #81c784; }
         .badge-failing { background: #ffcdd2; color: #c62828; border: 1px solid #e57373; }
+        .badge-waived { background: #ffe0b2; color: #e65100; border: 1px solid #ffb74d; }
     </style>
 </head>
 <body>
@@ -295,6 +427,7 @@ func generateSummaryHTML(controls []string, byControl map[string]*SummaryData) s
                     <th class="failing">FAILING @Policy</th>
                     <th class="passing">PASSING @Behavioural</th>
                     <th class="failing">FAILING @Behavioural</th>
+                    <th class="waived">WAIVED</th>
                 </tr>
             </thead>
             <tbody>
@@ -307,11 +440,14 @@ func generateSummaryHTML(controls []string, byControl map[string]*SummaryData) s
 		buf.WriteString(fmt.Sprintf("                    <td class=\"failing\">%s</td>\n", scenarioListHTML(d.FailingPolicy, d.FailingPolicyBadges, "failing")))
 		buf.WriteString(fmt.Sprintf("                    <td class=\"passing\">%s</td>\n", scenarioListHTML(d.PassingBehavioural, d.PassingBehaviouralBadges, "passing")))
 		buf.WriteString(fmt.Sprintf("                    <td class=\"failing\">%s</td>\n", scenarioListHTML(d.FailingBehavioural, d.FailingBehaviouralBadges, "failing")))
+		buf.WriteString(fmt.Sprintf("                    <td class=\"waived\">%s</td>\n", scenarioListHTML(d.Waived, d.WaivedBadges, "waived")))
 		buf.WriteString("                </tr>\n")
 	}
 	buf.WriteString(`            </tbody>
         </table>
-    </div>
+`)
+	buf.WriteString(serviceErrorsHTML(serviceErrors, false))
+	buf.WriteString(`    </div>
 </body>
 </html>`)
 	return buf.String()
@@ -327,7 +463,11 @@ func scenarioListHTML(scenarios []string, badges []string, badgeType string) str
 		b.WriteString("<li>")
 		b.WriteString(escapeHTML(s))
 		if badges[i] != "" {
-			b.WriteString("<span class=\"badge badge-" + badgeType + "\">")
+			class := badgeType
+			if strings.Contains(badges[i], waivedBadge) || strings.Contains(badges[i], waiverExpiredBadge) {
+				class = "waived"
+			}
+			b.WriteString("<span class=\"badge badge-" + class + "\">")
 			b.WriteString(escapeHTML(badges[i]))
 			b.WriteString("</span>")
 		}
@@ -347,10 +487,10 @@ func escapeHTML(s string) string {
 
 func generateSummaryText(controls []string, byControl map[string]*SummaryData) string {
 	var buf bytes.Buffer
-	headers := []string{"Control", "PASSING @Policy", "FAILING @Policy", "PASSING @Behavioural", "FAILING @Behavioural"}
+	headers := []string{"Control", "PASSING @Policy", "FAILING @Policy", "PASSING @Behavioural", "FAILING @Behavioural", "WAIVED"}
 	controlColWidth := 55 // Wide enough for "CCC.XXX.YYYY.ARZZ - Description"
 	colWidth := 20
-	separatorLen := controlColWidth + 4*(3+colWidth) // control + 4 columns with " | "
+	separatorLen := controlColWidth + 5*(3+colWidth) // control + 5 columns with " | "
 
 	buf.WriteString("\n" + strings.Repeat("=", separatorLen) + "\n")
 	buf.WriteString("CCC Compliance Test Summary\n")
@@ -379,6 +519,7 @@ func generateSummaryText(controls []string, byControl map[string]*SummaryData) s
 			strings.Join(d.FailingPolicy, ", "),
 			strings.Join(d.PassingBehavioural, ", "),
 			strings.Join(d.FailingBehavioural, ", "),
+			strings.Join(d.Waived, ", "),
 		}
 		buf.WriteString(pad(row[0], controlColWidth))
 		for _, cell := range row[1:] {
@@ -387,7 +528,7 @@ func generateSummaryText(controls []string, byControl map[string]*SummaryData) s
 		}
 		buf.WriteString("\n")
 		// If any cell has multiple scenarios, print them on following lines
-		allCells := [][]string{d.PassingPolicy, d.FailingPolicy, d.PassingBehavioural, d.FailingBehavioural}
+		allCells := [][]string{d.PassingPolicy, d.FailingPolicy, d.PassingBehavioural, d.FailingBehavioural, d.Waived}
 		maxLen := 0
 		for _, c := range allCells {
 			if len(c) > maxLen {
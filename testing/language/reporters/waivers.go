@@ -0,0 +1,182 @@
+package reporters
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// waiverDateFormat is the format of a waiver's expiry date
+const waiverDateFormat = "2006-01-02"
+
+// Waiver accepts a known failure until its expiry date. A waiver matches a scenario when
+// every selector it sets matches; control is required, the other selectors are optional.
+type Waiver struct {
+	Instance      string   `yaml:"instance,omitempty" json:"instance,omitempty"` // Instance ID from environment.yaml
+	Resource      string   `yaml:"resource,omitempty" json:"resource,omitempty"` // Resource name or UID
+	Labels        []string `yaml:"labels,omitempty" json:"labels,omitempty"`     // Every label must be on the resource
+	Control       string   `yaml:"control" json:"control"`                       // Control or AR, e.g. CCC.Core.CN01 or CCC.Core.CN01.AR01
+	Scenario      string   `yaml:"scenario,omitempty" json:"scenario,omitempty"` // Scenario name; empty matches every scenario of the control
+	Owner         string   `yaml:"owner" json:"owner"`
+	Ticket        string   `yaml:"ticket" json:"ticket"`
+	Justification string   `yaml:"justification" json:"justification"`
+	Expires       string   `yaml:"expires" json:"expires"` // YYYY-MM-DD; the waiver applies up to and including this day
+
+	expiry time.Time
+}
+
+// WaiverFile is the layout of the waivers YAML file
+type WaiverFile struct {
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// waiverSet holds the waivers loaded for this run; formatters for different resources
+// may run concurrently
+var waiverSet struct {
+	mu      sync.RWMutex
+	waivers []Waiver
+}
+
+// LoadWaivers reads the waivers file and applies it to all formatters created afterwards.
+// Every waiver must name a control, owner, ticket, justification and a valid expiry date.
+func LoadWaivers(path string) ([]Waiver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waivers file %s: %w", path, err)
+	}
+	var file WaiverFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse waivers file %s: %w", path, err)
+	}
+
+	for i := range file.Waivers {
+		w := &file.Waivers[i]
+		var missing []string
+		for _, field := range [][2]string{
+			{"control", w.Control},
+			{"owner", w.Owner},
+			{"ticket", w.Ticket},
+			{"justification", w.Justification},
+			{"expires", w.Expires},
+		} {
+			if strings.TrimSpace(field[1]) == "" {
+				missing = append(missing, field[0])
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("waiver %d in %s is missing: %s", i+1, path, strings.Join(missing, ", "))
+		}
+		w.expiry, err = time.Parse(waiverDateFormat, w.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("waiver %d in %s has an invalid expiry date '%s' (want YYYY-MM-DD)", i+1, path, w.Expires)
+		}
+	}
+
+	waiverSet.mu.Lock()
+	waiverSet.waivers = file.Waivers
+	waiverSet.mu.Unlock()
+	return file.Waivers, nil
+}
+
+// Expired reports whether the waiver's expiry date has passed
+func (w *Waiver) Expired(now time.Time) bool {
+	return !now.UTC().Before(w.expiry.AddDate(0, 0, 1))
+}
+
+// Describe summarises the waiver for reports, e.g. "SEC-123 (alice, until 2025-06-30): reason"
+func (w *Waiver) Describe() string {
+	return fmt.Sprintf("%s (%s, until %s): %s", w.Ticket, w.Owner, w.Expires, w.Justification)
+}
+
+// matches reports whether the waiver covers the scenario of feature run against params
+func (w *Waiver) matches(params *TestParams, feature, scenario string) bool {
+	control := featureID(feature)
+	if control != w.Control && !strings.HasPrefix(control, w.Control+".") {
+		return false
+	}
+	if w.Scenario != "" && w.Scenario != scenario {
+		return false
+	}
+	if w.Instance != "" && (params == nil || params.Instance.ID != w.Instance) {
+		return false
+	}
+	if w.Resource != "" && (params == nil || (params.ResourceName != w.Resource && params.UID != w.Resource)) {
+		return false
+	}
+	for _, label := range w.Labels {
+		if params == nil || !containsString(params.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchWaiver returns the waiver covering a scenario, or nil. When several waivers match,
+// one that has not expired is preferred, so renewing a waiver does not need the old one removed.
+func MatchWaiver(params *TestParams, feature, scenario string) *Waiver {
+	waiverSet.mu.RLock()
+	defer waiverSet.mu.RUnlock()
+
+	var match *Waiver
+	now := time.Now()
+	for i := range waiverSet.waivers {
+		w := &waiverSet.waivers[i]
+		if !w.matches(params, feature, scenario) {
+			continue
+		}
+		if !w.Expired(now) {
+			return w
+		}
+		if match == nil {
+			match = w
+		}
+	}
+	return match
+}
+
+// ScenarioTally counts the scenarios of one resource that failed, separating those whose
+// failure an unexpired waiver accepts, so the runner can pass a resource whose only
+// failures are waived. It is filled in by the resource's summary formatter.
+type ScenarioTally struct {
+	mu     sync.Mutex
+	failed int
+	waived int
+}
+
+// add counts one failed scenario
+func (t *ScenarioTally) add(waived bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if waived {
+		t.waived++
+	} else {
+		t.failed++
+	}
+}
+
+// Counts returns the number of failed scenarios without a waiver, and with one
+func (t *ScenarioTally) Counts() (failed, waived int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failed, t.waived
+}
+
+// featureID returns the control ID from a feature name, e.g. "CCC.Core.CN01.AR01 - Title"
+// gives "CCC.Core.CN01.AR01"
+func featureID(feature string) string {
+	id, _, _ := strings.Cut(feature, " - ")
+	return strings.TrimSpace(id)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
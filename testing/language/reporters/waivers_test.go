@@ -0,0 +1,208 @@
+package reporters
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	messages "github.com/cucumber/messages/go/v21"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// loadTestWaivers writes yaml to a waivers file and loads it, restoring the waiver set afterwards
+func loadTestWaivers(t *testing.T, yaml string) []Waiver {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "waivers.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		waiverSet.mu.Lock()
+		waiverSet.waivers = nil
+		waiverSet.mu.Unlock()
+	})
+	waivers, err := LoadWaivers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return waivers
+}
+
+func TestWaiverMatches(t *testing.T) {
+	params := &TestParams{
+		Instance:     types.InstanceConfig{ID: "main-aws"},
+		ResourceName: "bucket-1",
+		UID:          "arn:aws:s3:::bucket-1",
+		Labels:       []string{"env=prod", "pci"},
+	}
+	const feature = "CCC.Core.CN01.AR01 - Encrypt data in transit"
+	const scenario = "Service rejects plain HTTP"
+
+	tests := []struct {
+		name   string
+		waiver Waiver
+		params *TestParams
+		want   bool
+	}{
+		{"exact control", Waiver{Control: "CCC.Core.CN01.AR01"}, params, true},
+		{"control prefix", Waiver{Control: "CCC.Core.CN01"}, params, true},
+		{"control prefix stops at a dot", Waiver{Control: "CCC.Core.CN0"}, params, false},
+		{"other control", Waiver{Control: "CCC.Core.CN02"}, params, false},
+		{"scenario", Waiver{Control: "CCC.Core.CN01", Scenario: scenario}, params, true},
+		{"other scenario", Waiver{Control: "CCC.Core.CN01", Scenario: "Another"}, params, false},
+		{"instance", Waiver{Control: "CCC.Core.CN01", Instance: "main-aws"}, params, true},
+		{"other instance", Waiver{Control: "CCC.Core.CN01", Instance: "main-azure"}, params, false},
+		{"resource name", Waiver{Control: "CCC.Core.CN01", Resource: "bucket-1"}, params, true},
+		{"resource UID", Waiver{Control: "CCC.Core.CN01", Resource: "arn:aws:s3:::bucket-1"}, params, true},
+		{"other resource", Waiver{Control: "CCC.Core.CN01", Resource: "bucket-2"}, params, false},
+		{"labels", Waiver{Control: "CCC.Core.CN01", Labels: []string{"pci", "env=prod"}}, params, true},
+		{"missing label", Waiver{Control: "CCC.Core.CN01", Labels: []string{"pci", "env=dev"}}, params, false},
+		{"every selector", Waiver{Control: "CCC.Core.CN01", Scenario: scenario, Instance: "main-aws",
+			Resource: "bucket-1", Labels: []string{"pci"}}, params, true},
+		{"no params, control only", Waiver{Control: "CCC.Core.CN01"}, nil, true},
+		{"no params, instance", Waiver{Control: "CCC.Core.CN01", Instance: "main-aws"}, nil, false},
+		{"no params, resource", Waiver{Control: "CCC.Core.CN01", Resource: "bucket-1"}, nil, false},
+		{"no params, labels", Waiver{Control: "CCC.Core.CN01", Labels: []string{"pci"}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.waiver.matches(tt.params, feature, scenario); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaiverExpired(t *testing.T) {
+	w := Waiver{expiry: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2025, 6, 29, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC), false},
+		{time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC), true},
+		// The boundary is midnight UTC whatever the local zone
+		{time.Date(2025, 6, 30, 20, 0, 0, 0, time.FixedZone("UTC-5", -5*3600)), true},
+		{time.Date(2025, 7, 1, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*3600)), false},
+	}
+	for _, tt := range tests {
+		if got := w.Expired(tt.now); got != tt.want {
+			t.Errorf("Expired(%s) = %v, want %v", tt.now.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestLoadWaiversValidates(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"missing fields", "waivers:\n  - control: CCC.Core.CN01\n    expires: 2025-06-30\n"},
+		{"invalid date", "waivers:\n  - control: CCC.Core.CN01\n    owner: alice\n    ticket: SEC-1\n    justification: reason\n    expires: 30/06/2025\n"},
+		{"not yaml", "waivers: [\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "waivers.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadWaivers(path); err == nil {
+				t.Error("LoadWaivers succeeded, want an error")
+			}
+		})
+	}
+	if _, err := LoadWaivers(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("LoadWaivers of a missing file succeeded, want an error")
+	}
+}
+
+func TestMatchWaiverPrefersUnexpired(t *testing.T) {
+	loadTestWaivers(t, `waivers:
+  - control: CCC.Core.CN01
+    owner: alice
+    ticket: SEC-1
+    justification: old
+    expires: 2000-01-01
+  - control: CCC.Core.CN01
+    owner: alice
+    ticket: SEC-2
+    justification: renewed
+    expires: 2999-01-01
+`)
+	w := MatchWaiver(nil, "CCC.Core.CN01.AR01 - Title", "Scenario")
+	if w == nil || w.Ticket != "SEC-2" {
+		t.Errorf("MatchWaiver() = %+v, want SEC-2", w)
+	}
+	if w := MatchWaiver(nil, "CCC.Core.CN02.AR01 - Title", "Scenario"); w != nil {
+		t.Errorf("MatchWaiver() for another control = %+v, want nil", w)
+	}
+}
+
+func TestApplyWaiverStatusOverride(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		waiver     *OCSFWaiver
+		wantStatus string
+	}{
+		{"unexpired waiver", "FAIL", &OCSFWaiver{Ticket: "SEC-1", Expires: "2999-01-01"}, "WAIVED"},
+		{"expired waiver", "FAIL", &OCSFWaiver{Ticket: "SEC-1", Expires: "2000-01-01", Expired: true}, "FAIL"},
+		{"passing scenario", "PASS", &OCSFWaiver{Ticket: "SEC-1", Expires: "2999-01-01"}, "PASS"},
+		{"no waiver", "FAIL", nil, "FAIL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := finding("Scenario", tt.status)
+			f.Unmapped.Waiver = tt.waiver
+			applyWaiverStatusOverride(&f)
+			if f.StatusCode != tt.wantStatus {
+				t.Errorf("status = %s, want %s", f.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHTMLFormatterCountsWaivedScenarios(t *testing.T) {
+	loadTestWaivers(t, `waivers:
+  - control: CCC.Core.CN01
+    scenario: Waived
+    owner: alice
+    ticket: SEC-1
+    justification: accepted
+    expires: 2999-01-01
+  - control: CCC.Core.CN01
+    scenario: Expired
+    owner: alice
+    ticket: SEC-2
+    justification: lapsed
+    expires: 2000-01-01
+`)
+	var out bytes.Buffer
+	f := NewHTMLFormatterWithParams("suite", &out, TestParams{}).(*HTMLFormatter)
+	f.currentFeature = "CCC.Core.CN01.AR01 - Title"
+	for _, name := range []string{"Waived", "Expired", "Unwaived"} {
+		pickle := &messages.Pickle{Name: name}
+		f.Pickle(pickle)
+		f.Failed(pickle, &messages.PickleStep{Text: "a step"}, nil, errors.New("failed"))
+		// Rendering the waiver again must not change the counts
+		f.waiverHTML()
+	}
+	f.Summary()
+
+	if f.stats.failedScenarios != 2 || f.stats.waivedScenarios != 1 {
+		t.Errorf("failed = %d, waived = %d, want 2 and 1", f.stats.failedScenarios, f.stats.waivedScenarios)
+	}
+	html := out.String()
+	if !strings.Contains(html, "Waived:</strong> SEC-1") || !strings.Contains(html, "Waiver expired:</strong> SEC-2") {
+		t.Error("report does not show the waived and expired waivers")
+	}
+}
@@ -9,8 +9,19 @@ ENV_FILE=""
 SERVICE=""
 OUTPUT_DIR=""
 TIMEOUT="30m"
+TEARDOWN_TIMEOUT=""
 RESOURCE_FILTER=""
+SELECTOR=""
+INVENTORY=""
 TAGS=""
+PARALLEL=""
+PLAN=""
+RECORD_DIR=""
+REPLAY_DIR=""
+JOURNAL_DIR=""
+KEEP_RUNS=""
+BASELINE=""
+REGRESSIONS_ONLY=""
 
 # Parse command line arguments
 while [[ $# -gt 0 ]]; do
@@ -35,40 +46,119 @@ while [[ $# -gt 0 ]]; do
       TIMEOUT="$2"
       shift 2
       ;;
+    --teardown-timeout)
+      TEARDOWN_TIMEOUT="$2"
+      shift 2
+      ;;
     -r|--resource)
       RESOURCE_FILTER="$2"
       shift 2
       ;;
+    --selector)
+      SELECTOR="$2"
+      shift 2
+      ;;
+    --inventory)
+      INVENTORY="$2"
+      shift 2
+      ;;
     -g|--tags)
       TAGS="$2"
       shift 2
       ;;
+    -p|--parallel)
+      PARALLEL="$2"
+      shift 2
+      ;;
+    --plan)
+      PLAN="true"
+      shift
+      ;;
+    --record)
+      RECORD_DIR="$2"
+      shift 2
+      ;;
+    --replay)
+      REPLAY_DIR="$2"
+      shift 2
+      ;;
+    --journal-dir)
+      JOURNAL_DIR="$2"
+      shift 2
+      ;;
+    --keep-runs)
+      KEEP_RUNS="$2"
+      shift 2
+      ;;
+    --baseline)
+      BASELINE="$2"
+      shift 2
+      ;;
+    --regressions-only)
+      REGRESSIONS_ONLY="true"
+      shift
+      ;;
+    --waivers)
+      WAIVERS="$2"
+      shift 2
+      ;;
+    --sign-with)
+      SIGN_WITH="$2"
+      shift 2
+      ;;
+    --strict-env)
+      STRICT_ENV="true"
+      shift
+      ;;
     -h|--help)
       echo "Usage: $0 [OPTIONS]"
       echo ""
       echo "Required Options:"
-      echo "  -i, --instance INSTANCE_ID           Instance ID from environment.yaml (e.g. main-aws, main-azure)"
+      echo "  -i, --instance INSTANCE_ID           Instance ID(s) from environment.yaml: one ID, a comma-separated list, or 'all'"
       echo ""
       echo "Optional Options:"
       echo "  -e, --env-file PATH                  Path to environment.yaml (default: testing/environment.yaml)"
+      echo "      --strict-env                     Refuse to start if environment.yaml uses unset variables for the services run"
+      echo "                                       Check the file without calling any cloud API: ./ccc-compliance validate-env"
       echo "  -s, --service SERVICE                Service type to test. If not specified, tests all services in the instance."
-      echo "                                       Valid values: object-storage, block-storage, relational-database,"
-      echo "                                                     iam, load-balancer, security-group, vpc, logging"
-      echo "  -o, --output DIR                     Output directory (default: testing/output)"
-      echo "  -r, --resource RESOURCE              Filter to specific resource name"
+      echo "                                       List the valid values with: ./ccc-compliance services"
+      echo "  -o, --output DIR                     Output directory; each run is written to DIR/<run-id> (default: testing/output)"
+      echo "      --keep-runs N                    Keep only the last N runs in the output directory (default: keep all)"
+      echo "      --baseline PATH                  Compare findings with a previous run (combined.ocsf.json or run directory)"
+      echo "      --regressions-only               With --baseline, exit non-zero only for new failures"
+      echo "      --waivers PATH                   Waivers for known failures (default: testing/waivers.yaml, if present)"
+      echo "      --sign-with KEY                  Write a signed evidence.tar.gz of the run, using an Ed25519 key (PEM)."
+      echo "                                       Check it with: ./ccc-compliance verify -public-key PUB evidence.tar.gz"
+      echo "  -r, --resource RESOURCE              Filter to resources by name: an exact name, a glob ('prod-*') or a /regex/"
+      echo "      --selector 'EXPR'                Filter to resources by label (e.g., 'env=prod,data-class in (confidential)')"
+      echo "      --inventory FILE                 Test the resources listed in a YAML or JSON file instead of discovering them"
       echo "  -g, --tags 'TAG1 TAG2 ...'           Space-separated tags ANDed with service tags (e.g., '@CCC.Core.CN01 @Policy')."
       echo "                                       By default @NEGATIVE and @OPT_IN scenarios are excluded."
       echo "                                       Tags are ANDed with the service filter, so include service tags explicitly."
       echo "                                       e.g. for VPC opt-in: '--tags @OPT_IN @CCC.VPC'"
       echo "  -t, --timeout DURATION               Timeout for all tests (default: 30m)"
+      echo "      --teardown-timeout DURATION      Deadline for cleanup, which runs even after a timeout or Ctrl-C (default: 10m)"
+      echo "  -p, --parallel N                     Test up to N resources of a service concurrently (default: 1)"
+      echo "      --plan                           List resources and the scenarios that would run, without running them."
+      echo "                                       Discovery is read-only: nothing is provisioned, elevated or torn down."
+      echo "      --record DIR                     Record sanitized provider SDK HTTP traffic to cassettes in DIR"
+      echo "      --replay DIR                     Replay provider SDK HTTP traffic from cassettes in DIR (no cloud access)"
+      echo "      --journal-dir DIR                Teardown journal of created resources (default: testing/journal)."
+      echo "                                       Sweep leaks with: ./ccc-compliance cleanup -run-id ID | -older-than 24h"
       echo "  -h, --help                           Show this help message"
       echo ""
       echo "Examples:"
       echo "  $0 --instance main-aws"
       echo "  $0 --instance main-azure --service object-storage"
+      echo "  $0 --instance main-aws,main-azure                     # cross-cloud comparison in output/latest/summary.html"
+      echo "  $0 --instance all --service object-storage"
       echo "  $0 --instance main-gcp --tags '@CCC.Core.CN04 @Policy'"
       echo "  $0 --instance main-aws --tags '@OPT_IN'               # run opt-in scenarios explicitly"
+      echo "  $0 --instance main-aws --parallel 8                   # test 8 buckets at a time"
+      echo "  $0 --instance main-aws --tags '@CCC.Core.CN01' --plan # preview what a tag expression selects"
       echo "  $0 --instance main-aws --env-file /path/to/custom-environment.yaml"
+      echo "  $0 --instance main-local                              # offline run against in-memory fakes"
+      echo "  $0 --instance main-aws --baseline output/latest --regressions-only  # fail only on new failures"
       exit 0
       ;;
     *)
@@ -81,7 +171,7 @@ done
 
 # Validate required arguments
 if [ -z "$INSTANCE" ]; then
-  echo "Error: --instance is required (e.g. main-aws, main-azure, main-gcp)"
+  echo "Error: --instance is required (e.g. main-aws, main-azure, main-gcp, main-local)"
   echo "Use -h or --help for usage information"
   exit 1
 fi
@@ -115,14 +205,70 @@ if [ -n "$OUTPUT_DIR" ]; then
   CMD="$CMD -output=\"$OUTPUT_DIR\""
 fi
 
+if [ -n "$TEARDOWN_TIMEOUT" ]; then
+  CMD="$CMD -teardown-timeout=\"$TEARDOWN_TIMEOUT\""
+fi
+
 if [ -n "$RESOURCE_FILTER" ]; then
   CMD="$CMD -resource=\"$RESOURCE_FILTER\""
 fi
 
+if [ -n "$SELECTOR" ]; then
+  CMD="$CMD -selector=\"$SELECTOR\""
+fi
+
+if [ -n "$INVENTORY" ]; then
+  CMD="$CMD -inventory=\"$INVENTORY\""
+fi
+
 if [ -n "$TAGS" ]; then
   CMD="$CMD -tags=\"$TAGS\""
 fi
 
+if [ -n "$PARALLEL" ]; then
+  CMD="$CMD -parallel=\"$PARALLEL\""
+fi
+
+if [ -n "$PLAN" ]; then
+  CMD="$CMD -plan"
+fi
+
+if [ -n "$RECORD_DIR" ]; then
+  CMD="$CMD -record=\"$RECORD_DIR\""
+fi
+
+if [ -n "$REPLAY_DIR" ]; then
+  CMD="$CMD -replay=\"$REPLAY_DIR\""
+fi
+
+if [ -n "$BASELINE" ]; then
+  CMD="$CMD -baseline=\"$BASELINE\""
+fi
+
+if [ -n "$REGRESSIONS_ONLY" ]; then
+  CMD="$CMD -regressions-only"
+fi
+
+if [ -n "$STRICT_ENV" ]; then
+  CMD="$CMD -strict-env"
+fi
+
+if [ -n "$SIGN_WITH" ]; then
+  CMD="$CMD -sign-with=\"$SIGN_WITH\""
+fi
+
+if [ -n "$WAIVERS" ]; then
+  CMD="$CMD -waivers=\"$WAIVERS\""
+fi
+
+if [ -n "$KEEP_RUNS" ]; then
+  CMD="$CMD -keep-runs=\"$KEEP_RUNS\""
+fi
+
+if [ -n "$JOURNAL_DIR" ]; then
+  CMD="$CMD -journal-dir=\"$JOURNAL_DIR\""
+fi
+
 # Execute the command
 echo "🚀 Running compliance tests..."
 eval $CMD
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
@@ -8,17 +9,55 @@ import (
 
 // RunConfig is the configuration for running compliance tests
 type RunConfig struct {
-	ServiceName    string // e.g., "object-storage", "iam"
-	Instance       types.InstanceConfig
-	OutputDir      string
-	Timeout        time.Duration
-	ResourceFilter string
-	Tags           []string // Tag filters to AND with service tags (e.g., ["@CCC.Core.CN01", "@Policy"])
+	ServiceName     string // e.g., "object-storage", "iam"
+	Instance        types.InstanceConfig
+	OutputDir       string
+	Timeout         time.Duration     // Deadline for the run; cancelling also aborts in-flight SDK calls
+	TeardownTimeout time.Duration     // Separate deadline for TearDown and ResetAccess, which always run
+	Selector        *ResourceSelector // Resources to test, by name and label; nil tests every resource
+	Tags            []string          // Tag filters to AND with service tags (e.g., ["@CCC.Core.CN01", "@Policy"])
+	Parallel        int               // Maximum number of resources tested concurrently (values < 1 run serially)
+	Inventory       *Inventory        // Resources to test instead of discovering them; nil discovers
+}
+
+// RunStatus is the outcome of running one service
+type RunStatus string
+
+const (
+	RunPassed  RunStatus = "passed"  // All resources passed (or there was nothing to test)
+	RunFailed  RunStatus = "failed"  // Tests ran and at least one resource failed
+	RunErrored RunStatus = "errored" // The service could not be started, so no tests ran
+)
+
+// RunResult is the structured outcome of ServiceRunner.Run
+type RunResult struct {
+	ServiceName string
+	Instance    string
+	Status      RunStatus
+	Stage       string // Where an errored run stopped: factory, service, discovery, features or interrupted
+	Err         error
+	Stats       TestStats
+	Resources   []string // Names of the resources tested
+}
+
+// ExitCode returns the process exit code for this result (0 only when passed)
+func (r RunResult) ExitCode() int {
+	if r.Status == RunPassed {
+		return 0
+	}
+	return 1
 }
 
 // ServiceRunner is the interface for running a suite of compliance tests for a specific service
 type ServiceRunner interface {
-	Run() int
+	// Run executes the tests; a service that fails to start is reported as RunErrored
+	// rather than terminating the process, so the remaining services still run.
+	// Cancelling ctx aborts the run, which is then reported as RunErrored; test-created
+	// resources are still torn down.
+	Run(ctx context.Context) RunResult
+
+	// Plan lists the resources and scenarios Run would execute, without changing cloud state
+	Plan(ctx context.Context) ([]PlanResource, error)
 
 	GetConfig() RunConfig
 }
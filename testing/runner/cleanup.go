@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/factory"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// iamServiceType is the registered service that provisions the test identities of a run
+const iamServiceType = "iam"
+
+// sweepOrder deletes contents before their containers and grants before their principals
+var sweepOrder = []string{
+	journal.KindObject,
+	journal.KindRoleAssignment,
+	journal.KindInstance,
+	journal.KindBucket,
+	journal.KindIAMUser,
+	journal.KindServiceAccount,
+	journal.KindServicePrincipal,
+	journal.KindApplication,
+}
+
+// runCleanup implements `ccc-compliance cleanup`: it deletes resources that a run created
+// but never tore down, e.g. because it crashed or was killed. They are read from the
+// teardown journal and found in each instance's cloud by their run-ID tag, which also
+// catches leaks whose journal is lost or on another machine. Returns the exit code.
+func runCleanup(args []string, testingDir string) int {
+	flags := flag.NewFlagSet("cleanup", flag.ExitOnError)
+	runID := flags.String("run-id", "", "Sweep the resources of this run")
+	olderThan := flags.Duration("older-than", 0, "Sweep the resources of all runs created longer ago than this (e.g. 24h)")
+	envFile := flags.String("env-file", "", "Path to environment.yaml (default: environment.yaml in testing directory)")
+	instanceSpec := flags.String("instance", "all", "Instances to search for tagged resources: an ID, a comma-separated list of IDs, or all")
+	journalOnly := flags.Bool("journal-only", false, "Only sweep resources recorded in the teardown journal, without searching for tagged ones")
+	journalDir := flags.String("journal-dir", "", "Teardown journal directory (default: testing/journal)")
+	dryRun := flags.Bool("dry-run", false, "List the resources that would be deleted without deleting them")
+	timeout := flags.Duration("timeout", 30*time.Minute, "Timeout for the whole cleanup")
+	flags.Parse(args)
+
+	if (*runID == "") == (*olderThan == 0) {
+		log.Println("Error: cleanup needs exactly one of -run-id or -older-than")
+		return 2
+	}
+	if *journalDir == "" {
+		*journalDir = filepath.Join(testingDir, "journal")
+	}
+	envFilePath := *envFile
+	if envFilePath == "" {
+		envFilePath = filepath.Join(testingDir, "environment.yaml")
+	}
+
+	// Tagged resources are dated by their run ID, so one without a readable ID is never old
+	cutoff := time.Now().Add(-*olderThan)
+	selected := func(e journal.Entry) bool {
+		if *runID != "" {
+			return e.RunID == *runID
+		}
+		return !e.Time.IsZero() && e.Time.Before(cutoff)
+	}
+
+	outstanding, err := journal.Outstanding(*journalDir)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return 1
+	}
+	var entries []journal.Entry
+	for _, e := range outstanding {
+		if selected(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	envConfig, err := LoadEnvironment(envFilePath)
+	if err != nil {
+		log.Printf("Error loading environment file: %v", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	factories := make(map[string]factory.Factory)
+	defer func() {
+		for id, f := range factories {
+			factory.EvictFactory(f.GetProvider(), types.InstanceConfig{ID: id})
+		}
+	}()
+
+	findFailed := false
+	if !*journalOnly {
+		instances, err := FindInstances(envConfig, *instanceSpec)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return 1
+		}
+		var found []journal.Entry
+		found, findFailed = findTagged(ctx, instances, factories)
+		var matching []journal.Entry
+		for _, e := range found {
+			if selected(e) {
+				matching = append(matching, e)
+			}
+		}
+		entries = journal.Merge(entries, matching)
+	}
+
+	if len(entries) == 0 {
+		if findFailed {
+			log.Printf("⚠️  No leaked resources found, but some instances could not be searched")
+			return 1
+		}
+		log.Printf("✅ No leaked resources found")
+		return 0
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return sweepRank(entries[i].Kind) < sweepRank(entries[j].Kind)
+	})
+
+	if *dryRun {
+		log.Printf("🔍 %d leaked resource(s) would be deleted:", len(entries))
+		for _, e := range entries {
+			log.Printf("   %s  %-10s %-18s %s  (%s)", e.RunID, e.Instance, e.Kind, e.ID, e.Time.Format(time.RFC3339))
+		}
+		if findFailed {
+			return 1
+		}
+		return 0
+	}
+
+	log.Printf("🧹 Sweeping %d leaked resource(s)...", len(entries))
+	swept, failed := 0, 0
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		err := sweepEntry(ctx, envConfig, factories, e)
+		if err != nil {
+			failed++
+			log.Printf("   ❌ %s %s (%s): %v", e.Kind, e.ID, e.Instance, err)
+			continue
+		}
+		if err := journal.MarkSwept(*journalDir, e); err != nil {
+			log.Printf("   ⚠️  Warning: Deleted %s %s but failed to update the journal: %v", e.Kind, e.ID, err)
+		}
+		swept++
+		log.Printf("   ✅ %s %s (%s)", e.Kind, e.ID, e.Instance)
+	}
+
+	log.Printf("📊 Swept %d, failed %d, not attempted %d", swept, failed, len(entries)-swept-failed)
+	if ctx.Err() != nil {
+		log.Println("🛑 Cleanup was interrupted; run it again to sweep the rest")
+		return 1
+	}
+	if failed > 0 || findFailed {
+		return 1
+	}
+	return 0
+}
+
+// findTagged searches every service of instances that can list its tagged resources,
+// including the IAM service the runner provisions its test identities through.
+// Instances or services that cannot be searched are logged and reported as failed, so
+// the resources found elsewhere are still swept.
+func findTagged(ctx context.Context, instances []types.InstanceConfig, factories map[string]factory.Factory) ([]journal.Entry, bool) {
+	var found []journal.Entry
+	failed := false
+	for i := range instances {
+		inst := &instances[i]
+		cloudFactory, err := factoryFor(ctx, inst, factories)
+		if err != nil {
+			log.Printf("⚠️  Warning: Cannot search %s for tagged resources: %v", inst.ID, err)
+			failed = true
+			continue
+		}
+		for _, serviceType := range searchedServices(inst) {
+			// Services with no client for the provider have nothing to search
+			if _, _, err := generic.ServiceImplementation(serviceType, inst.Properties.Provider); err != nil {
+				continue
+			}
+			service, err := cloudFactory.GetReadOnlyServiceAPI(serviceType)
+			if errors.Is(err, generic.ErrNotApplicable) {
+				continue
+			}
+			if err != nil {
+				log.Printf("⚠️  Warning: Cannot search %s %s for tagged resources: %v", inst.ID, serviceType, err)
+				failed = true
+				continue
+			}
+			finder, ok := service.(journal.Finder)
+			if !ok {
+				continue
+			}
+			log.Printf("🔍 Searching %s %s for resources tagged %s...", inst.ID, serviceType, journal.TagKey)
+			entries, err := finder.FindTagged(ctx)
+			if err != nil {
+				log.Printf("⚠️  Warning: Failed to search %s %s: %v", inst.ID, serviceType, err)
+				failed = true
+				continue
+			}
+			found = append(found, entries...)
+		}
+	}
+	return found, failed
+}
+
+// searchedServices returns the service types of inst to search for tagged resources: its
+// configured services, and the IAM service they provision test identities through, which
+// environment.yaml never lists
+func searchedServices(inst *types.InstanceConfig) []string {
+	serviceTypes := []string{}
+	seen := map[string]bool{}
+	for _, svc := range inst.Services {
+		if !seen[svc.Type] {
+			seen[svc.Type] = true
+			serviceTypes = append(serviceTypes, svc.Type)
+		}
+	}
+	if !seen[iamServiceType] {
+		serviceTypes = append(serviceTypes, iamServiceType)
+	}
+	return serviceTypes
+}
+
+// sweepEntry deletes the resource recorded by e through the service that created it
+func sweepEntry(ctx context.Context, envConfig *types.EnvironmentConfig, factories map[string]factory.Factory, e journal.Entry) error {
+	inst, err := FindInstance(envConfig, e.Instance)
+	if err != nil {
+		return err
+	}
+	cloudFactory, err := factoryFor(ctx, inst, factories)
+	if err != nil {
+		return err
+	}
+
+	service, err := cloudFactory.GetReadOnlyServiceAPI(e.Service)
+	if err != nil {
+		return fmt.Errorf("failed to get service '%s': %w", e.Service, err)
+	}
+	sweeper, ok := service.(journal.Sweeper)
+	if !ok {
+		return fmt.Errorf("service '%s' cannot delete %s resources", e.Service, e.Kind)
+	}
+	return sweeper.Sweep(ctx, e)
+}
+
+// factoryFor returns the factory for inst, creating it on first use
+func factoryFor(ctx context.Context, inst *types.InstanceConfig, factories map[string]factory.Factory) (factory.Factory, error) {
+	if cloudFactory, ok := factories[inst.ID]; ok {
+		return cloudFactory, nil
+	}
+	cloudFactory, err := factory.NewFactory(factory.CloudProvider(inst.Properties.Provider), *inst)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create factory: %w", err)
+	}
+	cloudFactory.SetContext(ctx)
+	factories[inst.ID] = cloudFactory
+	return cloudFactory, nil
+}
+
+// sweepRank returns the position of kind in sweepOrder (unknown kinds last)
+func sweepRank(kind string) int {
+	for i, k := range sweepOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(sweepOrder)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/factory"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/iam"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// TestFindTaggedSearchesIAM checks that cleanup finds a tagged test identity although the
+// instance's services never list the IAM service that provisioned it
+func TestFindTaggedSearchesIAM(t *testing.T) {
+	dir := t.TempDir()
+	const runID = "20250102-150405-abcdef"
+	if err := journal.Configure(dir, runID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { journal.Configure(dir, "") })
+
+	inst := types.InstanceConfig{
+		ID:         "cleanup-local",
+		Properties: types.CloudParams{Provider: "local", Region: "local"},
+		Services:   []types.ServiceConfig{{Type: "object-storage"}},
+	}
+	cloudFactory, err := factory.NewFactory(factory.ProviderLocal, inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { factory.EvictFactory(factory.ProviderLocal, inst) })
+
+	service, err := cloudFactory.GetReadOnlyServiceAPI("iam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.(*iam.LocalIAMService).ProvisionUserWithAccess(context.Background(), "ccc-test-user", "object-storage", "read"); err != nil {
+		t.Fatal(err)
+	}
+
+	found, failed := findTagged(context.Background(), []types.InstanceConfig{inst}, map[string]factory.Factory{})
+	if failed {
+		t.Error("findTagged reported a failed search")
+	}
+	for _, e := range found {
+		if e.Service == "iam" && e.Kind == journal.KindIAMUser && e.ID == "ccc-test-user" && e.RunID == runID {
+			return
+		}
+	}
+	t.Errorf("tagged IAM user not found; found %+v", found)
+}
@@ -2,28 +2,288 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 	"gopkg.in/yaml.v3"
 )
 
-// LoadEnvironment loads and parses an environment.yaml file
+// UnresolvedVariable is a variable referenced in environment.yaml that has no value: it is
+// unset or empty and has no ${VAR:-default}
+type UnresolvedVariable struct {
+	Name     string
+	Instance string // Instance ID, or "" outside any instance
+	Service  string // Service type, or "" at instance level
+	Key      string // Key path of the value, e.g. services.vpc.bad-vpc-id
+	Line     int
+	Required bool   // Written as ${VAR:?message}
+	Message  string // The message of ${VAR:?message}
+}
+
+// String describes the variable and where it is used
+func (v UnresolvedVariable) String() string {
+	desc := fmt.Sprintf("${%s} in %s (line %d)", v.Name, v.Key, v.Line)
+	if v.Message != "" {
+		desc += ": " + v.Message
+	}
+	return desc
+}
+
+// shellNamePattern matches the variable names that are expanded; anything else (e.g. $1)
+// expands to an empty string without being reported
+var shellNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadEnvironment loads and parses an environment.yaml file. Unresolved variables expand
+// to empty strings; use LoadEnvironmentWithUnresolved to list them.
 func LoadEnvironment(path string) (*types.EnvironmentConfig, error) {
+	config, _, err := LoadEnvironmentWithUnresolved(path)
+	return config, err
+}
+
+// LoadEnvironmentWithUnresolved loads and parses an environment.yaml file, expanding ${VAR},
+// ${VAR:-default} and ${VAR:?message} in values, and returns every variable that could
+// not be resolved, with the instance and service it belongs to
+func LoadEnvironmentWithUnresolved(path string) (*types.EnvironmentConfig, []UnresolvedVariable, error) {
+	doc, unresolved, err := parseEnvironment(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var config types.EnvironmentConfig
+	if err := doc.Decode(&config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse environment file %s: %w", path, err)
+	}
+	return &config, unresolved, nil
+}
+
+// parseEnvironment reads environment.yaml into a YAML node tree with its variables expanded.
+// Values are expanded after parsing, so each unresolved variable can be attributed to its
+// instance and service, and commented-out lines are left alone.
+func parseEnvironment(path string) (*yaml.Node, []UnresolvedVariable, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read environment file %s: %w", path, err)
+		return nil, nil, fmt.Errorf("failed to read environment file %s: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse environment file %s: %w", path, err)
+	}
+	var unresolved []UnresolvedVariable
+	if len(doc.Content) > 0 {
+		expandEnvironmentNode(doc.Content[0], &unresolved)
 	}
-	
-	// Native parameter substitution to handle variables like ${INSTANCE_ID}
-	expandedData := []byte(os.ExpandEnv(string(data)))
+	return &doc, unresolved, nil
+}
 
-	var config types.EnvironmentConfig
-	if err := yaml.Unmarshal(expandedData, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse environment file %s: %w", path, err)
+// expandEnvironmentNode expands the values of the environment.yaml root mapping, tracking
+// the instance and service each value belongs to
+func expandEnvironmentNode(root *yaml.Node, unresolved *[]UnresolvedVariable) {
+	if root.Kind != yaml.MappingNode {
+		expandNode(root, UnresolvedVariable{}, unresolved)
+		return
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "instances" || value.Kind != yaml.SequenceNode {
+			expandNode(value, UnresolvedVariable{Key: key.Value}, unresolved)
+			continue
+		}
+		for _, inst := range value.Content {
+			if inst.Kind != yaml.MappingNode {
+				expandNode(inst, UnresolvedVariable{Key: "instances"}, unresolved)
+				continue
+			}
+			// Expand the ID first so the instance's other variables are attributed to it
+			var id string
+			if idNode := mappingValue(inst, "id"); idNode != nil {
+				expandNode(idNode, UnresolvedVariable{Key: "id"}, unresolved)
+				id = idNode.Value
+			}
+			for j := 0; j+1 < len(inst.Content); j += 2 {
+				instKey, instValue := inst.Content[j], inst.Content[j+1]
+				if instKey.Value == "id" {
+					continue
+				}
+				if instKey.Value != "services" || instValue.Kind != yaml.SequenceNode {
+					expandNode(instValue, UnresolvedVariable{Instance: id, Key: instKey.Value}, unresolved)
+					continue
+				}
+				for _, svc := range instValue.Content {
+					serviceType := ""
+					if typeNode := mappingValue(svc, "type"); typeNode != nil {
+						serviceType = typeNode.Value
+					}
+					expandNode(svc, UnresolvedVariable{Instance: id, Service: serviceType, Key: "services." + serviceType}, unresolved)
+				}
+			}
+		}
 	}
-	return &config, nil
+}
+
+// expandNode expands the scalars under node in place. loc carries the instance, service
+// and key path recorded for unresolved variables.
+func expandNode(node *yaml.Node, loc UnresolvedVariable, unresolved *[]UnresolvedVariable) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "$") {
+			return
+		}
+		var mapping func(expr string) string
+		mapping = func(expr string) string {
+			value, v, ok := expandVariable(expr, func(operand string) string {
+				return expandString(operand, mapping)
+			})
+			if !ok {
+				v.Instance, v.Service, v.Key, v.Line = loc.Instance, loc.Service, loc.Key, node.Line
+				*unresolved = append(*unresolved, v)
+			}
+			return value
+		}
+		node.Value = expandString(node.Value, mapping)
+		// Let plain scalars resolve their type from the expanded value (e.g. true, 443)
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			child := loc
+			if child.Key != "" {
+				child.Key += "."
+			}
+			child.Key += node.Content[i].Value
+			expandNode(node.Content[i+1], child, unresolved)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := loc
+			child.Key = fmt.Sprintf("%s[%d]", loc.Key, i)
+			expandNode(item, child, unresolved)
+		}
+	}
+}
+
+// expandString expands $NAME and ${expr} in s through mapping, like os.Expand, except that
+// $$ is a literal $, braces nest (so a default can itself reference a variable, e.g.
+// ${A:-${B}}) and an unterminated ${ is left as written
+func expandString(s string, mapping func(expr string) string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch {
+		case s[i+1] == '$':
+			b.WriteByte('$')
+			i++
+		case s[i+1] == '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(mapping(s[i+2 : end]))
+			i = end
+		default:
+			// As in the shell, $1 to $9 are single-character names
+			j := i + 1
+			for j < len(s) && isNameByte(s[j]) && !(j > i+1 && '0' <= s[i+1] && s[i+1] <= '9') {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte('$')
+				continue
+			}
+			b.WriteString(mapping(s[i+1 : j]))
+			i = j - 1
+		}
+	}
+	return b.String()
+}
+
+// closingBrace returns the index of the } closing a ${ whose contents start at start, or
+// -1 if it is not closed
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isNameByte reports whether c can appear in a $NAME reference
+func isNameByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// expandVariable resolves the inside of ${...}: NAME, NAME:-default or NAME:?message.
+// A default is expanded with expand when it is used. ok is false when the variable is
+// unset or empty and has no default.
+func expandVariable(expr string, expand func(string) string) (value string, v UnresolvedVariable, ok bool) {
+	name, operand, op := expr, "", ""
+	if i := strings.Index(expr, ":"); i >= 0 && i+1 < len(expr) && (expr[i+1] == '-' || expr[i+1] == '?') {
+		name, op, operand = expr[:i], expr[i:i+2], expr[i+2:]
+	}
+	if !shellNamePattern.MatchString(name) {
+		return "", v, true
+	}
+	if value := os.Getenv(name); value != "" {
+		return value, v, true
+	}
+	switch op {
+	case ":-":
+		return expand(operand), v, true
+	case ":?":
+		if operand == "" {
+			operand = "required variable is not set"
+		}
+		return "", UnresolvedVariable{Name: name, Required: true, Message: operand}, false
+	}
+	return "", UnresolvedVariable{Name: name}, false
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// UnresolvedFor returns the unresolved variables that affect a run of the given instances:
+// those outside any instance, at instance level, and in the services that will run
+// (every service when service is "")
+func UnresolvedFor(unresolved []UnresolvedVariable, instances []types.InstanceConfig, service string) []UnresolvedVariable {
+	running := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		running[inst.ID] = true
+	}
+	var affecting []UnresolvedVariable
+	for _, v := range unresolved {
+		if v.Instance != "" && !running[v.Instance] {
+			continue
+		}
+		if service != "" && v.Service != "" && v.Service != service {
+			continue
+		}
+		affecting = append(affecting, v)
+	}
+	return affecting
 }
 
 // FindInstance finds an instance by ID - convenience wrapper on EnvironmentConfig
@@ -39,3 +299,76 @@ func FindInstance(config *types.EnvironmentConfig, id string) (*types.InstanceCo
 	}
 	return nil, fmt.Errorf("instance '%s' not found (available: %s)", id, strings.Join(ids, ", "))
 }
+
+// FindInstances resolves an -instance value to one or more instances. The value may be a
+// single ID, a comma-separated list of IDs, or "all" for every instance in the file.
+func FindInstances(config *types.EnvironmentConfig, spec string) ([]types.InstanceConfig, error) {
+	if strings.TrimSpace(spec) == "all" {
+		if len(config.Instances) == 0 {
+			return nil, fmt.Errorf("no instances defined in environment file")
+		}
+		return config.Instances, nil
+	}
+
+	var instances []types.InstanceConfig
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(spec, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		inst, err := FindInstance(config, id)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, *inst)
+	}
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("no instance IDs given in '%s'", spec)
+	}
+	return instances, nil
+}
+
+// checkUnresolved returns why a run cannot start with the given unresolved variables:
+// with strict, any of them stops it; otherwise only ${VAR:?message} ones do
+func checkUnresolved(unresolved []UnresolvedVariable, strict bool) error {
+	if len(unresolved) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("-strict-env: set the variables above or give them a ${VAR:-default}")
+	}
+	required := 0
+	for _, v := range unresolved {
+		if v.Required {
+			required++
+		}
+	}
+	if required > 0 {
+		return fmt.Errorf("%d required variable(s) are not set", required)
+	}
+	return nil
+}
+
+// logUnresolved prints the unresolved variables grouped by instance
+func logUnresolved(unresolved []UnresolvedVariable) {
+	var instances []string
+	byInstance := make(map[string][]UnresolvedVariable)
+	for _, v := range unresolved {
+		if _, ok := byInstance[v.Instance]; !ok {
+			instances = append(instances, v.Instance)
+		}
+		byInstance[v.Instance] = append(byInstance[v.Instance], v)
+	}
+	for _, id := range instances {
+		if id == "" {
+			log.Printf("   Outside any instance:")
+		} else {
+			log.Printf("   Instance %s:", id)
+		}
+		for _, v := range byInstance[id] {
+			log.Printf("     - %s", v)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpandScalar(t *testing.T) {
+	t.Setenv("CCC_SET", "value")
+	t.Setenv("CCC_EMPTY", "")
+	t.Setenv("CCC_UNSET", "")
+	os.Unsetenv("CCC_UNSET") // t.Setenv restores it after the test
+
+	tests := []struct {
+		name       string
+		in         string
+		want       string
+		unresolved []UnresolvedVariable
+	}{
+		{"plain text", "no variables", "no variables", nil},
+		{"braced", "${CCC_SET}", "value", nil},
+		{"bare", "pre-$CCC_SET-post", "pre-value-post", nil},
+		{"unset", "a${CCC_UNSET}b", "ab", []UnresolvedVariable{{Name: "CCC_UNSET"}}},
+		{"empty counts as unset", "${CCC_EMPTY}", "", []UnresolvedVariable{{Name: "CCC_EMPTY"}}},
+		{"default unused", "${CCC_SET:-fallback}", "value", nil},
+		{"default for unset", "${CCC_UNSET:-fallback}", "fallback", nil},
+		{"default for empty", "${CCC_EMPTY:-fallback}", "fallback", nil},
+		{"empty default", "${CCC_UNSET:-}", "", nil},
+		{"required set", "${CCC_SET:?set CCC_SET}", "value", nil},
+		{"required unset", "${CCC_UNSET:?set CCC_UNSET}", "",
+			[]UnresolvedVariable{{Name: "CCC_UNSET", Required: true, Message: "set CCC_UNSET"}}},
+		{"required without message", "${CCC_UNSET:?}", "",
+			[]UnresolvedVariable{{Name: "CCC_UNSET", Required: true, Message: "required variable is not set"}}},
+		{"escaped dollar", "cost: $$5 and $${CCC_SET}", "cost: $5 and ${CCC_SET}", nil},
+		{"trailing dollar", "cost$", "cost$", nil},
+		{"lone dollar", "a $ b", "a $ b", nil},
+		{"positional is dropped", "$1x", "x", nil},
+		{"nested default", "${CCC_UNSET:-${CCC_SET}}", "value", nil},
+		{"nested default unset", "${CCC_UNSET:-x${CCC_UNSET}y}", "xy", []UnresolvedVariable{{Name: "CCC_UNSET"}}},
+		{"unterminated", "a${CCC_SET", "a${CCC_SET", nil},
+		{"unterminated after variable", "${CCC_SET}-${CCC_SET", "value-${CCC_SET", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &yaml.Node{Kind: yaml.ScalarNode, Value: tt.in, Line: 7}
+			var unresolved []UnresolvedVariable
+			expandNode(node, UnresolvedVariable{Instance: "inst", Key: "k"}, &unresolved)
+			if node.Value != tt.want {
+				t.Errorf("expanded %q to %q, want %q", tt.in, node.Value, tt.want)
+			}
+			if len(unresolved) != len(tt.unresolved) {
+				t.Fatalf("unresolved = %+v, want %+v", unresolved, tt.unresolved)
+			}
+			for i, want := range tt.unresolved {
+				want.Instance, want.Key, want.Line = "inst", "k", 7
+				if unresolved[i] != want {
+					t.Errorf("unresolved[%d] = %+v, want %+v", i, unresolved[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEnvironmentExpandsNestedNodes(t *testing.T) {
+	t.Setenv("CCC_REGION", "eu-west-1")
+	t.Setenv("CCC_PORT", "8443")
+	t.Setenv("CCC_UNSET", "")
+	os.Unsetenv("CCC_UNSET") // t.Setenv restores it after the test
+
+	path := filepath.Join(t.TempDir(), "environment.yaml")
+	err := os.WriteFile(path, []byte(`instances:
+  - id: main
+    properties:
+      provider: aws
+      region: ${CCC_REGION}
+    rules:
+      permitted-regions: ["${CCC_REGION}", "${CCC_UNSET:-us-east-1}"]
+    services:
+      - type: object-storage
+        resources:
+          - ${CCC_UNSET}
+        port: ${CCC_PORT}
+        nested:
+          key: ${CCC_UNSET:?give a key}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, unresolved, err := LoadEnvironmentWithUnresolved(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := config.Instances[0]
+	if inst.Properties.Region != "eu-west-1" {
+		t.Errorf("region = %q, want eu-west-1", inst.Properties.Region)
+	}
+	regions, _ := inst.Rules["permitted-regions"].([]interface{})
+	if len(regions) != 2 || regions[0] != "eu-west-1" || regions[1] != "us-east-1" {
+		t.Errorf("permitted-regions = %v, want [eu-west-1 us-east-1]", inst.Rules["permitted-regions"])
+	}
+	if port := inst.Services[0].Properties["port"]; port != 8443 {
+		t.Errorf("port = %#v, want the integer 8443", port)
+	}
+
+	want := []UnresolvedVariable{
+		{Name: "CCC_UNSET", Instance: "main", Service: "object-storage", Key: "services.object-storage.resources[0]", Line: 11},
+		{Name: "CCC_UNSET", Instance: "main", Service: "object-storage", Key: "services.object-storage.nested.key", Line: 14,
+			Required: true, Message: "give a key"},
+	}
+	if len(unresolved) != len(want) {
+		t.Fatalf("unresolved = %+v, want %+v", unresolved, want)
+	}
+	for i := range want {
+		if unresolved[i] != want[i] {
+			t.Errorf("unresolved[%d] = %+v, want %+v", i, unresolved[i], want[i])
+		}
+	}
+}
+
+func TestCheckUnresolved(t *testing.T) {
+	optional := UnresolvedVariable{Name: "OPTIONAL"}
+	required := UnresolvedVariable{Name: "REQUIRED", Required: true, Message: "set it"}
+
+	tests := []struct {
+		name       string
+		unresolved []UnresolvedVariable
+		strict     bool
+		wantErr    bool
+	}{
+		{"none", nil, false, false},
+		{"none strict", nil, true, false},
+		{"optional", []UnresolvedVariable{optional}, false, false},
+		{"optional strict", []UnresolvedVariable{optional}, true, true},
+		{"required", []UnresolvedVariable{optional, required}, false, true},
+		{"required strict", []UnresolvedVariable{required}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUnresolved(tt.unresolved, tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkUnresolved() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Names of the evidence archive and of the checksum manifest and signature inside it
+const (
+	evidenceArchiveName = "evidence.tar.gz"
+	checksumsName       = "SHA256SUMS"
+	signatureName       = "SHA256SUMS.sig"
+)
+
+// loadSigningKey reads an Ed25519 private key from a PKCS#8 PEM file, as written by
+// `openssl genpkey -algorithm ed25519`
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+	signingKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return signingKey, nil
+}
+
+// loadVerifyKey reads an Ed25519 public key from a PKIX PEM file, as written by
+// `openssl pkey -in key.pem -pubout`
+func loadVerifyKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key file %s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+// writeEvidenceBundle packages every file in runDir (reports, OCSF, attachments, policy
+// results and the run manifest) into runDir/evidence.tar.gz, together with a SHA256SUMS
+// manifest of those files and its detached Ed25519 signature. Returns the archive path.
+func writeEvidenceBundle(runDir string, key ed25519.PrivateKey) (string, error) {
+	var paths []string
+	err := filepath.WalkDir(runDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && path != filepath.Join(runDir, evidenceArchiveName) {
+			rel, err := filepath.Rel(runDir, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list run directory: %w", err)
+	}
+	sort.Strings(paths)
+
+	archivePath := filepath.Join(runDir, evidenceArchiveName)
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create evidence archive: %w", err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	var sums bytes.Buffer
+	for _, rel := range paths {
+		data, err := os.ReadFile(filepath.Join(runDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&sums, "%s  %s\n", hex.EncodeToString(sum[:]), rel)
+		if err := writeTarFile(tw, rel, data); err != nil {
+			return "", err
+		}
+	}
+
+	signature := ed25519.Sign(key, sums.Bytes())
+	if err := writeTarFile(tw, checksumsName, sums.Bytes()); err != nil {
+		return "", err
+	}
+	if err := writeTarFile(tw, signatureName, []byte(base64.StdEncoding.EncodeToString(signature)+"\n")); err != nil {
+		return "", err
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write evidence archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return "", fmt.Errorf("failed to write evidence archive: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to write evidence archive: %w", err)
+	}
+	return archivePath, nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now().UTC(),
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to evidence archive: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to evidence archive: %w", name, err)
+	}
+	return nil
+}
+
+// verifyEvidenceBundle checks the SHA256SUMS signature in an evidence archive against
+// publicKey, then that every file in the archive is listed with a matching hash and that
+// every listed file is present. Returns the number of files verified.
+func verifyEvidenceBundle(archivePath string, publicKey ed25519.PublicKey) (int, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open evidence archive: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read evidence archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	hashes := make(map[string]string)
+	var sums, signature []byte
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read evidence archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return 0, fmt.Errorf("unexpected entry %s in evidence archive", header.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s from evidence archive: %w", header.Name, err)
+		}
+		switch header.Name {
+		case checksumsName:
+			sums = data
+		case signatureName:
+			signature, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+			if err != nil {
+				return 0, fmt.Errorf("malformed %s: %w", signatureName, err)
+			}
+		default:
+			if _, dup := hashes[header.Name]; dup {
+				return 0, fmt.Errorf("duplicate entry %s in evidence archive", header.Name)
+			}
+			sum := sha256.Sum256(data)
+			hashes[header.Name] = hex.EncodeToString(sum[:])
+		}
+	}
+	if sums == nil || signature == nil {
+		return 0, fmt.Errorf("evidence archive has no %s or %s", checksumsName, signatureName)
+	}
+	if !ed25519.Verify(publicKey, sums, signature) {
+		return 0, fmt.Errorf("signature of %s does not match the public key", checksumsName)
+	}
+
+	listed := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		want, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return 0, fmt.Errorf("malformed line in %s: %q", checksumsName, scanner.Text())
+		}
+		listed[name] = true
+		got, present := hashes[name]
+		switch {
+		case !present:
+			return 0, fmt.Errorf("%s is listed in %s but missing from the archive", name, checksumsName)
+		case got != want:
+			return 0, fmt.Errorf("%s has been modified (sha256 %s, signed %s)", name, got, want)
+		}
+	}
+	for name := range hashes {
+		if !listed[name] {
+			return 0, fmt.Errorf("%s is in the archive but not in %s", name, checksumsName)
+		}
+	}
+	return len(hashes), nil
+}
+
+// runVerify implements `ccc-compliance verify`: it checks an evidence archive written
+// with -sign-with against the signer's public key. Returns the exit code.
+func runVerify(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	publicKeyFile := flags.String("public-key", "", "Ed25519 public key (PEM) of the key the archive was signed with")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: ccc-compliance verify -public-key <pem> <evidence.tar.gz>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *publicKeyFile == "" || flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	publicKey, err := loadVerifyKey(*publicKeyFile)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return 2
+	}
+
+	archivePath := flags.Arg(0)
+	count, err := verifyEvidenceBundle(archivePath, publicKey)
+	if err != nil {
+		log.Printf("❌ %s failed verification: %v", archivePath, err)
+		return 1
+	}
+	log.Printf("✅ %s verified: %d file(s) match the signed %s", archivePath, count, checksumsName)
+	return 0
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRunDir writes a small run directory and returns its path
+func writeRunDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"manifest.json":                     `{"run":"20250102-150405"}`,
+		"bucket.ocsf.json":                  `[]`,
+		"bucket.html":                       `<html></html>`,
+		"attachments/bucket/policy.json":    `{"Statement":[]}`,
+		"attachments/bucket/audit-log.json": `[]`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// rewriteArchive rewrites the archive at path, passing each entry through edit (which drops
+// it by returning nil) and appending the extra entries
+func rewriteArchive(t *testing.T, path string, edit func(name string, data []byte) []byte, extra map[string][]byte) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type entry struct {
+		name string
+		data []byte
+	}
+	var entries []entry
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data = edit(header.Name, data); data != nil {
+			entries = append(entries, entry{header.Name, data})
+		}
+	}
+	f.Close()
+	for name, data := range extra {
+		entries = append(entries, entry{name, data})
+	}
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, e := range entries {
+		if err := writeTarFile(tw, e.name, e.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func unchanged(name string, data []byte) []byte { return data }
+
+func TestEvidenceBundleRoundTrip(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(t *testing.T, archive string)
+		key     ed25519.PublicKey
+		wantErr string
+	}{
+		{name: "signed bundle verifies", tamper: func(*testing.T, string) {}, key: publicKey},
+		{name: "modified file", key: publicKey, wantErr: "bucket.html has been modified",
+			tamper: func(t *testing.T, archive string) {
+				rewriteArchive(t, archive, func(name string, data []byte) []byte {
+					if name == "bucket.html" {
+						return []byte("<html>all passed</html>")
+					}
+					return data
+				}, nil)
+			}},
+		{name: "extra file", key: publicKey, wantErr: "extra.json is in the archive but not in SHA256SUMS",
+			tamper: func(t *testing.T, archive string) {
+				rewriteArchive(t, archive, unchanged, map[string][]byte{"extra.json": []byte("{}")})
+			}},
+		{name: "removed file", key: publicKey, wantErr: "manifest.json is listed in SHA256SUMS but missing",
+			tamper: func(t *testing.T, archive string) {
+				rewriteArchive(t, archive, func(name string, data []byte) []byte {
+					if name == "manifest.json" {
+						return nil
+					}
+					return data
+				}, nil)
+			}},
+		{name: "modified SHA256SUMS", key: publicKey, wantErr: "signature of SHA256SUMS does not match",
+			tamper: func(t *testing.T, archive string) {
+				rewriteArchive(t, archive, func(name string, data []byte) []byte {
+					if name == checksumsName {
+						return append(data, []byte(strings.Repeat("0", 64)+"  extra.json\n")...)
+					}
+					return data
+				}, map[string][]byte{"extra.json": []byte("{}")})
+			}},
+		{name: "wrong public key", tamper: func(*testing.T, string) {}, key: otherKey,
+			wantErr: "signature of SHA256SUMS does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive, err := writeEvidenceBundle(writeRunDir(t), privateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(t, archive)
+
+			count, err := verifyEvidenceBundle(archive, tt.key)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("verify: %v", err)
+				}
+				if count != 5 {
+					t.Errorf("verified %d files, want 5", count)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verify error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadKeysRejectMalformedAndNonEd25519(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPrivate, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPublic, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPrivateDER, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublicDER, err := x509.MarshalPKIXPublicKey(edPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notPEM := write("not-pem", []byte("not a key"))
+	garbage := write("garbage.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}))
+	ecPrivatePath := write("ec.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecPrivate}))
+	ecPublicPath := write("ec.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecPublic}))
+	edPrivatePath := write("ed.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPrivateDER}))
+	edPublicPath := write("ed.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: edPublicDER}))
+
+	for _, path := range []string{notPEM, garbage, ecPrivatePath, edPublicPath, filepath.Join(dir, "missing.pem")} {
+		if _, err := loadSigningKey(path); err == nil {
+			t.Errorf("loadSigningKey(%s) succeeded, want an error", filepath.Base(path))
+		}
+	}
+	for _, path := range []string{notPEM, garbage, ecPublicPath, edPrivatePath, filepath.Join(dir, "missing.pem")} {
+		if _, err := loadVerifyKey(path); err == nil {
+			t.Errorf("loadVerifyKey(%s) succeeded, want an error", filepath.Base(path))
+		}
+	}
+	if _, err := loadSigningKey(edPrivatePath); err != nil {
+		t.Errorf("loadSigningKey(ed.pem): %v", err)
+	}
+	if _, err := loadVerifyKey(edPublicPath); err != nil {
+		t.Errorf("loadVerifyKey(ed.pub): %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/language/reporters"
+)
+
+// runIDPattern matches run directory names, as produced by journal.NewRunID
+var runIDPattern = regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`)
+
+// RunTotals counts service runners by outcome, and the resources they tested
+type RunTotals struct {
+	Runners         int `json:"runners"`
+	Passed          int `json:"passed"`
+	Failed          int `json:"failed"`
+	Errored         int `json:"errored"`
+	ResourcesPassed int `json:"resourcesPassed"`
+	ResourcesFailed int `json:"resourcesFailed"`
+	ResourcesWaived int `json:"resourcesWaived"` // Resources whose only failures are waived
+}
+
+// ErroredService is a service that could not be tested, as recorded in the manifest
+type ErroredService struct {
+	Instance string `json:"instance"`
+	Service  string `json:"service"`
+	Stage    string `json:"stage"`
+	Error    string `json:"error"`
+}
+
+// RunManifest describes one run; it is written to manifest.json in the run directory
+type RunManifest struct {
+	RunID          string           `json:"runId"`
+	StartedAt      time.Time        `json:"startedAt"`
+	FinishedAt     time.Time        `json:"finishedAt"`
+	Status         string           `json:"status"` // passed, failed, errored, interrupted or empty
+	Instances      []string         `json:"instances"`
+	Service        string           `json:"service,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	ResourceFilter string           `json:"resourceFilter,omitempty"`
+	Selector       string           `json:"selector,omitempty"`
+	Totals         RunTotals        `json:"totals"`
+	Errored        []ErroredService `json:"errored,omitempty"`
+
+	Baseline   *reporters.BaselineCounts `json:"baseline,omitempty"` // Set when run with -baseline
+	Provenance *Provenance               `json:"provenance,omitempty"`
+}
+
+// addResult counts a service runner's result into the manifest
+func (m *RunManifest) addResult(result RunResult) {
+	m.Totals.Runners++
+	switch result.Status {
+	case RunPassed:
+		m.Totals.Passed++
+	case RunFailed:
+		m.Totals.Failed++
+	case RunErrored:
+		m.Totals.Errored++
+		errText := ""
+		if result.Err != nil {
+			errText = result.Err.Error()
+		}
+		m.Errored = append(m.Errored, ErroredService{
+			Instance: result.Instance,
+			Service:  result.ServiceName,
+			Stage:    result.Stage,
+			Error:    errText,
+		})
+	}
+	m.Totals.ResourcesPassed += result.Stats.Passed
+	m.Totals.ResourcesFailed += result.Stats.Failed
+	m.Totals.ResourcesWaived += result.Stats.Waived
+}
+
+// writeRunManifest writes manifest.json to the run directory
+func writeRunManifest(runDir string, manifest RunManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, "manifest.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write run manifest: %w", err)
+	}
+	return nil
+}
+
+// updateLatest points <baseDir>/latest at the run directory
+func updateLatest(baseDir, runID string) error {
+	link := filepath.Join(baseDir, "latest")
+	if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace latest link: %w", err)
+	}
+	if err := os.Symlink(runID, link); err != nil {
+		return fmt.Errorf("failed to create latest link: %w", err)
+	}
+	return nil
+}
+
+// listRunDirs returns the names of the run directories in baseDir, oldest first
+func listRunDirs(baseDir string) ([]string, error) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	var runs []string
+	for _, entry := range entries {
+		if entry.IsDir() && runIDPattern.MatchString(entry.Name()) {
+			runs = append(runs, entry.Name())
+		}
+	}
+	sort.Strings(runs)
+	return runs, nil
+}
+
+// pruneRuns deletes the oldest run directories so that at most keep remain (keep < 1
+// keeps every run)
+func pruneRuns(baseDir string, keep int) error {
+	if keep < 1 {
+		return nil
+	}
+	runs, err := listRunDirs(baseDir)
+	if err != nil {
+		return err
+	}
+	for len(runs) > keep {
+		log.Printf("🗑️  Removing old run %s (keeping the last %d)", runs[0], keep)
+		if err := os.RemoveAll(filepath.Join(baseDir, runs[0])); err != nil {
+			return fmt.Errorf("failed to remove run %s: %w", runs[0], err)
+		}
+		runs = runs[1:]
+	}
+	return nil
+}
+
+// writeHistory rebuilds history.json and history.html in baseDir from the manifests of
+// the runs still on disk, newest first. Runs without a manifest (e.g. killed) are listed
+// as incomplete.
+func writeHistory(baseDir string) error {
+	runs, err := listRunDirs(baseDir)
+	if err != nil {
+		return err
+	}
+
+	history := make([]RunManifest, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		manifest := RunManifest{RunID: runs[i], Status: "incomplete"}
+		data, err := os.ReadFile(filepath.Join(baseDir, runs[i], "manifest.json"))
+		if err == nil {
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				log.Printf("⚠️  Warning: Failed to parse manifest of run %s: %v", runs[i], err)
+			}
+		}
+		history = append(history, manifest)
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run history: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "history.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write run history: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, "history.html"), []byte(generateHistoryHTML(history)), 0644); err != nil {
+		return fmt.Errorf("failed to write run history: %w", err)
+	}
+	return nil
+}
+
+// generateHistoryHTML renders the run history as a table linking to each run's summary
+func generateHistoryHTML(history []RunManifest) string {
+	var rows strings.Builder
+	for _, m := range history {
+		started := ""
+		if !m.StartedAt.IsZero() {
+			started = m.StartedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(&rows, `<tr class="%s"><td><a href="%s/summary.html">%s</a></td><td>%s</td><td>%s</td><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d / %d / %d</td></tr>
+`,
+			html.EscapeString(m.Status), m.RunID, m.RunID, started,
+			html.EscapeString(strings.Join(m.Instances, ", ")), strings.ToUpper(html.EscapeString(m.Status)),
+			m.Totals.Passed, m.Totals.Failed, m.Totals.Errored,
+			m.Totals.ResourcesPassed, m.Totals.ResourcesWaived, m.Totals.ResourcesFailed)
+	}
+
+	return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>CCC Compliance Run History</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 2em; color: #333; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; }
+th { background: #f5f5f5; }
+tr.passed td:nth-child(4) { color: #2e7d32; font-weight: bold; }
+tr.failed td:nth-child(4), tr.errored td:nth-child(4) { color: #c62828; font-weight: bold; }
+tr.interrupted td:nth-child(4), tr.incomplete td:nth-child(4) { color: #ef6c00; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>CCC Compliance Run History</h1>
+<table>
+<tr><th>Run</th><th>Started</th><th>Instances</th><th>Status</th><th>Runners Passed</th><th>Runners Failed</th><th>Runners Errored</th><th>Resources Passed / Waived / Failed</th></tr>
+` + rows.String() + `</table>
+</body>
+</html>
+`
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestPruneRuns(t *testing.T) {
+	runs := []string{
+		"20250101-090000-aaaaaa",
+		"20250102-090000-bbbbbb",
+		"20250102-100000-cccccc",
+		"20250103-090000-dddddd",
+	}
+	// Entries pruning must leave alone, whatever the retention count
+	others := []string{"latest", "history.json", "reports", "20250101-000000", "20240101-000000-ZZZZZZ"}
+
+	tests := []struct {
+		keep int
+		want []string // run directories left
+	}{
+		{0, runs},
+		{-1, runs},
+		{1, runs[3:]},
+		{2, runs[2:]},
+		{4, runs},
+		{10, runs},
+	}
+	for _, tt := range tests {
+		baseDir := t.TempDir()
+		for _, run := range runs {
+			if err := os.MkdirAll(filepath.Join(baseDir, run, "attachments"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, dir := range []string{"reports", "20250101-000000", "20240101-000000-ZZZZZZ"} {
+			if err := os.Mkdir(filepath.Join(baseDir, dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(baseDir, "history.json"), []byte("[]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := updateLatest(baseDir, runs[len(runs)-1]); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := pruneRuns(baseDir, tt.keep); err != nil {
+			t.Fatalf("keep %d: %v", tt.keep, err)
+		}
+
+		got, err := listRunDirs(baseDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("keep %d left runs %v, want %v", tt.keep, got, tt.want)
+		}
+		for _, name := range others {
+			if _, err := os.Lstat(filepath.Join(baseDir, name)); err != nil {
+				t.Errorf("keep %d removed %s: %v", tt.keep, name, err)
+			}
+		}
+		entries, err := os.ReadDir(baseDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != len(tt.want)+len(others) {
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			sort.Strings(names)
+			t.Errorf("keep %d left %v", tt.keep, names)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+	"gopkg.in/yaml.v3"
+)
+
+// InventoryResource is one resource listed in an --inventory file. Catalog types and tag
+// filter default to those of the registered service.
+type InventoryResource struct {
+	Instance            string   `yaml:"instance"` // Instance ID; empty applies to every instance being run
+	Service             string   `yaml:"service"`  // Service type, e.g. "object-storage"
+	ResourceName        string   `yaml:"resource-name"`
+	UID                 string   `yaml:"uid"`
+	HostName            string   `yaml:"host-name"`
+	PortNumber          string   `yaml:"port-number"`
+	Protocol            string   `yaml:"protocol"`
+	ProviderServiceType string   `yaml:"provider-service-type"`
+	CatalogTypes        []string `yaml:"catalog-types"`
+	TagFilter           []string `yaml:"tag-filter"`
+	Labels              []string `yaml:"labels"`
+	ReportFile          string   `yaml:"report-file"`
+	ReportTitle         string   `yaml:"report-title"`
+}
+
+// Inventory is the list of resources to test instead of discovering them
+type Inventory struct {
+	Path      string
+	Resources []InventoryResource
+}
+
+// LoadInventory reads an inventory file, a YAML or JSON list of resources, and checks that
+// every resource names a registered service and a resource name
+func LoadInventory(path string) (*Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory file: %w", err)
+	}
+	// JSON is valid YAML, so one decoder reads both formats. Unknown keys are rejected, so a
+	// misspelled key is not silently dropped.
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var resources []InventoryResource
+	if err := decoder.Decode(&resources); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse inventory file %s: %w", path, err)
+	}
+
+	var errs []error
+	for i, res := range resources {
+		name := fmt.Sprintf("resource %d", i+1)
+		if res.ResourceName != "" {
+			name += fmt.Sprintf(" (%s)", res.ResourceName)
+		}
+		if res.ResourceName == "" {
+			errs = append(errs, fmt.Errorf("%s: resource-name is required", name))
+		}
+		if res.Service == "" {
+			errs = append(errs, fmt.Errorf("%s: service is required", name))
+		} else if _, ok := generic.LookupService(res.Service); !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", name, unknownValueMessage("service type", res.Service, generic.ServiceNames())))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid inventory file %s:\n%w", path, errors.Join(errs...))
+	}
+	return &Inventory{Path: path, Resources: resources}, nil
+}
+
+// CheckInstances reports resources whose instance is not defined in environment.yaml, since
+// they would silently never be tested
+func (inv *Inventory) CheckInstances(config *types.EnvironmentConfig) error {
+	known := make(map[string]bool, len(config.Instances))
+	for _, inst := range config.Instances {
+		known[inst.ID] = true
+	}
+	var errs []error
+	for _, res := range inv.Resources {
+		if res.Instance != "" && !known[res.Instance] {
+			errs = append(errs, fmt.Errorf("resource %s: unknown instance '%s'", res.ResourceName, res.Instance))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid inventory file %s:\n%w", inv.Path, errors.Join(errs...))
+	}
+	return nil
+}
+
+// For returns the resources of a service on an instance as TestParams, in file order
+func (inv *Inventory) For(inst types.InstanceConfig, serviceType string) []types.TestParams {
+	resources := []types.TestParams{}
+	for _, res := range inv.Resources {
+		if res.Service != serviceType || (res.Instance != "" && res.Instance != inst.ID) {
+			continue
+		}
+		title := res.ReportTitle
+		if title == "" {
+			title = res.ResourceName
+			if res.HostName != "" && res.PortNumber != "" {
+				title = fmt.Sprintf("%s:%s", res.HostName, res.PortNumber)
+			}
+		}
+		reportFile := res.ReportFile
+		if reportFile == "" && res.PortNumber != "" {
+			reportFile = fmt.Sprintf("%s-port", res.ResourceName)
+		}
+		resources = append(resources, types.TestParams{
+			ResourceName:        res.ResourceName,
+			UID:                 res.UID,
+			HostName:            res.HostName,
+			PortNumber:          res.PortNumber,
+			Protocol:            res.Protocol,
+			ProviderServiceType: res.ProviderServiceType,
+			ServiceType:         serviceType,
+			CatalogTypes:        res.CatalogTypes,
+			TagFilter:           res.TagFilter,
+			Labels:              res.Labels,
+			ReportFile:          reportFile,
+			ReportTitle:         title,
+			Instance:            inst,
+		})
+	}
+	return resources
+}
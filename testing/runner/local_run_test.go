@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestLocalInstancePasses runs the runner against the main-local instance, whose services
+// are in-memory fakes that behave compliantly, so every scenario it selects must pass
+func TestLocalInstancePasses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs every feature against the local instance")
+	}
+
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "ccc-compliance")
+	build := exec.Command("go", "build", "-o", binary, ".")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+
+	run := exec.Command(binary,
+		"-instance", "main-local",
+		"-env-file", filepath.Join("..", "environment.yaml"),
+		"-output", filepath.Join(dir, "output"),
+		"-journal-dir", filepath.Join(dir, "journal"),
+	)
+	out, err := run.CombinedOutput()
+	if err != nil {
+		t.Fatalf("main-local run failed: %v\n%s", err, out)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "output", "latest", "manifest.json")); err != nil {
+		t.Errorf("run wrote no manifest: %v", err)
+	}
+}
@@ -1,38 +1,79 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/journal"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic/recorder"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/language/reporters"
 	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
 )
 
 var (
-	instance       = flag.String("instance", "", "Instance ID from environment.yaml (e.g. main-aws, main-azure)")
-	envFile        = flag.String("env-file", "", "Path to environment.yaml (default: environment.yaml in testing directory)")
-	service        = flag.String("service", "", "Service type to test (object-storage, logging, block-storage, relational-database, iam, load-balancer, security-group, vpc). If not specified, tests all services defined in the instance.")
-	outputDir      = flag.String("output", "", "Output directory for test reports (default: testing/output)")
-	timeout        = flag.Duration("timeout", 30*time.Minute, "Timeout for all tests")
-	resourceFilter = flag.String("resource", "", "Filter tests to a specific resource name")
-	tags           = flag.String("tags", "", "Space-separated tag filters ANDed with service tags (e.g., '@CCC.Core.CN01 @Policy')")
+	instance        = flag.String("instance", "", "Instance ID(s) from environment.yaml: one ID, a comma-separated list (e.g. main-aws,main-azure), or 'all'")
+	envFile         = flag.String("env-file", "", "Path to environment.yaml (default: environment.yaml in testing directory)")
+	strictEnv       = flag.Bool("strict-env", false, "Refuse to start when environment.yaml references unset variables for the instances and services being run")
+	service         = flag.String("service", "", "Service type to test ("+strings.Join(generic.ServiceNames(), ", ")+"). If not specified, tests all services defined in the instance.")
+	outputDir       = flag.String("output", "", "Output directory for test reports; each run is written to its own <run-id> sub-directory (default: testing/output)")
+	keepRuns        = flag.Int("keep-runs", 0, "Number of past runs to keep in the output directory; older runs are deleted (0 keeps every run)")
+	timeout         = flag.Duration("timeout", 30*time.Minute, "Timeout for all tests")
+	teardownTimeout = flag.Duration("teardown-timeout", 10*time.Minute, "Separate deadline for tearing down test-created resources and resetting elevated access, which runs even after a timeout or Ctrl-C")
+	resourceFilter  = flag.String("resource", "", "Filter tests to resources by name: an exact name, a glob (e.g. 'prod-*') or a /regex/")
+	selector        = flag.String("selector", "", "Filter tests to resources by label, e.g. 'env=prod,data-class in (confidential,restricted)'")
+	inventoryFile   = flag.String("inventory", "", "YAML or JSON list of the resources to test; skips resource discovery (and the default resources it may create)")
+	tags            = flag.String("tags", "", "Space-separated tag filters ANDed with service tags (e.g., '@CCC.Core.CN01 @Policy')")
+	plan            = flag.Bool("plan", false, "Dry run: discover resources read-only and list the scenarios that would run, without executing them")
+	parallel        = flag.Int("parallel", 1, "Maximum number of resources to test concurrently within each service")
+	recordDir       = flag.String("record", "", "Record sanitized provider SDK HTTP traffic to cassettes in this directory")
+	replayDir       = flag.String("replay", "", "Replay provider SDK HTTP traffic from cassettes in this directory instead of calling the cloud")
+	baseline        = flag.String("baseline", "", "Compare findings with a previous run: its combined.ocsf.json or run directory (e.g. output/latest)")
+	regressionsOnly = flag.Bool("regressions-only", false, "With -baseline, exit non-zero only for new failures, not for findings that were already failing")
+	waivers         = flag.String("waivers", "", "Waivers file accepting known failures until they expire (default: testing/waivers.yaml, if present)")
+	signWith        = flag.String("sign-with", "", "Ed25519 private key (PKCS#8 PEM) to sign an evidence archive of the run directory with; check it with `verify`")
+	journalDir      = flag.String("journal-dir", "", "Directory for the teardown journal of created resources, swept by `cleanup` (default: testing/journal)")
 )
 
 func main() {
-	flag.Parse()
-
 	// Resolve the testing directory relative to this source file
 	_, filename, _, _ := runtime.Caller(0)
 	runnerDir := filepath.Dir(filename)
 	testingDir := filepath.Dir(runnerDir)
 
+	// `ccc-compliance cleanup ...` sweeps resources leaked by earlier runs
+	if len(os.Args) > 1 && os.Args[1] == "cleanup" {
+		os.Exit(runCleanup(os.Args[2:], testingDir))
+	}
+
+	// `ccc-compliance verify ...` checks a signed evidence archive
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(runVerify(os.Args[2:]))
+	}
+
+	// `ccc-compliance services` lists the registered service types
+	if len(os.Args) > 1 && os.Args[1] == "services" {
+		os.Exit(runServices())
+	}
+
+	// `ccc-compliance validate-env ...` checks environment.yaml without calling any cloud API
+	if len(os.Args) > 1 && os.Args[1] == "validate-env" {
+		os.Exit(runValidateEnv(os.Args[2:], testingDir))
+	}
+
+	flag.Parse()
+
 	// Set default output directory
 	if *outputDir == "" {
 		*outputDir = filepath.Join(testingDir, "output")
@@ -48,133 +89,448 @@ func main() {
 	if *instance == "" {
 		log.Fatal("Error: -instance flag is required (e.g. main-aws, main-azure, main-gcp)")
 	}
+	if *parallel < 1 {
+		log.Fatalf("Error: -parallel must be at least 1 (got %d)", *parallel)
+	}
+	if *regressionsOnly && *baseline == "" {
+		log.Fatal("Error: -regressions-only requires -baseline")
+	}
+
+	// Load the baseline before this run replaces output/latest
+	var baselineFindings []reporters.OCSFFinding
+	if *baseline != "" && !*plan {
+		var err error
+		if baselineFindings, err = reporters.LoadOCSFFindings(*baseline); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Printf("📏 Baseline: %s (%d findings)", *baseline, len(baselineFindings))
+	}
+
+	// Load the waivers: failures they cover are reported as waived until the waiver expires
+	waiversPath := *waivers
+	if waiversPath == "" {
+		if _, err := os.Stat(filepath.Join(testingDir, "waivers.yaml")); err == nil {
+			waiversPath = filepath.Join(testingDir, "waivers.yaml")
+		}
+	}
+	if waiversPath != "" && !*plan {
+		loaded, err := reporters.LoadWaivers(waiversPath)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Printf("⚑ Waivers: %s (%d waiver(s))", waiversPath, len(loaded))
+		for _, w := range loaded {
+			if w.Expired(time.Now()) {
+				log.Printf("   ⚠️  Waiver %s for %s expired on %s; its failures are reported as failures", w.Ticket, w.Control, w.Expires)
+			}
+		}
+	}
+
+	// Load the signing key up front, so a bad key fails before any test runs
+	var signingKey ed25519.PrivateKey
+	if *signWith != "" && !*plan {
+		var err error
+		if signingKey, err = loadSigningKey(*signWith); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
+	// Configure the HTTP recorder for the provider SDK clients
+	switch {
+	case *recordDir != "" && *replayDir != "":
+		log.Fatal("Error: -record and -replay cannot be used together")
+	case *recordDir != "":
+		if err := recorder.Configure(recorder.ModeRecord, *recordDir); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Printf("📼 Recording provider HTTP traffic to %s", *recordDir)
+	case *replayDir != "":
+		if err := recorder.Configure(recorder.ModeReplay, *replayDir); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Printf("📼 Replaying provider HTTP traffic from %s", *replayDir)
+	}
+
+	// The run ID names the run's output directory and tags the resources it creates
+	runID := journal.NewRunID()
+	startedAt := time.Now().UTC()
+
+	// Journal every resource the run creates, tagged with the run ID, so anything a crashed
+	// or killed run leaks can be swept with `ccc-compliance cleanup -run-id <id>`. Plan
+	// and replay modes create nothing in the cloud.
+	if !*plan && *replayDir == "" {
+		if *journalDir == "" {
+			*journalDir = filepath.Join(testingDir, "journal")
+		}
+		if err := journal.Configure(*journalDir, runID); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Printf("📓 Run ID: %s (journal: %s)", runID, *journalDir)
+	}
 
 	// Load types.yaml
-	envConfig, err := LoadEnvironment(envFilePath)
+	envConfig, allUnresolved, err := LoadEnvironmentWithUnresolved(envFilePath)
 	if err != nil {
 		log.Fatalf("Error loading environment file: %v", err)
 	}
 
-	// Find the requested instance
-	inst, err := FindInstance(envConfig, *instance)
+	// Resolve the requested instance(s): a single ID, a comma-separated list, or "all"
+	instances, err := FindInstances(envConfig, *instance)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	// Unset variables would otherwise expand to empty strings and only fail deep inside a
+	// scenario. ${VAR:?message} variables are always required; -strict-env requires all.
+	if unresolved := UnresolvedFor(allUnresolved, instances, *service); len(unresolved) > 0 {
+		log.Printf("⚠️  %d variable(s) in %s are not set:", len(unresolved), envFilePath)
+		logUnresolved(unresolved)
+		if err := checkUnresolved(unresolved, *strictEnv); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Println()
+	}
+
+	// Decode every service block and the rules of the instances being run, so a missing
+	// required key or a value of the wrong type fails here rather than in a scenario
+	for _, inst := range instances {
+		if err := inst.Validate(*service); err != nil {
+			log.Fatalf("Error: %v\n(check the file with: ccc-compliance validate-env -instance %s)", err, inst.ID)
+		}
+	}
+	multiInstance := len(instances) > 1
+
+	// Resources are chosen by name and label. Discovery fills the labels from the tags or
+	// labels of each resource; an inventory lists them.
+	resourceSelector, err := NewResourceSelector(*resourceFilter, *selector)
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 
+	// A static inventory replaces discovery, so no resource is listed or created to find
+	// what to test
+	var inventory *Inventory
+	if *inventoryFile != "" {
+		inventory, err = LoadInventory(*inventoryFile)
+		if err == nil {
+			err = inventory.CheckInstances(envConfig)
+		}
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		log.Printf("📋 Inventory: %d resource(s) from %s", len(inventory.Resources), *inventoryFile)
+	}
+
 	log.Printf("🚀 Starting CCC CFI Compliance Tests")
-	log.Printf("   Instance: %s (%s)", inst.ID, inst.Properties.Provider)
+	for _, inst := range instances {
+		log.Printf("   Instance: %s (%s)", inst.ID, inst.Properties.Provider)
+	}
 	log.Println()
 
-	// Prepare output directory
-	log.Printf("🧹 Cleaning output directory: %s", *outputDir)
-	if err := os.RemoveAll(*outputDir); err != nil && !os.IsNotExist(err) {
-		log.Printf("⚠️  Warning: Failed to clean output directory: %v", err)
+	// Prepare output directory. Each run writes to its own <run-id> sub-directory, so the
+	// evidence of earlier runs is kept. Plan mode only reads from the cloud and writes
+	// plan.json to the output directory itself.
+	runDir := *outputDir
+	if !*plan {
+		runDir = filepath.Join(*outputDir, runID)
 	}
-	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+	if err := os.MkdirAll(runDir, 0755); err != nil {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
-	log.Printf("✅ Output directory ready")
-	log.Println()
+	if !*plan {
+		log.Printf("✅ Output directory ready: %s", runDir)
+		log.Println()
+	}
 
-	// Determine which services to run from the instance definition
-	servicesToRun := inst.Services
+	// Validate the requested service type
 	if *service != "" {
-		// Validate and filter to the requested service type
-		validService := false
-		for _, st := range types.ServiceTypes {
-			if st == *service {
-				validService = true
-				break
+		if _, ok := generic.LookupService(*service); !ok {
+			log.Fatalf("Error: invalid service '%s'. Valid services are: %s", *service, strings.Join(generic.ServiceNames(), ", "))
+		}
+		log.Printf("   Service: %s", *service)
+		log.Println()
+	}
+
+	// Build one runner per service for each instance. With several instances, each
+	// instance writes its reports to its own sub-directory of the output directory.
+	var runs []instanceRun
+	for _, inst := range instances {
+		instOutputDir := runDir
+		if multiInstance {
+			instOutputDir = filepath.Join(runDir, sanitizeFilename(inst.ID))
+			if err := os.MkdirAll(instOutputDir, 0755); err != nil {
+				log.Fatalf("Failed to create output directory: %v", err)
 			}
 		}
-		if !validService {
-			log.Fatalf("Error: invalid service '%s'. Valid services are: %s", *service, strings.Join(types.ServiceTypes, ", "))
+
+		// Determine which services to run from the instance definition
+		servicesToRun := inst.Services
+		if *service != "" {
+			var filtered []types.ServiceConfig
+			for _, svc := range inst.Services {
+				if svc.Type == *service {
+					filtered = append(filtered, svc)
+				}
+			}
+			if len(filtered) == 0 {
+				if !multiInstance {
+					log.Fatalf("Error: service '%s' is not defined in instance '%s'", *service, inst.ID)
+				}
+				log.Printf("⏭️  Skipping instance '%s': service '%s' is not defined", inst.ID, *service)
+				continue
+			}
+			servicesToRun = filtered
 		}
-		var filtered []types.ServiceConfig
-		for _, svc := range inst.Services {
-			if svc.Type == *service {
-				filtered = append(filtered, svc)
+
+		// With an inventory, only services that list resources for this instance are run
+		if inventory != nil {
+			var listed []types.ServiceConfig
+			for _, svc := range servicesToRun {
+				if len(inventory.For(inst, svc.Type)) > 0 {
+					listed = append(listed, svc)
+				} else {
+					log.Printf("⏭️  Skipping service '%s' on instance '%s': no resources in the inventory", svc.Type, inst.ID)
+				}
 			}
+			servicesToRun = listed
 		}
-		if len(filtered) == 0 {
-			log.Fatalf("Error: service '%s' is not defined in instance '%s'", *service, inst.ID)
+
+		run := instanceRun{instance: inst, outputDir: instOutputDir}
+		for i := range servicesToRun {
+			run.runners = append(run.runners, NewBasicServiceRunner(RunConfig{
+				ServiceName:     servicesToRun[i].Type,
+				Instance:        inst,
+				OutputDir:       instOutputDir,
+				Timeout:         *timeout,
+				TeardownTimeout: *teardownTimeout,
+				Selector:        resourceSelector,
+				Tags:            parseTags(*tags),
+				Parallel:        *parallel,
+				Inventory:       inventory,
+			}))
 		}
-		servicesToRun = filtered
-		log.Printf("   Service: %s", *service)
-		log.Println()
+		runs = append(runs, run)
 	}
 
-	// Build one runner per service
-	var runners []ServiceRunner
-	for i := range servicesToRun {
-		runners = append(runners, NewBasicServiceRunner(RunConfig{
-			ServiceName:    servicesToRun[i].Type,
-			Instance:       *inst,
-			OutputDir:      *outputDir,
-			Timeout:        *timeout,
-			ResourceFilter: *resourceFilter,
-			Tags:           parseTags(*tags),
-		}))
+	// Ctrl-C / SIGTERM cancels the run context, aborting in-flight SDK calls; each runner
+	// then still tears down under its own -teardown-timeout deadline. The remaining runners
+	// are recorded as interrupted and the reports are still written.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Println("\n🛑 Interrupted: cancelling the run and tearing down test-created resources (press Ctrl-C again to force quit)")
+		// Restore default signal handling, so a second signal terminates immediately
+		stop()
+	}()
+
+	if *plan {
+		exitCode := 0
+		for _, run := range runs {
+			if code := runPlan(ctx, run.instance, run.runners, run.outputDir); code != 0 {
+				exitCode = code
+			}
+		}
+		os.Exit(exitCode)
 	}
 
-	log.Printf("📋 Running %d service runner(s)", len(runners))
+	// Record what produced this run's results; the version also stamps every OCSF finding
+	log.Println("🧾 Recording run provenance (runner version, tool versions, feature hashes)...")
+	provenance := collectProvenance(testingDir, instances, parseTags(*tags))
+	reporters.SetProductVersion(provenance.productVersion())
+
+	totalRunners := 0
+	for _, run := range runs {
+		totalRunners += len(run.runners)
+	}
+	log.Printf("📋 Running %d service runner(s) across %d instance(s)", totalRunners, len(runs))
 	log.Println()
 
-	// Run all service runners
+	// Run all service runners, one instance at a time. A service that fails to start is
+	// recorded as errored and the remaining services keep running.
 	totalFailed := 0
 	totalPassed := 0
+	var errored []RunResult
+	manifest := RunManifest{
+		RunID:          runID,
+		StartedAt:      startedAt,
+		Service:        *service,
+		Tags:           parseTags(*tags),
+		ResourceFilter: *resourceFilter,
+		Selector:       *selector,
+	}
+	for _, run := range runs {
+		manifest.Instances = append(manifest.Instances, run.instance.ID)
+	}
 
-	for i, runner := range runners {
-		log.Printf("🔧 Running service runner %d/%d", i+1, len(runners))
-		exitCode := runner.Run()
+	for _, run := range runs {
+		if multiInstance {
+			log.Println("\n" + strings.Repeat("=", 60))
+			log.Printf("☁️  Instance: %s (%s)", run.instance.ID, run.instance.Properties.Provider)
+			log.Println(strings.Repeat("=", 60))
+		}
 
-		if exitCode == 0 {
-			totalPassed++
-		} else {
-			totalFailed++
+		for i, runner := range run.runners {
+			log.Printf("🔧 Running service runner %d/%d", i+1, len(run.runners))
+			result := runner.Run(ctx)
+			manifest.addResult(result)
+
+			switch result.Status {
+			case RunPassed:
+				totalPassed++
+			case RunFailed:
+				totalFailed++
+			case RunErrored:
+				errored = append(errored, result)
+			}
+		}
+
+		if multiInstance {
+			// Per-instance combined OCSF and summary in the instance's sub-directory
+			log.Printf("\n🔗 Combining OCSF output files for %s...", run.instance.ID)
+			if err := combineOCSFFiles(run.outputDir); err != nil {
+				log.Printf("⚠️  Warning: Failed to combine OCSF files: %v", err)
+			}
+			if err := reporters.GenerateInstanceSummaryReport(run.outputDir, run.instance.ID); err != nil {
+				log.Printf("⚠️  Warning: Failed to generate summary report for %s: %v", run.instance.ID, err)
+			}
 		}
 	}
 
 	// Combine all OCSF files into a single file
 	log.Println("\n🔗 Combining OCSF output files...")
-	if err := combineOCSFFiles(*outputDir); err != nil {
+	if multiInstance {
+		err = combineInstanceOCSFFiles(runDir, runs)
+	} else {
+		err = combineOCSFFiles(runDir)
+	}
+	if err != nil {
 		log.Printf("⚠️  Warning: Failed to combine OCSF files: %v", err)
 	} else {
-		log.Printf("   ✅ Combined OCSF file created: %s", filepath.Join(*outputDir, "combined.ocsf.json"))
+		log.Printf("   ✅ Combined OCSF file created: %s", filepath.Join(runDir, "combined.ocsf.json"))
 	}
 
-	// Generate summary report (summary.html + console)
+	// Generate summary report (summary.html + console); with several instances this is
+	// the cross-instance view with one column per instance
 	log.Println("\n📋 Generating summary report...")
-	if err := reporters.GenerateSummaryReport(*outputDir); err != nil {
+	if err := reporters.GenerateSummaryReport(runDir); err != nil {
 		log.Printf("⚠️  Warning: Failed to generate summary report: %v", err)
 	} else {
-		log.Printf("   ✅ Summary report created: %s", filepath.Join(*outputDir, "summary.html"))
+		log.Printf("   ✅ Summary report created: %s", filepath.Join(runDir, "summary.html"))
+	}
+
+	// Classify this run's findings against the baseline
+	var diff *reporters.BaselineDiff
+	if *baseline != "" {
+		log.Println("\n📏 Comparing with baseline...")
+		current, err := reporters.LoadOCSFFindings(runDir)
+		if err != nil {
+			log.Printf("⚠️  Warning: %v", err)
+		} else {
+			d := reporters.CompareBaseline(*baseline, baselineFindings, current)
+			diff = &d
+			manifest.Baseline = &d.Counts
+			if err := reporters.WriteBaselineDiff(runDir, d); err != nil {
+				log.Printf("⚠️  Warning: Failed to write baseline diff: %v", err)
+			} else {
+				log.Printf("   ✅ Baseline diff created: %s", filepath.Join(runDir, "baseline-diff.html"))
+			}
+		}
 	}
 
 	// Print summary
 	log.Println("\n" + strings.Repeat("=", 60))
 	log.Printf("📊 Overall Summary")
-	log.Printf("   Total Runners: %d", len(runners))
+	log.Printf("   Total Runners: %d", totalRunners)
 	log.Printf("   Passed: %d", totalPassed)
 	log.Printf("   Failed: %d", totalFailed)
+	log.Printf("   Errored: %d", len(errored))
+	for _, result := range errored {
+		log.Printf("     ❌ %s/%s (%s): %v", result.Instance, result.ServiceName, result.Stage, result.Err)
+	}
 	log.Println(strings.Repeat("=", 60))
 
-	if totalFailed > 0 {
-		log.Println("❌ Some runners had test failures")
-		os.Exit(1)
-	} else if len(runners) == 0 {
-		log.Println("⚠️  No runners were executed")
-		os.Exit(1)
+	var message string
+	exitCode := 1
+	if ctx.Err() != nil {
+		manifest.Status, message = "interrupted", "🛑 Run was interrupted; results are incomplete"
+	} else if len(errored) > 0 {
+		manifest.Status, message = "errored", "❌ Some services could not be tested"
+	} else if totalFailed > 0 {
+		manifest.Status, message = "failed", "❌ Some runners had test failures"
+	} else if totalRunners == 0 {
+		manifest.Status, message = "empty", "⚠️  No runners were executed"
 	} else if totalPassed == 0 {
-		log.Println("⚠️  No runners executed any tests")
-		os.Exit(0)
+		manifest.Status, message, exitCode = "empty", "⚠️  No runners executed any tests", 0
+	} else {
+		manifest.Status, message, exitCode = "passed", "✅ All runners passed", 0
+	}
+
+	// With -regressions-only, findings that were already failing in the baseline do not
+	// fail the run; an interrupted run or a failed comparison still does
+	if *regressionsOnly && ctx.Err() == nil {
+		message, exitCode = regressionsOutcome(diff)
+	}
+
+	manifest.FinishedAt = time.Now().UTC()
+	provenance.addPolicyHashes(testingDir)
+	manifest.Provenance = provenance
+	recordRun(*outputDir, manifest, *keepRuns)
+
+	// Package the run directory into a signed archive, so the evidence is tamper-evident
+	if signingKey != nil {
+		if archivePath, err := writeEvidenceBundle(runDir, signingKey); err != nil {
+			log.Printf("❌ Failed to write signed evidence archive: %v", err)
+			exitCode = 1
+		} else {
+			log.Printf("🔏 Signed evidence archive: %s", archivePath)
+		}
+	}
+
+	log.Println(message)
+	os.Exit(exitCode)
+}
+
+// regressionsOutcome returns the message and exit code of a -regressions-only run: only new
+// failures since the baseline fail it, or a comparison that could not be made (nil diff)
+func regressionsOutcome(diff *reporters.BaselineDiff) (string, int) {
+	switch {
+	case diff == nil:
+		return "❌ Could not compare with the baseline", 1
+	case diff.Counts.NewFailures > 0:
+		return fmt.Sprintf("❌ %d new failure(s) since the baseline", diff.Counts.NewFailures), 1
+	}
+	return "✅ No new failures since the baseline", 0
+}
+
+// recordRun writes the run manifest, points latest at the run, applies the retention
+// count and rebuilds the run history. Failures are warnings: the reports are already written.
+func recordRun(baseDir string, manifest RunManifest, keep int) {
+	runDir := filepath.Join(baseDir, manifest.RunID)
+	if err := writeRunManifest(runDir, manifest); err != nil {
+		log.Printf("⚠️  Warning: %v", err)
+	}
+	if err := updateLatest(baseDir, manifest.RunID); err != nil {
+		log.Printf("⚠️  Warning: %v", err)
+	}
+	if err := pruneRuns(baseDir, keep); err != nil {
+		log.Printf("⚠️  Warning: %v", err)
+	}
+	if err := writeHistory(baseDir); err != nil {
+		log.Printf("⚠️  Warning: %v", err)
 	} else {
-		log.Println("✅ All runners passed")
-		os.Exit(0)
+		log.Printf("📚 Run history: %s", filepath.Join(baseDir, "history.html"))
 	}
 }
 
-// combineOCSFFiles combines all *ocsf.json files in the output directory into a single combined_ocsf.json file
+// instanceRun is the set of service runners for one instance and where its reports go
+type instanceRun struct {
+	instance  types.InstanceConfig
+	outputDir string
+	runners   []ServiceRunner
+}
+
+// combineOCSFFiles combines all *ocsf.json files in the output directory into a single combined.ocsf.json file
 func combineOCSFFiles(outputDir string) error {
 	pattern := filepath.Join(outputDir, "*ocsf.json")
 	files, err := filepath.Glob(pattern)
@@ -182,6 +538,33 @@ func combineOCSFFiles(outputDir string) error {
 		return fmt.Errorf("failed to find OCSF files: %w", err)
 	}
 
+	// Never fold a previous combined file back into itself
+	combinedPath := filepath.Join(outputDir, "combined.ocsf.json")
+	var resourceFiles []string
+	for _, file := range files {
+		if file != combinedPath {
+			resourceFiles = append(resourceFiles, file)
+		}
+	}
+
+	return writeCombinedOCSF(resourceFiles, combinedPath)
+}
+
+// combineInstanceOCSFFiles combines each instance's combined.ocsf.json into a single
+// cross-instance combined.ocsf.json at the top of the output directory
+func combineInstanceOCSFFiles(outputDir string, runs []instanceRun) error {
+	var files []string
+	for _, run := range runs {
+		path := filepath.Join(run.outputDir, "combined.ocsf.json")
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return writeCombinedOCSF(files, filepath.Join(outputDir, "combined.ocsf.json"))
+}
+
+// writeCombinedOCSF concatenates the findings in files into a single JSON array at combinedPath
+func writeCombinedOCSF(files []string, combinedPath string) error {
 	if len(files) == 0 {
 		log.Printf("   No OCSF files found to combine")
 		return nil
@@ -205,7 +588,6 @@ func combineOCSFFiles(outputDir string) error {
 		log.Printf("   Added %d item(s) from %s", len(items), filepath.Base(file))
 	}
 
-	combinedPath := filepath.Join(outputDir, "combined.ocsf.json")
 	combinedData, err := json.MarshalIndent(combined, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal combined data: %w", err)
@@ -219,6 +601,54 @@ func combineOCSFFiles(outputDir string) error {
 	return nil
 }
 
+// runPlan prints the dry-run matrix for all runners and writes plan.json; returns the exit code
+func runPlan(ctx context.Context, inst types.InstanceConfig, runners []ServiceRunner, outputDir string) int {
+	result := Plan{
+		Instance:  inst.ID,
+		Provider:  inst.Properties.Provider,
+		Resources: []PlanResource{},
+	}
+	for _, runner := range runners {
+		resources, err := runner.Plan(ctx)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", runner.GetConfig().ServiceName, err))
+			continue
+		}
+		result.Resources = append(result.Resources, resources...)
+	}
+
+	printPlan(result)
+
+	path, err := writePlanJSON(result, outputDir)
+	if err != nil {
+		log.Printf("⚠️  Warning: %v", err)
+		return 1
+	}
+	log.Printf("   ✅ Plan written to: %s", path)
+
+	if len(result.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runServices implements `ccc-compliance services`: it lists each registered service type
+// with its catalog types, default tag filter and providers. Returns the exit code.
+func runServices() int {
+	log.Printf("📋 Registered services:")
+	for _, def := range generic.RegisteredServices() {
+		log.Printf("   %s", def.Name)
+		if len(def.CatalogTypes) > 0 {
+			log.Printf("      Catalog types: %s", strings.Join(def.CatalogTypes, ", "))
+		}
+		if len(def.TagFilter) > 0 {
+			log.Printf("      Tag filter:    %s", tagExpression(def.TagFilter))
+		}
+		log.Printf("      Providers:     %s", strings.Join(def.ProviderNames(), ", "))
+	}
+	return 0
+}
+
 // parseTags parses a space-separated tags string into a slice of tags
 func parseTags(tagsStr string) []string {
 	if tagsStr == "" {
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/language/reporters"
+)
+
+func TestRegressionsOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		diff *reporters.BaselineDiff
+		want int
+	}{
+		{"comparison failed", nil, 1},
+		{"new failures", &reporters.BaselineDiff{Counts: reporters.BaselineCounts{NewFailures: 1, StillFailing: 3}}, 1},
+		{"only still failing", &reporters.BaselineDiff{Counts: reporters.BaselineCounts{StillFailing: 3, Fixed: 1}}, 0},
+		{"nothing failing", &reporters.BaselineDiff{Counts: reporters.BaselineCounts{Unchanged: 5, NotInCurrent: 2}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if message, got := regressionsOutcome(tt.diff); got != tt.want {
+				t.Errorf("exit code = %d (%s), want %d", got, message, tt.want)
+			}
+		})
+	}
+}
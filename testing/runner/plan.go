@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin/go/v26"
+	"github.com/cucumber/godog"
+	messages "github.com/cucumber/messages/go/v21"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/factory"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// PlanScenario is a scenario that would run against a resource
+type PlanScenario struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// PlanFeature groups the selected scenarios of a single feature file
+type PlanFeature struct {
+	Name      string         `json:"name"`
+	URI       string         `json:"uri"`
+	Scenarios []PlanScenario `json:"scenarios"`
+}
+
+// PlanResource is one discovered resource and the scenarios its tag expression selects
+type PlanResource struct {
+	Service       string        `json:"service"`
+	ResourceName  string        `json:"resourceName"`
+	UID           string        `json:"uid"`
+	Labels        []string      `json:"labels,omitempty"`
+	ReportFile    string        `json:"reportFile"`
+	TagExpression string        `json:"tagExpression"`
+	ScenarioCount int           `json:"scenarioCount"`
+	Features      []PlanFeature `json:"features"`
+}
+
+// Plan is the dry-run output for an instance
+type Plan struct {
+	Instance  string         `json:"instance"`
+	Provider  string         `json:"provider"`
+	Resources []PlanResource `json:"resources"`
+	Errors    []string       `json:"errors,omitempty"`
+}
+
+// planResources returns the resources of the inventory, or discovers them read-only
+func (r *BasicServiceRunner) planResources(ctx context.Context) ([]types.TestParams, error) {
+	config := r.Config
+	if config.Inventory != nil {
+		log.Printf("📋 Using %s resources from inventory %s", config.ServiceName, config.Inventory.Path)
+		return config.Inventory.For(config.Instance, config.ServiceName), nil
+	}
+
+	provider := factory.CloudProvider(config.Instance.Properties.Provider)
+	cloudFactory, err := factory.NewFactory(provider, config.Instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create factory: %w", err)
+	}
+	// Nothing was elevated or created, so there is nothing to tear down; evicting is enough
+	// to keep the next service for this instance from sharing its clients
+	defer factory.EvictFactory(provider, config.Instance)
+	cloudFactory.SetContext(ctx)
+
+	service, err := cloudFactory.GetReadOnlyServiceAPI(config.ServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service '%s': %w", config.ServiceName, err)
+	}
+
+	log.Printf("🔍 Discovering %s resources (read-only)...", config.ServiceName)
+	resources, err := service.DiscoverTestableResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+	return resources, nil
+}
+
+// Plan discovers resources (or takes them from the inventory) without provisioning,
+// elevating access or tearing down, and returns the scenarios each resource would run
+// (implements ServiceRunner interface)
+func (r *BasicServiceRunner) Plan(ctx context.Context) ([]PlanResource, error) {
+	config := r.Config
+
+	resources, err := r.planResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	featuresPaths, err := findFeaturesPaths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read features directory: %w", err)
+	}
+	featureNames, err := parseFeatureNames(featuresPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var planned []PlanResource
+	for _, resource := range resources {
+		if !r.matchesResourceFilter(resource) {
+			continue
+		}
+		resource = r.applyTagFilter(resource)
+		expr := tagExpression(resource.TagFilter)
+
+		pr := PlanResource{
+			Service:       config.ServiceName,
+			ResourceName:  resource.ResourceName,
+			Labels:        resource.Labels,
+			UID:           resource.UID,
+			ReportFile:    sanitizeFilename(reportBaseName(resource)),
+			TagExpression: expr,
+			Features:      []PlanFeature{},
+		}
+		for _, ft := range selectScenarios(featuresPaths, expr) {
+			if name, ok := featureNames[ft.URI]; ok {
+				ft.Name = name
+			}
+			pr.Features = append(pr.Features, ft)
+			pr.ScenarioCount += len(ft.Scenarios)
+		}
+		planned = append(planned, pr)
+	}
+
+	return planned, nil
+}
+
+// reportBaseName returns the name used for a resource's report files
+func reportBaseName(params types.TestParams) string {
+	if params.ReportFile != "" {
+		return params.ReportFile
+	}
+	return params.ResourceName
+}
+
+// selectScenarios returns the scenarios godog selects with the tag expression, grouped by
+// feature in the order they would run. godog is run with a hook that skips every scenario
+// before its first step, so its own tag filtering decides the plan and nothing is executed.
+func selectScenarios(featuresPaths []string, expr string) []PlanFeature {
+	var features []PlanFeature
+	index := make(map[string]int)
+
+	godog.TestSuite{
+		Name: "plan",
+		ScenarioInitializer: func(sc *godog.ScenarioContext) {
+			sc.Before(func(ctx context.Context, scenario *godog.Scenario) (context.Context, error) {
+				i, ok := index[scenario.Uri]
+				if !ok {
+					i = len(features)
+					index[scenario.Uri] = i
+					features = append(features, PlanFeature{Name: scenario.Uri, URI: scenario.Uri})
+				}
+				tagNames := make([]string, 0, len(scenario.Tags))
+				for _, tag := range scenario.Tags {
+					tagNames = append(tagNames, tag.Name)
+				}
+				features[i].Scenarios = append(features[i].Scenarios, PlanScenario{Name: scenario.Name, Tags: tagNames})
+				return ctx, godog.ErrSkip
+			})
+		},
+		Options: &godog.Options{
+			Format:      "progress",
+			Output:      io.Discard,
+			Paths:       featuresPaths,
+			Tags:        expr,
+			Concurrency: 1,
+			NoColors:    true,
+		},
+	}.Run()
+
+	return features
+}
+
+// parseFeatureNames returns the name of every .feature file under the given paths, by path
+func parseFeatureNames(featuresPaths []string) (map[string]string, error) {
+	newID := (&messages.Incrementing{}).NewId
+	names := make(map[string]string)
+
+	for _, root := range featuresPaths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".feature") {
+				return nil
+			}
+			if _, seen := names[path]; seen {
+				return nil
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			doc, err := gherkin.ParseGherkinDocument(bytes.NewReader(data), newID)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			if doc.Feature != nil {
+				names[path] = strings.TrimSpace(doc.Feature.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load features from %s: %w", root, err)
+		}
+	}
+	return names, nil
+}
+
+// printPlan writes the resource × feature × scenario matrix to the log
+func printPlan(plan Plan) {
+	log.Println("\n" + strings.Repeat("=", 60))
+	log.Printf("🗺️  Test Plan: %s (%s)", plan.Instance, plan.Provider)
+	log.Println(strings.Repeat("=", 60))
+
+	totalScenarios := 0
+	for _, pr := range plan.Resources {
+		log.Printf("\n📦 [%s] %s (%s)", pr.Service, pr.ResourceName, pr.ReportFile)
+		if len(pr.Labels) > 0 {
+			log.Printf("   Labels: %s", strings.Join(pr.Labels, ", "))
+		}
+		log.Printf("   Tag Filter: %s", pr.TagExpression)
+		if len(pr.Features) == 0 {
+			log.Printf("   ⚠️  No scenarios selected")
+			continue
+		}
+		for _, ft := range pr.Features {
+			log.Printf("   📄 %s (%d)", ft.Name, len(ft.Scenarios))
+			for _, sc := range ft.Scenarios {
+				log.Printf("      - %s", sc.Name)
+			}
+		}
+		totalScenarios += pr.ScenarioCount
+	}
+
+	for _, e := range plan.Errors {
+		log.Printf("\n❌ %s", e)
+	}
+
+	log.Println("\n" + strings.Repeat("=", 60))
+	log.Printf("   Resources: %d", len(plan.Resources))
+	log.Printf("   Scenarios: %d", totalScenarios)
+	log.Println(strings.Repeat("=", 60))
+}
+
+// writePlanJSON writes the plan to plan.json in the output directory
+func writePlanJSON(plan Plan, outputDir string) (string, error) {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	path := filepath.Join(outputDir, "plan.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write plan: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/language/cloud"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+	"gopkg.in/yaml.v3"
+)
+
+// toolVersionCommands are the external tools the steps and policies shell out to, with
+// the arguments that print their version
+var toolVersionCommands = map[string][]string{
+	"aws":        {"--version"},
+	"az":         {"--version"},
+	"gcloud":     {"--version"},
+	"openssl":    {"version"},
+	"nc":         {"-h"},
+	"testssl.sh": {"--version"},
+}
+
+// secretKeyPattern matches further config keys whose values are redacted from the manifest,
+// for keys the schema does not describe (see redactedInstance)
+var secretKeyPattern = regexp.MustCompile(`(?i)(secret|password|passwd|token|credential|private-key|access-key|account-key|api-key|key$|connection-string|(^|[-_])sas([-_]|$))`)
+
+// redactedValue replaces the value of a secret key
+const redactedValue = "[REDACTED]"
+
+// Provenance records what produced a run's results, so a result that changes between
+// runs can be traced to the cloud, the policies or the tooling
+type Provenance struct {
+	GitCommit     string                   `json:"gitCommit,omitempty"`
+	GitDirty      bool                     `json:"gitDirty,omitempty"` // Uncommitted changes in the working tree
+	ModuleVersion string                   `json:"moduleVersion"`
+	GoVersion     string                   `json:"goVersion"`
+	Tools         map[string]string        `json:"tools"`         // First line of each tool's version output, or "not found"
+	TagExpression string                   `json:"tagExpression"` // ANDed with each service's catalog tags
+	Instances     []map[string]interface{} `json:"instances"`     // Expanded instance config, secrets redacted
+	Features      []FileHash               `json:"features"`
+	Policies      []FileHash               `json:"policies"` // Policy files evaluated by the run
+}
+
+// FileHash is the SHA-256 of a file, with its path relative to the testing directory
+type FileHash struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// collectProvenance records the runner version, tool versions, feature file hashes and
+// instance config at the start of a run; policy hashes are added by addPolicyHashes
+func collectProvenance(testingDir string, instances []types.InstanceConfig, tags []string) *Provenance {
+	p := &Provenance{
+		ModuleVersion: "(devel)",
+		GoVersion:     runtime.Version(),
+		Tools:         toolVersions(),
+		TagExpression: tagExpression(runTagFilter(tags)),
+		Features:      []FileHash{},
+		Policies:      []FileHash{},
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "" {
+			p.ModuleVersion = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				p.GitCommit = setting.Value
+			case "vcs.modified":
+				p.GitDirty = setting.Value == "true"
+			}
+		}
+	}
+	// `go run` does not stamp VCS information into the binary; ask git instead
+	if p.GitCommit == "" {
+		if out, err := exec.Command("git", "-C", testingDir, "rev-parse", "HEAD").Output(); err == nil {
+			p.GitCommit = strings.TrimSpace(string(out))
+			if status, err := exec.Command("git", "-C", testingDir, "status", "--porcelain").Output(); err == nil {
+				p.GitDirty = len(bytes.TrimSpace(status)) > 0
+			}
+		}
+	}
+
+	for _, inst := range instances {
+		p.Instances = append(p.Instances, redactedInstance(inst))
+	}
+
+	featuresDir := filepath.Join(testingDir, "features")
+	filepath.WalkDir(featuresDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".feature") {
+			p.Features = append(p.Features, hashFile(testingDir, path))
+		}
+		return nil
+	})
+	return p
+}
+
+// addPolicyHashes records the policy files evaluated by the run
+func (p *Provenance) addPolicyHashes(testingDir string) {
+	for _, path := range cloud.EvaluatedPolicies() {
+		p.Policies = append(p.Policies, hashFile(testingDir, path))
+	}
+}
+
+// productVersion is the version reported in OCSF findings: the module version of a
+// released build, otherwise the short git commit
+func (p *Provenance) productVersion() string {
+	if p.ModuleVersion != "" && p.ModuleVersion != "(devel)" {
+		return p.ModuleVersion
+	}
+	if p.GitCommit == "" {
+		return ""
+	}
+	version := p.GitCommit
+	if len(version) > 12 {
+		version = version[:12]
+	}
+	if p.GitDirty {
+		version += "-dirty"
+	}
+	return version
+}
+
+// hashFile returns the SHA-256 of path; unreadable files are recorded with an empty hash
+func hashFile(testingDir, path string) FileHash {
+	rel, err := filepath.Rel(testingDir, path)
+	if err != nil {
+		rel = path
+	}
+	hash := FileHash{Path: filepath.ToSlash(rel)}
+	f, err := os.Open(path)
+	if err != nil {
+		return hash
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err == nil {
+		hash.SHA256 = hex.EncodeToString(h.Sum(nil))
+	}
+	return hash
+}
+
+// toolVersions runs each tool's version command concurrently, allowing each 15 seconds
+func toolVersions() map[string]string {
+	versions := make(map[string]string, len(toolVersionCommands))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for tool, args := range toolVersionCommands {
+		wg.Add(1)
+		go func(tool string, args []string) {
+			defer wg.Done()
+			version := toolVersion(tool, args)
+			mu.Lock()
+			versions[tool] = version
+			mu.Unlock()
+		}(tool, args)
+	}
+	wg.Wait()
+	return versions
+}
+
+// toolVersion returns the first line of the tool's version output that contains a digit.
+// Some tools (nc -h) exit non-zero after printing their version, so errors are ignored.
+func toolVersion(tool string, args []string) string {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return "not found"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	out, _ := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if strings.ContainsAny(line, "0123456789") {
+			return line
+		}
+	}
+	return "unknown"
+}
+
+// redactedInstance returns the instance config as written in environment.yaml, after
+// variable expansion, with the values of secret keys replaced: those the schema marks as
+// secret, and any others that look like secrets
+func redactedInstance(inst types.InstanceConfig) map[string]interface{} {
+	services := make([]map[string]interface{}, 0, len(inst.Services))
+	for _, svc := range inst.Services {
+		service := map[string]interface{}{"type": svc.Type}
+		schema := types.ServicePropertyKeys(svc.Type)
+		for k, v := range svc.Properties {
+			if schema[k].Secret {
+				v = redactedValue
+			}
+			service[k] = v
+		}
+		services = append(services, service)
+	}
+	rules := make(map[string]interface{}, len(inst.Rules))
+	for k, v := range inst.Rules {
+		if types.RuleSchema[k].Secret {
+			v = redactedValue
+		}
+		rules[k] = v
+	}
+
+	raw := map[string]interface{}{}
+	data, err := yaml.Marshal(struct {
+		Properties types.CloudParams        `yaml:"properties"`
+		Services   []map[string]interface{} `yaml:"services"`
+		Rules      map[string]interface{}   `yaml:"rules,omitempty"`
+	}{inst.Properties, services, rules})
+	if err == nil {
+		yaml.Unmarshal(data, &raw)
+	}
+	raw["id"] = inst.ID
+	redact(raw)
+	return raw
+}
+
+// redact replaces, in place, the values of secret-looking keys in nested maps and lists
+func redact(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if secretKeyPattern.MatchString(k) {
+				v[k] = redactedValue
+				continue
+			}
+			redact(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redact(item)
+		}
+	}
+}
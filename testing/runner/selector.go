@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+// labelRequirement is one comma-separated term of a --selector expression
+type labelRequirement struct {
+	key    string
+	op     string // "=", "!=", "in", "notin", "exists" or "!exists"
+	values []string
+}
+
+// setPattern matches the set-based terms "key in (a, b)" and "key notin (a, b)"
+var setPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// ResourceSelector chooses the resources a run tests, by name (--resource) and by label
+// (--selector). A resource is tested when it matches both.
+type ResourceSelector struct {
+	name   func(string) bool // nil matches every name
+	labels []labelRequirement
+}
+
+// NewResourceSelector parses the --resource name pattern and the --selector expression;
+// either may be empty.
+//
+// The name pattern is an exact resource name, a glob ("prod-*") or a regular expression
+// between slashes ("/^prod-(eu|us)-/"). The selector is a comma-separated list of label
+// requirements, all of which must hold: "key=value" (or "=="), "key!=value",
+// "key in (a, b)", "key notin (a, b)", "key" (the label is set) and "!key" (it is not).
+// As with Kubernetes label selectors, "!=" and "notin" also match resources without the label.
+func NewResourceSelector(namePattern, selector string) (*ResourceSelector, error) {
+	s := &ResourceSelector{}
+
+	switch {
+	case namePattern == "":
+	case len(namePattern) > 2 && strings.HasPrefix(namePattern, "/") && strings.HasSuffix(namePattern, "/"):
+		re, err := regexp.Compile(namePattern[1 : len(namePattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource pattern %s: %w", namePattern, err)
+		}
+		s.name = re.MatchString
+	case strings.ContainsAny(namePattern, "*?["):
+		if _, err := path.Match(namePattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid resource pattern %s: %w", namePattern, err)
+		}
+		s.name = func(name string) bool {
+			matched, _ := path.Match(namePattern, name)
+			return matched
+		}
+	default:
+		s.name = func(name string) bool { return name == namePattern }
+	}
+
+	terms, err := splitSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range terms {
+		req, err := parseRequirement(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+		s.labels = append(s.labels, req)
+	}
+	return s, nil
+}
+
+// splitSelector splits a selector at the commas outside parentheses
+func splitSelector(selector string) ([]string, error) {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid selector %q: unbalanced ')'", selector)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid selector %q: unbalanced '('", selector)
+	}
+	if strings.TrimSpace(selector) == "" {
+		return nil, nil
+	}
+	return append(terms, selector[start:]), nil
+}
+
+// parseRequirement parses one term of a selector
+func parseRequirement(term string) (labelRequirement, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return labelRequirement{}, fmt.Errorf("empty requirement")
+	}
+
+	if m := setPattern.FindStringSubmatch(term); m != nil {
+		var values []string
+		for _, v := range strings.Split(m[3], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return labelRequirement{}, fmt.Errorf("%s: no values", term)
+		}
+		return labelRequirement{key: m[1], op: m[2], values: values}, checkLabelKey(m[1], term)
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if key, value, found := strings.Cut(term, op); found {
+			key = strings.TrimSpace(key)
+			if op == "==" {
+				op = "="
+			}
+			return labelRequirement{key: key, op: op, values: []string{strings.TrimSpace(value)}}, checkLabelKey(key, term)
+		}
+	}
+
+	if key, found := strings.CutPrefix(term, "!"); found {
+		key = strings.TrimSpace(key)
+		return labelRequirement{key: key, op: "!exists"}, checkLabelKey(key, term)
+	}
+	return labelRequirement{key: term, op: "exists"}, checkLabelKey(term, term)
+}
+
+// checkLabelKey rejects keys that are empty or contain selector syntax, e.g. "env in prod"
+// written without parentheses
+func checkLabelKey(key, term string) error {
+	if key == "" || strings.ContainsAny(key, " \t!=(),") {
+		return fmt.Errorf("%s: invalid label key %q", term, key)
+	}
+	return nil
+}
+
+// Matches reports whether the resource's name and labels satisfy the selector
+func (s *ResourceSelector) Matches(resource types.TestParams) bool {
+	if s.name != nil && !s.name(resource.ResourceName) {
+		return false
+	}
+	for _, req := range s.labels {
+		if !req.matches(resource.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether labels satisfy the requirement
+func (req labelRequirement) matches(labels []string) bool {
+	value, set := types.LabelValue(labels, req.key)
+	switch req.op {
+	case "exists":
+		return set
+	case "!exists":
+		return !set
+	case "=":
+		return set && value == req.values[0]
+	case "!=":
+		return !set || value != req.values[0]
+	case "in":
+		return set && containsString(req.values, value)
+	case "notin":
+		return !set || !containsString(req.values, value)
+	}
+	return false
+}
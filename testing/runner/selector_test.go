@@ -0,0 +1,148 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+)
+
+func TestResourceSelectorLabels(t *testing.T) {
+	prodEU := types.TestParams{ResourceName: "a", Labels: []string{"env=prod", "region=eu", "pci"}}
+	devUS := types.TestParams{ResourceName: "b", Labels: []string{"env=dev", "region=us"}}
+	unlabelled := types.TestParams{ResourceName: "c"}
+	resources := []types.TestParams{prodEU, devUS, unlabelled}
+
+	tests := []struct {
+		selector string
+		want     []string // names of the matching resources
+	}{
+		{"", []string{"a", "b", "c"}},
+		{"env=prod", []string{"a"}},
+		{"env==prod", []string{"a"}},
+		{" env = prod ", []string{"a"}},
+		{"env!=prod", []string{"b", "c"}},
+		{"env in (prod, dev)", []string{"a", "b"}},
+		{"env in (staging)", nil},
+		{"env notin (prod)", []string{"b", "c"}},
+		{"pci", []string{"a"}},
+		{"!pci", []string{"b", "c"}},
+		{"env", []string{"a", "b"}},
+		{"!env", []string{"c"}},
+		{"pci=", []string{"a"}},
+		{"env=prod,region=eu", []string{"a"}},
+		{"env=prod,region=us", nil},
+		{"region in (eu, us),env!=dev", []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := NewResourceSelector("", tt.selector)
+			if err != nil {
+				t.Fatalf("NewResourceSelector(%q): %v", tt.selector, err)
+			}
+			var got []string
+			for _, r := range resources {
+				if s.Matches(r) {
+					got = append(got, r.ResourceName)
+				}
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("%q matched %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceSelectorNames(t *testing.T) {
+	names := []string{"prod", "prod-eu", "prod-us", "dev-eu", "prod-*"}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"", names},
+		// Names without glob characters match exactly, not as a prefix
+		{"prod", []string{"prod"}},
+		{"prod-eu", []string{"prod-eu"}},
+		{"prod-*", []string{"prod-eu", "prod-us", "prod-*"}},
+		{"*-eu", []string{"prod-eu", "dev-eu"}},
+		{"prod-??", []string{"prod-eu", "prod-us"}},
+		{"prod-[e]u", []string{"prod-eu"}},
+		// Regular expressions are unanchored unless they say otherwise
+		{"/eu/", []string{"prod-eu", "dev-eu"}},
+		{"/^prod-(eu|us)$/", []string{"prod-eu", "prod-us"}},
+		{"/^prod$/", []string{"prod"}},
+		// Too short to be a regular expression, so an exact name
+		{"//", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			s, err := NewResourceSelector(tt.pattern, "")
+			if err != nil {
+				t.Fatalf("NewResourceSelector(%q): %v", tt.pattern, err)
+			}
+			var got []string
+			for _, name := range names {
+				if s.Matches(types.TestParams{ResourceName: name}) {
+					got = append(got, name)
+				}
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("%q matched %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceSelectorNameAndLabels(t *testing.T) {
+	s, err := NewResourceSelector("prod-*", "env=prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		resource types.TestParams
+		want     bool
+	}{
+		{types.TestParams{ResourceName: "prod-eu", Labels: []string{"env=prod"}}, true},
+		{types.TestParams{ResourceName: "prod-eu", Labels: []string{"env=dev"}}, false},
+		{types.TestParams{ResourceName: "dev-eu", Labels: []string{"env=prod"}}, false},
+	} {
+		if got := s.Matches(tt.resource); got != tt.want {
+			t.Errorf("Matches(%s %v) = %v, want %v", tt.resource.ResourceName, tt.resource.Labels, got, tt.want)
+		}
+	}
+}
+
+func TestResourceSelectorRejectsMalformed(t *testing.T) {
+	tests := []struct{ pattern, selector string }{
+		{"/prod-(eu/", ""},
+		{"/[/", ""},
+		{"prod-[", ""},
+		{"", "env in (prod"},
+		{"", "env in prod)"},
+		{"", "env=prod,,region=eu"},
+		{"", "env=prod,"},
+		{"", "=prod"},
+		{"", "!=prod"},
+		{"", "env in ()"},
+		{"", "env in prod"},
+		{"", "!"},
+		{"", "env prod"},
+	}
+	for _, tt := range tests {
+		if _, err := NewResourceSelector(tt.pattern, tt.selector); err == nil {
+			t.Errorf("NewResourceSelector(%q, %q) succeeded, want an error", tt.pattern, tt.selector)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
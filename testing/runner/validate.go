@@ -0,0 +1,390 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/finos-labs/ccc-cfi-compliance/testing/api/generic"
+	"github.com/finos-labs/ccc-cfi-compliance/testing/types"
+	"gopkg.in/yaml.v3"
+)
+
+// envProblem is an issue found in environment.yaml by validate-env
+type envProblem struct {
+	Instance string // Instance ID, or "" outside any instance
+	Line     int
+	Message  string
+	Warning  bool
+}
+
+// integerPattern matches integer values written as strings, e.g. "2"
+var integerPattern = regexp.MustCompile(`^-?[0-9]+$`)
+
+// runValidateEnv implements `ccc-compliance validate-env`: it checks environment.yaml
+// against the per-service schema in types/schema.go without calling any cloud API.
+// Returns the exit code.
+func runValidateEnv(args []string, testingDir string) int {
+	flags := flag.NewFlagSet("validate-env", flag.ExitOnError)
+	envFile := flags.String("env-file", "", "Path to environment.yaml (default: environment.yaml in testing directory)")
+	instance := flags.String("instance", "all", "Instance ID(s) to validate: one ID, a comma-separated list, or 'all'")
+	strict := flags.Bool("strict", false, "Fail on warnings too (unset variables, keys the instance's provider does not read)")
+	flags.Parse(args)
+
+	envFilePath := *envFile
+	if envFilePath == "" {
+		envFilePath = filepath.Join(testingDir, "environment.yaml")
+	}
+
+	doc, unresolved, err := parseEnvironment(envFilePath)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return 1
+	}
+	var config types.EnvironmentConfig
+	if err := doc.Decode(&config); err != nil {
+		log.Printf("❌ Failed to parse environment file %s: %v", envFilePath, err)
+		return 1
+	}
+	instances, err := FindInstances(&config, *instance)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return 1
+	}
+
+	var problems []envProblem
+	if len(doc.Content) > 0 {
+		problems = validateEnvironment(doc.Content[0])
+	}
+	for _, v := range UnresolvedFor(unresolved, instances, "") {
+		message := fmt.Sprintf("%s: ${%s} is not set", v.Key, v.Name)
+		if v.Message != "" {
+			message += ": " + v.Message
+		}
+		problems = append(problems, envProblem{Instance: v.Instance, Line: v.Line, Message: message, Warning: !v.Required})
+	}
+
+	selected := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		selected[inst.ID] = true
+	}
+	var reported []envProblem
+	for _, p := range problems {
+		if p.Instance == "" || selected[p.Instance] {
+			reported = append(reported, p)
+		}
+	}
+	sort.SliceStable(reported, func(i, j int) bool {
+		return reported[i].Line < reported[j].Line
+	})
+
+	log.Printf("🔎 Validating %s", envFilePath)
+	errors, warnings := 0, 0
+	var order []string
+	byInstance := make(map[string][]envProblem)
+	for _, p := range reported {
+		if _, ok := byInstance[p.Instance]; !ok {
+			order = append(order, p.Instance)
+		}
+		byInstance[p.Instance] = append(byInstance[p.Instance], p)
+		if p.Warning {
+			warnings++
+		} else {
+			errors++
+		}
+	}
+	for _, id := range order {
+		if id == "" {
+			log.Printf("   Top level:")
+		} else {
+			log.Printf("   Instance %s:", id)
+		}
+		for _, p := range byInstance[id] {
+			icon := "❌"
+			if p.Warning {
+				icon = "⚠️ "
+			}
+			log.Printf("     %s line %d: %s", icon, p.Line, p.Message)
+		}
+	}
+
+	if errors > 0 || (*strict && warnings > 0) {
+		log.Printf("❌ %d error(s), %d warning(s) in %d instance(s)", errors, warnings, len(instances))
+		return 1
+	}
+	log.Printf("✅ %d instance(s) valid (%d warning(s))", len(instances), warnings)
+	return 0
+}
+
+// validateEnvironment checks the root of environment.yaml: an instances list, each with
+// an id, a provider, known service types and known keys of the right types
+func validateEnvironment(root *yaml.Node) []envProblem {
+	var problems []envProblem
+	if root.Kind != yaml.MappingNode {
+		return append(problems, envProblem{Line: root.Line, Message: "the file must be a mapping with an 'instances' list"})
+	}
+	instancesNode := mappingValue(root, "instances")
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if key := root.Content[i]; key.Value != "instances" {
+			problems = append(problems, envProblem{Line: key.Line, Message: unknownKeyMessage("", key.Value, []string{"instances"})})
+		}
+	}
+	if instancesNode == nil || instancesNode.Kind != yaml.SequenceNode {
+		return append(problems, envProblem{Line: root.Line, Message: "'instances' must be a list of instances"})
+	}
+
+	seen := make(map[string]int)
+	for _, inst := range instancesNode.Content {
+		problems = append(problems, validateInstance(inst, seen)...)
+	}
+	return problems
+}
+
+// validateInstance checks one entry of the instances list; seen maps instance IDs to the
+// line they were first defined on
+func validateInstance(inst *yaml.Node, seen map[string]int) []envProblem {
+	var problems []envProblem
+	if inst.Kind != yaml.MappingNode {
+		return append(problems, envProblem{Line: inst.Line, Message: "each instance must be a mapping with id, properties and services"})
+	}
+
+	id := ""
+	if idNode := mappingValue(inst, "id"); idNode != nil {
+		id = idNode.Value
+	}
+	add := func(line int, warning bool, format string, args ...interface{}) {
+		problems = append(problems, envProblem{Instance: id, Line: line, Message: fmt.Sprintf(format, args...), Warning: warning})
+	}
+	if id == "" {
+		add(inst.Line, false, "instance has no id")
+	} else if line, dup := seen[id]; dup {
+		add(inst.Line, false, "duplicate instance id '%s' (first defined on line %d)", id, line)
+	} else {
+		seen[id] = inst.Line
+	}
+
+	instanceKeys := []string{"id", "properties", "services", "rules"}
+	for i := 0; i+1 < len(inst.Content); i += 2 {
+		if key := inst.Content[i]; !containsString(instanceKeys, key.Value) {
+			add(key.Line, false, "%s", unknownKeyMessage("", key.Value, instanceKeys))
+		}
+	}
+
+	provider := ""
+	properties := mappingValue(inst, "properties")
+	if properties != nil {
+		if providerNode := mappingValue(properties, "provider"); providerNode != nil {
+			provider = providerNode.Value
+		}
+	}
+	switch {
+	case provider == "":
+		add(inst.Line, false, "properties.provider is required (one of %s)", strings.Join(types.Providers, ", "))
+	case !containsString(types.Providers, provider):
+		add(mappingValue(properties, "provider").Line, false, "unknown provider '%s' (one of %s)", provider, strings.Join(types.Providers, ", "))
+		provider = ""
+	}
+
+	for _, p := range validateBlock(inst.Line, properties, "properties", provider, types.InstancePropertySchema) {
+		add(p.Line, p.Warning, "%s", p.Message)
+	}
+	if rules := mappingValue(inst, "rules"); rules != nil {
+		for _, p := range validateBlock(inst.Line, rules, "rules", provider, types.RuleSchema) {
+			add(p.Line, p.Warning, "%s", p.Message)
+		}
+	}
+
+	services := mappingValue(inst, "services")
+	if services == nil || services.Kind != yaml.SequenceNode {
+		add(inst.Line, false, "'services' must be a list of service blocks")
+		return problems
+	}
+	serviceLines := make(map[string]int)
+	for _, svc := range services.Content {
+		if svc.Kind != yaml.MappingNode {
+			add(svc.Line, false, "each service must be a mapping with a type")
+			continue
+		}
+		typeNode := mappingValue(svc, "type")
+		if typeNode == nil || typeNode.Value == "" {
+			add(svc.Line, false, "service has no type (one of %s)", strings.Join(generic.ServiceNames(), ", "))
+			continue
+		}
+		serviceType := typeNode.Value
+		definition, ok := generic.LookupService(serviceType)
+		if !ok {
+			add(typeNode.Line, false, "%s", unknownValueMessage("service type", serviceType, generic.ServiceNames()))
+			continue
+		}
+		if _, ok := definition.Providers[provider]; provider != "" && !ok {
+			supported := make([]string, 0, len(definition.Providers))
+			for p := range definition.Providers {
+				supported = append(supported, p)
+			}
+			sort.Strings(supported)
+			add(typeNode.Line, false, "instance '%s': service '%s' is not implemented for provider '%s' (only %s)", id, serviceType, provider, strings.Join(supported, ", "))
+			continue
+		}
+		if line, dup := serviceLines[serviceType]; dup {
+			add(svc.Line, false, "duplicate '%s' service (first defined on line %d); only the first is used", serviceType, line)
+			continue
+		}
+		serviceLines[serviceType] = svc.Line
+
+		schema := types.ServicePropertyKeys(serviceType)
+		schema["type"] = types.PropertySchema{Type: types.StringProperty}
+		for _, p := range validateBlock(svc.Line, svc, "services."+serviceType, provider, schema) {
+			add(p.Line, p.Warning, "%s", p.Message)
+		}
+	}
+	return problems
+}
+
+// validateBlock checks the keys of a mapping against schema: unknown keys and wrong value
+// types are errors, keys the provider does not read are warnings, and keys required for
+// the provider must be set. provider is "" when it is unknown.
+func validateBlock(line int, block *yaml.Node, where, provider string, schema map[string]types.PropertySchema) []envProblem {
+	var problems []envProblem
+	if block != nil && block.Kind != yaml.MappingNode && block.Tag != "!!null" {
+		return append(problems, envProblem{Line: block.Line, Message: fmt.Sprintf("%s must be a mapping", where)})
+	}
+
+	known := make([]string, 0, len(schema))
+	for key := range schema {
+		known = append(known, key)
+	}
+	sort.Strings(known)
+
+	set := make(map[string]bool)
+	if block != nil {
+		for i := 0; i+1 < len(block.Content); i += 2 {
+			key, value := block.Content[i], block.Content[i+1]
+			property, ok := schema[key.Value]
+			if !ok {
+				problems = append(problems, envProblem{Line: key.Line, Message: unknownKeyMessage(where, key.Value, known)})
+				continue
+			}
+			if value.Kind != yaml.ScalarNode || (value.Tag != "!!null" && strings.TrimSpace(value.Value) != "") {
+				set[key.Value] = true
+			}
+			if provider != "" && !property.AppliesTo(provider) {
+				problems = append(problems, envProblem{Line: key.Line, Warning: true,
+					Message: fmt.Sprintf("%s.%s is not used by provider '%s' (only %s)", where, key.Value, provider, strings.Join(property.Providers, ", "))})
+			}
+			if message := checkPropertyType(value, property.Type); message != "" {
+				problems = append(problems, envProblem{Line: value.Line, Message: fmt.Sprintf("%s.%s %s", where, key.Value, message)})
+			}
+		}
+	}
+
+	if provider != "" {
+		for _, key := range known {
+			if schema[key].IsRequiredFor(provider) && !set[key] {
+				problems = append(problems, envProblem{Line: line, Message: fmt.Sprintf("%s.%s is required for provider '%s'", where, key, provider)})
+			}
+		}
+	}
+	return problems
+}
+
+// checkPropertyType returns why value is not of the expected type, or "" if it is.
+// Empty values are accepted; required keys are checked separately.
+func checkPropertyType(value *yaml.Node, want types.PropertyType) string {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+		return ""
+	}
+	switch want {
+	case types.ListProperty:
+		if value.Kind == yaml.SequenceNode {
+			return ""
+		}
+		if value.Kind == yaml.ScalarNode && strings.Contains(value.Value, ",") {
+			return fmt.Sprintf("must be a YAML list, e.g. [%s], not a comma-separated string", strings.Join(strings.Split(value.Value, ","), ", "))
+		}
+		if value.Kind == yaml.ScalarNode {
+			return fmt.Sprintf("must be a YAML list, e.g. [%s]", value.Value)
+		}
+		return "must be a YAML list"
+	case types.CSVProperty:
+		if value.Kind == yaml.SequenceNode {
+			return "must be a comma-separated string, e.g. \"a,b\", not a YAML list"
+		}
+	case types.IntProperty:
+		if value.Kind == yaml.ScalarNode && (value.Tag == "!!int" || integerPattern.MatchString(value.Value)) {
+			return ""
+		}
+		return fmt.Sprintf("must be an integer (got '%s')", value.Value)
+	case types.BoolProperty:
+		if value.Kind == yaml.ScalarNode && (value.Tag == "!!bool" || strings.EqualFold(value.Value, "true") || strings.EqualFold(value.Value, "false")) {
+			return ""
+		}
+		return fmt.Sprintf("must be true or false (got '%s')", value.Value)
+	}
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Sprintf("must be a %s", want)
+	}
+	return ""
+}
+
+// unknownKeyMessage reports an unknown key, suggesting the closest known key
+func unknownKeyMessage(where, key string, known []string) string {
+	name := key
+	if where != "" {
+		name = where + "." + key
+	}
+	return unknownValueMessage("key", name, nil) + suggestion(key, known)
+}
+
+// unknownValueMessage reports an unknown value, suggesting the closest known value
+func unknownValueMessage(what, value string, known []string) string {
+	return fmt.Sprintf("unknown %s '%s'", what, value) + suggestion(value, known)
+}
+
+// suggestion returns " (did you mean 'x'?)" for the known value closest to value, if any
+// is within a few edits
+func suggestion(value string, known []string) string {
+	best, bestDistance := "", 4
+	for _, k := range known {
+		if d := editDistance(value, k); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s'?)", best)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateEnvironmentRejectsServiceWithoutProvider(t *testing.T) {
+	var doc yaml.Node
+	err := yaml.Unmarshal([]byte(`
+instances:
+  - id: main-azure
+    properties:
+      provider: azure
+      region: eastus
+      azure-resource-group: rg
+      azure-subscription-id: sub
+    services:
+      - type: object-storage
+      - type: vpc
+`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unsupported []envProblem
+	for _, p := range validateEnvironment(doc.Content[0]) {
+		if strings.Contains(p.Message, "not implemented for provider") {
+			unsupported = append(unsupported, p)
+		}
+	}
+	if len(unsupported) != 1 {
+		t.Fatalf("got %d unsupported-service problems, want 1 for vpc: %+v", len(unsupported), unsupported)
+	}
+	p := unsupported[0]
+	if p.Warning || p.Instance != "main-azure" || p.Line != 11 ||
+		!strings.Contains(p.Message, "'main-azure'") || !strings.Contains(p.Message, "'vpc'") || !strings.Contains(p.Message, "'azure'") {
+		t.Errorf("problem = %+v, want an error for vpc on azure in main-azure at line 11", p)
+	}
+}
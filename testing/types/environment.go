@@ -1,11 +1,6 @@
 package types
 
-import (
-	"fmt"
-	"strings"
-
-	"gopkg.in/yaml.v3"
-)
+import "gopkg.in/yaml.v3"
 
 // EnvironmentConfig is the top-level structure of types.yaml
 type EnvironmentConfig struct {
@@ -42,72 +37,109 @@ func (ic InstanceConfig) ServiceProperties(serviceType string) map[string]interf
 	return nil
 }
 
+// Service blocks and rules are decoded into the structs below by DecodeProperties. The yaml
+// tag names the key; optional tags are providers (comma-separated providers that read the
+// key), required (providers that need it set), default (value used when the key is unset
+// or empty), format:"csv" (a []string written as a comma-separated string rather than a
+// YAML list) and secret:"true" (a credential, redacted from the run manifest). []string
+// fields accept either form.
+
+// ObjectStorageConfig holds typed properties from the environment.yaml object-storage block.
+type ObjectStorageConfig struct {
+	RetentionPeriodDays    int    `yaml:"object-storage-retention-period-days" providers:"aws,azure"`
+	RetentionPeriodSeconds int    `yaml:"object-storage-retention-period-seconds" providers:"gcp"`
+	AzureStorageAccount    string `yaml:"azure-storage-account" providers:"azure" required:"azure"`
+	DefaultContainer       string `yaml:"default-container" providers:"azure" default:"ccc-test-container-2"`
+	GcpBucketName          string `yaml:"gcp-bucket-name" providers:"gcp"`
+}
+
+// LoggingConfig holds typed properties from the environment.yaml logging block.
+type LoggingConfig struct {
+	AwsCloudTrailLogGroupName string `yaml:"aws-cloud-trail-log-group-name" providers:"aws"`
+	GcpLogBucketName          string `yaml:"gcp-log-bucket-name" providers:"gcp"`
+}
+
 // VpcServiceConfig holds typed VPC service properties from the environment.yaml vpc block.
-// All fields map directly to their kebab-case counterparts in the yaml vpc service block.
 type VpcServiceConfig struct {
-	Cn03ReceiverVpcId                string
-	Cn03NonAllowlistedRequesterVpcId string
-	Cn03AllowedRequesterVpcIds       []string
-	Cn03DisallowedRequesterVpcIds    []string
-	Cn03AllowedRequesterVpcIdsCsv    string
-	Cn03DisallowedRequesterVpcIdsCsv string
-	Cn04FlowLogGroupName             string
-	BadVpcId                         string
+	Cn03ReceiverVpcId                string   `yaml:"cn03-receiver-vpc-id" providers:"aws"`
+	Cn03NonAllowlistedRequesterVpcId string   `yaml:"cn03-non-allowlisted-requester-vpc-id" providers:"aws,local"`
+	Cn03AllowedRequesterVpcIds       []string `yaml:"cn03-allowed-requester-vpc-ids" providers:"aws,local"`
+	Cn03DisallowedRequesterVpcIds    []string `yaml:"cn03-disallowed-requester-vpc-ids" providers:"aws,local"`
+	Cn03AllowedRequesterVpcIdsCsv    []string `yaml:"cn03-allowed-requester-vpc-ids-csv" providers:"aws,local" format:"csv"`
+	Cn03DisallowedRequesterVpcIdsCsv []string `yaml:"cn03-disallowed-requester-vpc-ids-csv" providers:"aws,local" format:"csv"`
+	Cn04FlowLogGroupName             string   `yaml:"cn04-flow-log-group-name" providers:"aws,local"`
+	BadVpcId                         string   `yaml:"bad-vpc-id" providers:"aws"`
+}
+
+// RulesConfig holds the typed instance rules read by features and policies.
+type RulesConfig struct {
+	PermittedRegions                    []string `yaml:"permitted-regions"`
+	ReplicationLocations                []string `yaml:"replication-locations"`
+	PermittedDestinationStorageAccounts []string `yaml:"permitted-destination-storage-accounts" providers:"azure"`
+	PermittedAccountIDs                 []string `yaml:"permitted-account-ids" providers:"aws" format:"csv"`
+	PermittedProjectIDs                 []string `yaml:"permitted-project-ids" providers:"gcp" format:"csv"`
+}
+
+// ObjectStorageConfig returns typed object-storage properties for this instance.
+// Invalid values are left at their zero value; Validate reports them.
+func (ic InstanceConfig) ObjectStorageConfig() ObjectStorageConfig {
+	var config ObjectStorageConfig
+	ic.DecodeServiceConfig("object-storage", &config)
+	return config
 }
 
 // VpcServiceConfig returns typed VPC service properties for this instance.
 // Returns a zero-value struct if no vpc service block is configured.
 func (ic InstanceConfig) VpcServiceConfig() VpcServiceConfig {
-	props := ic.ServiceProperties("vpc")
-	if props == nil {
-		return VpcServiceConfig{}
-	}
-	return VpcServiceConfig{
-		Cn03ReceiverVpcId:                vpcPropString(props, "cn03-receiver-vpc-id"),
-		Cn03NonAllowlistedRequesterVpcId: vpcPropString(props, "cn03-non-allowlisted-requester-vpc-id"),
-		Cn03AllowedRequesterVpcIds:       vpcPropStringSlice(props, "cn03-allowed-requester-vpc-ids"),
-		Cn03DisallowedRequesterVpcIds:    vpcPropStringSlice(props, "cn03-disallowed-requester-vpc-ids"),
-		Cn03AllowedRequesterVpcIdsCsv:    vpcPropString(props, "cn03-allowed-requester-vpc-ids-csv"),
-		Cn03DisallowedRequesterVpcIdsCsv: vpcPropString(props, "cn03-disallowed-requester-vpc-ids-csv"),
-		Cn04FlowLogGroupName:             vpcPropString(props, "cn04-flow-log-group-name"),
-		BadVpcId:                         vpcPropString(props, "bad-vpc-id"),
-	}
+	var config VpcServiceConfig
+	ic.DecodeServiceConfig("vpc", &config)
+	return config
 }
 
-func vpcPropString(props map[string]interface{}, key string) string {
-	if v, ok := props[key]; ok {
-		if v == nil {
-			return ""
-		}
-		s := strings.TrimSpace(fmt.Sprintf("%v", v))
-		if s == "<nil>" {
-			return ""
-		}
-		return s
-	}
-	return ""
-}
-
-func vpcPropStringSlice(props map[string]interface{}, key string) []string {
-	if v, ok := props[key]; ok {
-		switch tv := v.(type) {
-		case []interface{}:
-			out := make([]string, 0, len(tv))
-			for _, item := range tv {
-				if s := strings.TrimSpace(fmt.Sprintf("%v", item)); s != "" {
-					out = append(out, s)
-				}
-			}
-			return out
-		case []string:
-			return tv
-		case string:
-			if s := strings.TrimSpace(tv); s != "" {
-				return []string{s}
-			}
+// LocalServiceConfig holds the properties of a service on the offline "local" provider.
+// Every control is compliant unless it is listed in NonCompliantControls.
+type LocalServiceConfig struct {
+	Resources            []string `yaml:"resources" providers:"local"`              // In-memory resources to expose (bucket names, VPC IDs)
+	NonCompliantControls []string `yaml:"non-compliant-controls" providers:"local"` // Controls the fake should violate, e.g. "CCC.ObjStor.CN02"
+	ReplicaRegion        string   `yaml:"replica-region" providers:"local"`         // Secondary region reported by GetReplicationStatus
+	Labels               []string `yaml:"labels" providers:"local"`                 // Labels of every resource, as "key=value"
+}
+
+// LocalServiceConfig returns the local-provider properties for the named service type.
+// Returns a zero-value struct if the service is not configured.
+func (ic InstanceConfig) LocalServiceConfig(serviceType string) LocalServiceConfig {
+	var config LocalServiceConfig
+	ic.DecodeServiceConfig(serviceType, &config)
+	return config
+}
+
+// Compliant reports whether the fake should behave compliantly for the given control
+func (c LocalServiceConfig) Compliant(control string) bool {
+	for _, nc := range c.NonCompliantControls {
+		if nc == control {
+			return false
 		}
 	}
-	return []string{}
+	return true
+}
+
+// EndpointConfig holds client endpoint overrides from a service block, used to point the
+// cloud clients at a local emulator such as MinIO, LocalStack, Azurite or fake-gcs-server.
+type EndpointConfig struct {
+	EndpointURL        string `yaml:"endpoint-url" providers:"aws,azure,gcp"`            // Base URL replacing the default service endpoint, e.g. http://localhost:9000
+	UsePathStyle       bool   `yaml:"use-path-style" providers:"aws"`                    // Address buckets as endpoint/bucket rather than bucket.endpoint
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify" providers:"aws,azure,gcp"`    // Skip TLS certificate verification (self-signed emulator certs)
+	ConnectionString   string `yaml:"connection-string" providers:"azure" secret:"true"` // Azure Storage connection string (Azurite); takes precedence over EndpointURL
+	AccountKey         string `yaml:"account-key" providers:"azure" secret:"true"`       // Azure shared key used with EndpointURL; anonymous access if empty
+	Unauthenticated    bool   `yaml:"unauthenticated" providers:"gcp"`                   // Send no credentials (GCP emulators such as fake-gcs-server)
+}
+
+// EndpointConfig returns the endpoint overrides for the named service type.
+// Returns a zero-value struct (default endpoints) if none are configured.
+func (ic InstanceConfig) EndpointConfig(serviceType string) EndpointConfig {
+	var config EndpointConfig
+	ic.DecodeServiceConfig(serviceType, &config)
+	return config
 }
 
 func (s *ServiceConfig) UnmarshalYAML(value *yaml.Node) error {
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// LabelsFromMap converts a resource's tags or labels to TestParams labels, "key=value"
+// sorted by key
+func LabelsFromMap(tags map[string]string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	labels := make([]string, 0, len(tags))
+	for key, value := range tags {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// LabelValue returns the value of the label with the given key, and whether it is set
+func LabelValue(labels []string, key string) (string, bool) {
+	for _, label := range labels {
+		k, v, _ := strings.Cut(label, "=")
+		if k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // PolicyResult contains the complete result of a policy check
 type PolicyResult struct {
 	// Policy metadata
@@ -38,13 +43,44 @@ type RuleResult struct {
 
 // PolicyDefinition represents the structure of a policy YAML file
 type PolicyDefinition struct {
-	Name               string `yaml:"name"`
-	ServiceType        string `yaml:"service_type"`
-	RequirementText    string `yaml:"requirement_text"`
-	ValidityScore      int    `yaml:"validity_score"`
-	ValidityCommentary string `yaml:"validity_commentary"`
-	Query              string `yaml:"query"`
-	Rules              []Rule `yaml:"rules"`
+	Name               string    `yaml:"name"`
+	ServiceType        string    `yaml:"service_type"`
+	RequirementText    string    `yaml:"requirement_text"`
+	ValidityScore      int       `yaml:"validity_score"`
+	ValidityCommentary string    `yaml:"validity_commentary"`
+	Query              string    `yaml:"query"`         // Shell command, normally an aws, az or gcloud CLI call
+	SDK                *SDKQuery `yaml:"sdk,omitempty"` // SDK call made instead of Query
+	Rules              []Rule    `yaml:"rules"`
+}
+
+// SDKQuery is a policy query made through a Go SDK client held by the service under test
+// rather than a CLI, e.g. {provider: aws, service: s3, operation: GetPublicAccessBlock,
+// params: {Bucket: "${ResourceName}"}}. Its result is marshalled to JSON for the rules.
+type SDKQuery struct {
+	Provider  string                 `yaml:"provider" json:"provider"`                 // aws, azure or gcp
+	Service   string                 `yaml:"service" json:"service"`                   // SDK client, e.g. "s3", "ec2", "armstorage"
+	Operation string                 `yaml:"operation" json:"operation"`               // Client method, e.g. "GetPublicAccessBlock"
+	Params    map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"` // Fields of the operation's input struct (AWS)
+	Args      []interface{}          `yaml:"args,omitempty" json:"args,omitempty"`     // Positional arguments after the context (Azure, GCP)
+}
+
+// String describes the call, e.g. aws s3.GetPublicAccessBlock {"Bucket":"my-bucket"}
+func (q SDKQuery) String() string {
+	call := fmt.Sprintf("%s %s.%s", q.Provider, q.Service, q.Operation)
+	var input interface{}
+	switch {
+	case q.Params != nil:
+		input = q.Params
+	case len(q.Args) > 0:
+		input = q.Args
+	default:
+		return call
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Sprintf("%s %v", call, input)
+	}
+	return call + " " + string(data)
 }
 
 // Rule represents a single validation rule in a policy
@@ -0,0 +1,100 @@
+package types
+
+import "reflect"
+
+// PropertyType is the YAML type expected for a key in environment.yaml
+type PropertyType string
+
+const (
+	StringProperty PropertyType = "string"
+	IntProperty    PropertyType = "integer"
+	BoolProperty   PropertyType = "boolean"
+	ListProperty   PropertyType = "list" // YAML list, e.g. [a, b]
+	CSVProperty    PropertyType = "csv"  // Comma-separated string, e.g. "a,b"
+)
+
+// PropertySchema describes one key of an instance's properties or rules, or of a service
+// block. It is derived from the tags of the struct the key decodes into.
+type PropertySchema struct {
+	Type        PropertyType
+	Providers   []string // Providers that read the key; empty means every provider
+	RequiredFor []string // Providers that need the key to be set
+	Default     string   // Value used when the key is unset
+	Secret      bool     // The value is a credential, redacted from the run manifest
+}
+
+// Providers contains the supported values of properties.provider
+var Providers = []string{"aws", "azure", "gcp", "local"}
+
+// InstancePropertySchema describes the keys of an instance's properties block (CloudParams)
+var InstancePropertySchema = schemaOf(reflect.TypeOf(CloudParams{}))
+
+// RuleSchema describes the keys of an instance's rules block (RulesConfig)
+var RuleSchema = schemaOf(reflect.TypeOf(RulesConfig{}))
+
+// ServicePropertyKeys returns every key accepted in a service block of the given type: those
+// of EndpointConfig, LocalServiceConfig and the struct registered for the type
+func ServicePropertyKeys(serviceType string) map[string]PropertySchema {
+	keys := make(map[string]PropertySchema)
+	for _, t := range serviceConfigTypes(serviceType) {
+		for k, v := range schemaOf(t) {
+			keys[k] = v
+		}
+	}
+	return keys
+}
+
+// schemaOf derives the schema of each tagged field of a config struct
+func schemaOf(t reflect.Type) map[string]PropertySchema {
+	schema := make(map[string]PropertySchema)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := propertyKey(field)
+		if key == "" {
+			continue
+		}
+		schema[key] = PropertySchema{
+			Type:        propertyType(field),
+			Providers:   tagList(field, "providers"),
+			RequiredFor: tagList(field, "required"),
+			Default:     field.Tag.Get("default"),
+			Secret:      field.Tag.Get("secret") == "true",
+		}
+	}
+	return schema
+}
+
+// propertyType returns the YAML type expected for a config struct field
+func propertyType(field reflect.StructField) PropertyType {
+	switch field.Type.Kind() {
+	case reflect.Int, reflect.Int64:
+		return IntProperty
+	case reflect.Bool:
+		return BoolProperty
+	case reflect.Slice:
+		if field.Tag.Get("format") == "csv" {
+			return CSVProperty
+		}
+		return ListProperty
+	}
+	return StringProperty
+}
+
+// AppliesTo reports whether the key is read for the given provider
+func (s PropertySchema) AppliesTo(provider string) bool {
+	return len(s.Providers) == 0 || containsProvider(s.Providers, provider)
+}
+
+// IsRequiredFor reports whether the key must be set for the given provider
+func (s PropertySchema) IsRequiredFor(provider string) bool {
+	return containsProvider(s.RequiredFor, provider)
+}
+
+func containsProvider(providers []string, provider string) bool {
+	for _, p := range providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
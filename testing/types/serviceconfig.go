@@ -0,0 +1,308 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// serviceConfigs maps each service type to the struct its environment.yaml block decodes into
+var serviceConfigs = map[string]reflect.Type{}
+
+// RegisterServiceConfig registers the struct a service type's block decodes into, e.g.
+// RegisterServiceConfig("vpc", VpcServiceConfig{}). It is called by generic.RegisterService
+// for the Config of a service definition. Every block may also set the keys of
+// EndpointConfig and LocalServiceConfig.
+func RegisterServiceConfig(serviceType string, config interface{}) {
+	t := reflect.TypeOf(config)
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("RegisterServiceConfig(%s): %T is not a struct", serviceType, config))
+	}
+	serviceConfigs[serviceType] = t
+}
+
+// serviceConfigTypes returns the structs a block of the given service type decodes into
+func serviceConfigTypes(serviceType string) []reflect.Type {
+	configTypes := []reflect.Type{reflect.TypeOf(EndpointConfig{}), reflect.TypeOf(LocalServiceConfig{})}
+	if t, ok := serviceConfigs[serviceType]; ok {
+		configTypes = append(configTypes, t)
+	}
+	return configTypes
+}
+
+// DecodeServiceConfig decodes the block of the named service type into out, which points to
+// the registered struct, EndpointConfig or LocalServiceConfig
+func (ic InstanceConfig) DecodeServiceConfig(serviceType string, out interface{}) error {
+	return DecodeProperties(ic.ServiceProperties(serviceType), out, ic.Properties.Provider)
+}
+
+// DecodeProperties decodes a service block or rules map into the struct out points to. Unset
+// keys take their default, values are converted to the field types (a []string accepts a
+// YAML list or a comma-separated string), and keys required for provider must be set.
+// Every problem is reported in the returned error; the other fields are still decoded.
+func DecodeProperties(props map[string]interface{}, out interface{}, provider string) error {
+	return errors.Join(decodeProperties(props, out, provider)...)
+}
+
+func decodeProperties(props map[string]interface{}, out interface{}, provider string) []error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return []error{fmt.Errorf("cannot decode properties into %T: not a pointer to a struct", out)}
+	}
+	v = v.Elem()
+
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := propertyKey(field)
+		if key == "" {
+			continue
+		}
+		raw := props[key]
+		if isEmptyProperty(raw) {
+			if def, ok := field.Tag.Lookup("default"); ok {
+				raw = def
+			} else {
+				if containsProvider(tagList(field, "required"), provider) {
+					errs = append(errs, fmt.Errorf("%s is required for provider '%s'", key, provider))
+				}
+				continue
+			}
+		}
+		if err := setProperty(v.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s %w", key, err))
+		}
+	}
+	return errs
+}
+
+// setProperty converts a value decoded from YAML to the type of field and stores it
+func setProperty(field reflect.Value, raw interface{}) error {
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := scalarString(raw)
+		if !ok {
+			return fmt.Errorf("must be a string, not %s", describeValue(raw))
+		}
+		field.SetString(s)
+	case reflect.Int, reflect.Int64:
+		switch n := raw.(type) {
+		case int:
+			field.SetInt(int64(n))
+		case int64:
+			field.SetInt(n)
+		case float64:
+			if n != float64(int64(n)) {
+				return fmt.Errorf("must be an integer (got %v)", n)
+			}
+			field.SetInt(int64(n))
+		case string:
+			i, err := strconv.Atoi(strings.TrimSpace(n))
+			if err != nil {
+				return fmt.Errorf("must be an integer (got '%s')", n)
+			}
+			field.SetInt(int64(i))
+		default:
+			return fmt.Errorf("must be an integer, not %s", describeValue(raw))
+		}
+	case reflect.Bool:
+		switch b := raw.(type) {
+		case bool:
+			field.SetBool(b)
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(b))
+			if err != nil {
+				return fmt.Errorf("must be true or false (got '%s')", b)
+			}
+			field.SetBool(parsed)
+		default:
+			return fmt.Errorf("must be true or false, not %s", describeValue(raw))
+		}
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("has unsupported type %s", field.Type())
+		}
+		items := []string{}
+		switch list := raw.(type) {
+		case []interface{}:
+			for _, item := range list {
+				s, ok := scalarString(item)
+				if !ok {
+					return fmt.Errorf("must be a list of strings, not a list of %s", describeValue(item))
+				}
+				if s != "" {
+					items = append(items, s)
+				}
+			}
+		case []string:
+			items = append(items, list...)
+		default:
+			s, ok := scalarString(raw)
+			if !ok {
+				return fmt.Errorf("must be a list or a comma-separated string, not %s", describeValue(raw))
+			}
+			for _, item := range strings.Split(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("has unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// scalarString returns a scalar YAML value as a trimmed string; false for lists and mappings
+func scalarString(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case nil:
+		return "", true
+	case string:
+		return strings.TrimSpace(v), true
+	case int, int64, float64, bool:
+		return fmt.Sprintf("%v", v), true
+	}
+	return "", false
+}
+
+// describeValue names the YAML kind of a value for error messages
+func describeValue(raw interface{}) string {
+	switch raw.(type) {
+	case []interface{}, []string:
+		return "a list"
+	case map[string]interface{}:
+		return "a mapping"
+	case bool:
+		return "a boolean"
+	case int, int64, float64:
+		return "a number"
+	}
+	return fmt.Sprintf("a %T", raw)
+}
+
+// isEmptyProperty reports whether a value counts as unset: missing, null, blank or an empty list
+func isEmptyProperty(raw interface{}) bool {
+	switch v := raw.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// propertyKey returns the environment.yaml key of a config struct field, or "" if it has none
+func propertyKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if key == "-" {
+		return ""
+	}
+	return key
+}
+
+// tagList returns the comma-separated values of a struct tag
+func tagList(field reflect.StructField, name string) []string {
+	var values []string
+	for _, v := range strings.Split(field.Tag.Get(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// DecodedServiceProperties returns a copy of the properties of the named service type with
+// the keys of its config structs decoded (see decodedProperties)
+func (ic InstanceConfig) DecodedServiceProperties(serviceType string) map[string]interface{} {
+	return decodedProperties(ic.ServiceProperties(serviceType), ic.Properties.Provider, serviceConfigTypes(serviceType)...)
+}
+
+// DecodedRules returns a copy of the instance's rules with the keys of RulesConfig decoded
+// (see decodedProperties)
+func (ic InstanceConfig) DecodedRules() map[string]interface{} {
+	return decodedProperties(ic.Rules, ic.Properties.Provider, reflect.TypeOf(RulesConfig{}))
+}
+
+// decodedProperties returns a copy of props in which each key of the config structs holds its
+// decoded value, so that features and policies see the same values as the services: numbers
+// and booleans written as strings are converted, comma-separated lists are split, and unset
+// keys the provider reads take their default. Lists are []interface{}, as YAML lists decode.
+// Other keys, and values that cannot be decoded (Validate reports them), are copied as is.
+func decodedProperties(props map[string]interface{}, provider string, configTypes ...reflect.Type) map[string]interface{} {
+	decoded := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		decoded[k] = v
+	}
+	for _, t := range configTypes {
+		schema := schemaOf(t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key := propertyKey(field)
+			if key == "" {
+				continue
+			}
+			raw := props[key]
+			if isEmptyProperty(raw) {
+				def, ok := field.Tag.Lookup("default")
+				if !ok || !schema[key].AppliesTo(provider) {
+					continue
+				}
+				raw = def
+			}
+			value := reflect.New(field.Type).Elem()
+			if err := setProperty(value, raw); err != nil {
+				continue
+			}
+			decoded[key] = value.Interface()
+			if list, ok := decoded[key].([]string); ok {
+				items := make([]interface{}, len(list))
+				for j, item := range list {
+					items[j] = item
+				}
+				decoded[key] = items
+			}
+		}
+	}
+	return decoded
+}
+
+// Validate decodes the instance's properties, rules and service blocks, or only the block of
+// serviceType if it is not empty, and reports every missing required key and invalid value
+func (ic InstanceConfig) Validate(serviceType string) error {
+	provider := ic.Properties.Provider
+	var errs []error
+
+	properties := reflect.ValueOf(ic.Properties)
+	for i := 0; i < properties.NumField(); i++ {
+		field := properties.Type().Field(i)
+		if containsProvider(tagList(field, "required"), provider) && strings.TrimSpace(properties.Field(i).String()) == "" {
+			errs = append(errs, fmt.Errorf("properties.%s is required for provider '%s'", propertyKey(field), provider))
+		}
+	}
+
+	for _, err := range decodeProperties(ic.Rules, &RulesConfig{}, provider) {
+		errs = append(errs, fmt.Errorf("rules.%w", err))
+	}
+
+	for _, svc := range ic.Services {
+		if serviceType != "" && svc.Type != serviceType {
+			continue
+		}
+		for _, t := range serviceConfigTypes(svc.Type) {
+			for _, err := range decodeProperties(svc.Properties, reflect.New(t).Interface(), provider) {
+				errs = append(errs, fmt.Errorf("services.%s.%w", svc.Type, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("instance %s has invalid configuration:\n%w", ic.ID, errors.Join(errs...))
+	}
+	return nil
+}
@@ -21,11 +21,11 @@ type TestParams struct {
 
 // CloudParams holds the cloud provider and instance-level configuration
 type CloudParams struct {
-	Provider            string `yaml:"provider,omitempty"`
-	Region              string `yaml:"region,omitempty"`
-	AzureResourceGroup  string `yaml:"azure-resource-group,omitempty"`
-	AzureSubscriptionID string `yaml:"azure-subscription-id,omitempty"`
-	GcpProjectId        string `yaml:"gcp-project-id,omitempty"`
+	Provider            string `yaml:"provider,omitempty" required:"aws,azure,gcp,local"`
+	Region              string `yaml:"region,omitempty" required:"aws,azure,gcp,local"`
+	AzureResourceGroup  string `yaml:"azure-resource-group,omitempty" providers:"azure" required:"azure"`
+	AzureSubscriptionID string `yaml:"azure-subscription-id,omitempty" providers:"azure" required:"azure"`
+	GcpProjectId        string `yaml:"gcp-project-id,omitempty" providers:"gcp" required:"gcp"`
 }
 
 // Attachment holds a named piece of content attached to a test step
@@ -40,15 +40,3 @@ type AttachmentProvider interface {
 	GetAttachments() []Attachment
 	ClearAttachments()
 }
-
-// ServiceTypes contains all known service types.
-// These are also used as tags on tests relevant to that service.
-var ServiceTypes = []string{
-	"object-storage",
-	"block-storage",
-	"relational-database",
-	"iam",
-	"load-balancer",
-	"security-group",
-	"vpc",
-}